@@ -0,0 +1,40 @@
+package oas
+
+import (
+	"encoding/json"
+)
+
+func AnyValue(v interface{}) Any {
+	return Any{
+		Value:   v,
+		Present: true,
+	}
+}
+
+// Any holds a json value with its presence,
+// so that null, false, 0 and "" are kept instead of being dropped as empty
+type Any struct {
+	Value   interface{}
+	Present bool
+}
+
+func (a Any) IsZero() bool {
+	return !a.Present
+}
+
+func (a Any) MarshalJSON() ([]byte, error) {
+	if !a.Present {
+		return []byte("null"), nil
+	}
+	return json.Marshal(a.Value)
+}
+
+func (a *Any) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	a.Value = v
+	a.Present = true
+	return nil
+}
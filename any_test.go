@@ -0,0 +1,259 @@
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/go-courier/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAny(t *testing.T) {
+	g := NewCaseGroup("Any")
+
+	g.It("schema with zero default and example", `{"type":"boolean","default":false,"example":false}`, func() *Schema {
+		s := Boolean()
+		s.Default = AnyValue(false)
+		s.Example = AnyValue(false)
+		return s
+	}())
+	g.It("schema with null default", `{"type":"string","default":null,"nullable":true}`, func() *Schema {
+		s := String()
+		s.Nullable = true
+		s.Default = AnyValue(nil)
+		return s
+	}())
+	g.It("example with zero value", `{"value":0}`, func() *Example {
+		e := NewExample()
+		e.Value = AnyValue(float64(0))
+		return e
+	}())
+	g.It("parameter with explode false", `{"name":"ids","in":"query","style":"form","explode":false,"schema":{"type":"array","items":{"type":"string"}},"example":""}`, func() *Parameter {
+		p := QueryParameter("ids", ItemsOf(String()), false).WithStyle(ParameterStyleForm, false)
+		p.Example = AnyValue("")
+		return p
+	}())
+	g.It("additional properties false", `{"type":"object","additionalProperties":false}`, &Schema{
+		SchemaObject: SchemaObject{
+			Type:                 TypeObject,
+			AdditionalProperties: &SchemaOrBool{},
+		},
+	})
+	g.It("additional properties true", `{"type":"object","additionalProperties":true}`, &Schema{
+		SchemaObject: SchemaObject{
+			Type:                 TypeObject,
+			AdditionalProperties: &SchemaOrBool{Allows: true},
+		},
+	})
+
+	g.Run(t)
+
+	t.Run("explode default", func(t *testing.T) {
+		assert.True(t, QueryParameter("ids", String(), false).ExplodeOrDefault())
+		assert.False(t, PathParameter("id", String()).ExplodeOrDefault())
+		assert.False(t, QueryParameter("ids", String(), false).WithStyle(ParameterStyleForm, false).ExplodeOrDefault())
+		assert.True(t, NewEncoding().ExplodeOrDefault())
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		g := &docGenerator{r: rand.New(rand.NewSource(seed))}
+		openapi := g.OpenAPI()
+
+		data, err := json.Marshal(openapi)
+		assert.NoError(t, err)
+
+		decoded := &OpenAPI{}
+		if !assert.NoError(t, UnmarshalStrict(data, decoded), fmt.Sprintf("seed %d", seed)) {
+			continue
+		}
+		assert.Equal(t, openapi, decoded, fmt.Sprintf("seed %d", seed))
+
+		data2, err := json.Marshal(decoded)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(data), string(data2), fmt.Sprintf("seed %d", seed))
+	}
+}
+
+// docGenerator generates random documents for round-trip checking,
+// which focus on values could be lost, like zero values, null and tri-state bools
+type docGenerator struct {
+	r *rand.Rand
+}
+
+func (g *docGenerator) oneIn(n int) bool {
+	return g.r.Intn(n) == 0
+}
+
+func (g *docGenerator) name() string {
+	return fmt.Sprintf("n%d", g.r.Intn(1000))
+}
+
+func (g *docGenerator) Value(depth int) interface{} {
+	n := 7
+	if depth > 2 {
+		n = 5
+	}
+	switch g.r.Intn(n) {
+	case 0:
+		return nil
+	case 1:
+		return g.oneIn(2)
+	case 2:
+		return float64(g.r.Intn(3))
+	case 3:
+		return []string{"", "a", "b"}[g.r.Intn(3)]
+	case 4:
+		return g.r.Float64()
+	case 5:
+		list := make([]interface{}, g.r.Intn(3))
+		for i := range list {
+			list[i] = g.Value(depth + 1)
+		}
+		return list
+	}
+	m := map[string]interface{}{}
+	for i := g.r.Intn(3); i > 0; i-- {
+		m[g.name()] = g.Value(depth + 1)
+	}
+	return m
+}
+
+func (g *docGenerator) Any() Any {
+	if g.oneIn(3) {
+		return Any{}
+	}
+	return AnyValue(g.Value(0))
+}
+
+func (g *docGenerator) BoolPtr() *bool {
+	if g.oneIn(3) {
+		return nil
+	}
+	return ptr.Bool(g.oneIn(2))
+}
+
+func (g *docGenerator) Extensions() SpecExtensions {
+	e := SpecExtensions{}
+	if g.oneIn(3) {
+		e.AddExtension("x-"+g.name(), g.Value(0))
+	}
+	return e
+}
+
+func (g *docGenerator) Schema(depth int) *Schema {
+	if depth > 0 && g.oneIn(5) {
+		return RefSchemaByRefer(NewComponentRefer("schemas", g.name()))
+	}
+
+	s := []func() *Schema{String, Integer, Double, Boolean, DateTime}[g.r.Intn(5)]()
+
+	if depth < 3 {
+		switch g.r.Intn(4) {
+		case 0:
+			s = ItemsOf(g.Schema(depth + 1))
+		case 1:
+			s = ObjectOf(Props{g.name(): g.Schema(depth + 1)})
+			switch g.r.Intn(4) {
+			case 0:
+				s.AdditionalProperties = &SchemaOrBool{Allows: g.oneIn(2)}
+			case 1:
+				s.AdditionalProperties = &SchemaOrBool{Allows: true, Schema: g.Schema(depth + 1)}
+			}
+		}
+	}
+
+	s.Default = g.Any()
+	s.Example = g.Any()
+	s.Nullable = g.oneIn(2)
+	s.SpecExtensions = g.Extensions()
+	return s
+}
+
+func (g *docGenerator) Example() *Example {
+	e := NewExample()
+	e.Summary = g.name()
+	e.Value = g.Any()
+	return e
+}
+
+func (g *docGenerator) Parameter() *Parameter {
+	p := []func(string, *Schema, bool) *Parameter{QueryParameter, HeaderParameter, CookieParameter}[g.r.Intn(3)](g.name(), g.Schema(0), g.oneIn(2))
+	if g.oneIn(2) {
+		p.Style = ParameterStyleForm
+	}
+	p.Explode = g.BoolPtr()
+	p.Example = g.Any()
+	if g.oneIn(2) {
+		p.AddExample(g.name(), g.Example())
+	}
+	p.SpecExtensions = g.Extensions()
+	return p
+}
+
+func (g *docGenerator) MediaType() *MediaType {
+	mt := NewMediaTypeWithSchema(g.Schema(0))
+	mt.Example = g.Any()
+	if g.oneIn(2) {
+		e := NewEncoding()
+		e.Style = ParameterStyleForm
+		e.Explode = g.BoolPtr()
+		mt.AddEncoding(g.name(), e)
+	}
+	return mt
+}
+
+func (g *docGenerator) Operation() *Operation {
+	op := NewOperation(g.name())
+	for i := g.r.Intn(3); i > 0; i-- {
+		op.AddParameter(g.Parameter())
+	}
+	if g.oneIn(2) {
+		rb := NewRequestBody("", g.oneIn(2))
+		rb.AddContent("application/json", g.MediaType())
+		op.SetRequestBody(rb)
+	}
+
+	resp := NewResponse(g.name())
+	resp.AddContent("application/json", g.MediaType())
+	op.AddResponse(200+g.r.Intn(5), resp)
+	if g.oneIn(2) {
+		op.AddResponseRange(4, NewResponse(g.name()))
+	}
+	if g.oneIn(2) {
+		op.SetDefaultResponse(NewResponse(g.name()))
+	}
+	switch g.r.Intn(4) {
+	case 0:
+		op.DisableSecurity()
+	case 1:
+		op.AddSecurityRequirement(&SecurityRequirement{"token": []string{}})
+		op.AddOptionalSecurity()
+	}
+	op.SpecExtensions = g.Extensions()
+	return op
+}
+
+func (g *docGenerator) OpenAPI() *OpenAPI {
+	openapi := NewOpenAPI()
+	openapi.Title = g.name()
+	openapi.Version = "1.0.0"
+
+	for i := g.r.Intn(4); i >= 0; i-- {
+		openapi.AddOperation([]HttpMethod{GET, POST, PUT}[g.r.Intn(3)], "/"+g.name(), g.Operation())
+	}
+	for i := g.r.Intn(3); i > 0; i-- {
+		openapi.AddSchema(g.name(), g.Schema(0))
+	}
+	for i := g.r.Intn(2); i > 0; i-- {
+		openapi.AddExample(g.name(), g.Example())
+	}
+	if g.oneIn(2) {
+		openapi.AddSecurityRequirement(&SecurityRequirement{"token": []string{}})
+	}
+	openapi.SpecExtensions = g.Extensions()
+	return openapi
+}
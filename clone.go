@@ -0,0 +1,165 @@
+package oas
+
+import (
+	"reflect"
+)
+
+// deepClone copies v with all maps, slices, pointers and interface values,
+// shared or circular pointers and maps in v are shared or circular in the copy too.
+func deepClone[T any](v T) T {
+	c := &cloner{seen: map[cloneKey]reflect.Value{}}
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	dst.Set(c.clone(src))
+	return dst.Interface().(T)
+}
+
+type cloneKey struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+type cloner struct {
+	seen map[cloneKey]reflect.Value
+}
+
+func (c *cloner) clone(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{typ: v.Type(), ptr: v.Pointer()}
+		if x, ok := c.seen[key]; ok {
+			return x
+		}
+		x := reflect.New(v.Type().Elem())
+		c.seen[key] = x
+		x.Elem().Set(c.clone(v.Elem()))
+		return x
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		x := reflect.New(v.Type()).Elem()
+		x.Set(c.clone(v.Elem()))
+		return x
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{typ: v.Type(), ptr: v.Pointer()}
+		if x, ok := c.seen[key]; ok {
+			return x
+		}
+		x := reflect.MakeMapWithSize(v.Type(), v.Len())
+		c.seen[key] = x
+		iter := v.MapRange()
+		for iter.Next() {
+			x.SetMapIndex(c.clone(iter.Key()), c.clone(iter.Value()))
+		}
+		return x
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{typ: v.Type(), ptr: v.Pointer(), len: v.Len()}
+		if x, ok := c.seen[key]; ok {
+			return x
+		}
+		x := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		c.seen[key] = x
+		for i := 0; i < v.Len(); i++ {
+			x.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return x
+	case reflect.Array:
+		x := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			x.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return x
+	case reflect.Struct:
+		x := reflect.New(v.Type()).Elem()
+		// unexported fields are copied as they are
+		x.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := x.Field(i); f.CanSet() {
+				f.Set(c.clone(v.Field(i)))
+			}
+		}
+		return x
+	}
+	return v
+}
+
+func (o *OpenAPI) Clone() *OpenAPI { return deepClone(o) }
+
+func (i *Info) Clone() *Info { return deepClone(i) }
+
+func (c *Contact) Clone() *Contact { return deepClone(c) }
+
+func (l *License) Clone() *License { return deepClone(l) }
+
+func (d *ExternalDoc) Clone() *ExternalDoc { return deepClone(d) }
+
+func (s *Server) Clone() *Server { return deepClone(s) }
+
+func (v *ServerVariable) Clone() *ServerVariable { return deepClone(v) }
+
+func (t *Tag) Clone() *Tag { return deepClone(t) }
+
+func (c *Components) Clone() *Components { return deepClone(c) }
+
+func (p *Paths) Clone() *Paths { return deepClone(p) }
+
+func (i *PathItem) Clone() *PathItem { return deepClone(i) }
+
+func (op *Operation) Clone() *Operation { return deepClone(op) }
+
+func (c *Callback) Clone() *Callback { return deepClone(c) }
+
+func (p *Parameter) Clone() *Parameter { return deepClone(p) }
+
+func (h *Header) Clone() *Header { return deepClone(h) }
+
+func (e *Example) Clone() *Example { return deepClone(e) }
+
+func (rb *RequestBody) Clone() *RequestBody { return deepClone(rb) }
+
+func (r *Responses) Clone() *Responses { return deepClone(r) }
+
+func (r *Response) Clone() *Response { return deepClone(r) }
+
+func (l *Link) Clone() *Link { return deepClone(l) }
+
+func (mt *MediaType) Clone() *MediaType { return deepClone(mt) }
+
+func (e *Encoding) Clone() *Encoding { return deepClone(e) }
+
+func (s *Schema) Clone() *Schema { return deepClone(s) }
+
+func (v *SchemaValidation) Clone() *SchemaValidation { return deepClone(v) }
+
+func (s *SchemaOrBool) Clone() *SchemaOrBool { return deepClone(s) }
+
+func (d *Discriminator) Clone() *Discriminator { return deepClone(d) }
+
+func (x *XML) Clone() *XML { return deepClone(x) }
+
+func (ss *SecurityScheme) Clone() *SecurityScheme { return deepClone(ss) }
+
+func (f *OAuthFlows) Clone() *OAuthFlows { return deepClone(f) }
+
+func (f *OAuthFlow) Clone() *OAuthFlow { return deepClone(f) }
+
+func (r SecurityRequirement) Clone() SecurityRequirement { return deepClone(r) }
+
+func (ref *ComponentRefer) Clone() *ComponentRefer { return deepClone(ref) }
+
+func (ref *StringRefer) Clone() *StringRefer { return deepClone(ref) }
+
+func (v SpecExtensions) Clone() SpecExtensions { return deepClone(v) }
+
+func (a Any) Clone() Any { return deepClone(a) }
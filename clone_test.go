@@ -0,0 +1,104 @@
+package oas
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	t.Run("schema", func(t *testing.T) {
+		s := ObjectOf(Props{"name": String()}, "name")
+		s.AddExtension("x-meta", map[string]interface{}{"tags": []interface{}{"a"}})
+		s.Default = AnyValue(map[string]interface{}{"name": "x"})
+
+		c := s.Clone()
+		assert.Equal(t, s, c)
+
+		c.Properties["name"].Format = "email"
+		c.Required[0] = "id"
+		c.Extensions["x-meta"].(map[string]interface{})["tags"].([]interface{})[0] = "b"
+		c.Default.Value.(map[string]interface{})["name"] = "y"
+
+		assert.Equal(t, "", s.Properties["name"].Format)
+		assert.Equal(t, []string{"name"}, s.Required)
+		assert.Equal(t, map[string]interface{}{"tags": []interface{}{"a"}}, s.Extensions["x-meta"])
+		assert.Equal(t, map[string]interface{}{"name": "x"}, s.Default.Value)
+	})
+
+	t.Run("refer", func(t *testing.T) {
+		s := RefSchema("#/components/schemas/Pet")
+		c := s.Clone()
+		assert.Equal(t, "#/components/schemas/Pet", c.Refer.RefString())
+
+		c.Refer.(*StringRefer).Ref = "#/components/schemas/Cat"
+		assert.Equal(t, "#/components/schemas/Pet", s.Refer.RefString())
+	})
+
+	t.Run("shared and cyclic", func(t *testing.T) {
+		shared := String()
+		node := ObjectOf(Props{"a": shared, "b": shared})
+		node.SetProperty("children", ItemsOf(node), false)
+
+		c := node.Clone()
+		assert.NotSame(t, node, c)
+		assert.Same(t, c.Properties["a"], c.Properties["b"])
+		assert.NotSame(t, shared, c.Properties["a"])
+		assert.Same(t, c, c.Properties["children"].Items)
+	})
+
+	t.Run("openapi", func(t *testing.T) {
+		openapi := NewOpenAPI()
+		openapi.AddSchema("Pet", ObjectOf(Props{"name": String()}))
+		op := NewOperation("listPets").WithTags("pets")
+		resp := NewResponse("ok")
+		resp.AddContent("application/json", NewMediaTypeWithSchema(ItemsOf(openapi.RefSchema("Pet"))))
+		op.AddResponse(http.StatusOK, resp)
+		openapi.AddOperation(GET, "/pets", op)
+
+		c := openapi.Clone()
+		assert.Equal(t, openapi, c)
+
+		c.Schemas["Pet"].Title = "Pet"
+		c.Paths.Paths["/pets"].Operations.Operations[GET].Tags[0] = "animals"
+		assert.Equal(t, "", openapi.Schemas["Pet"].Title)
+		assert.Equal(t, []string{"pets"}, op.Tags)
+	})
+}
+
+func TestBuildersImmutable(t *testing.T) {
+	s := ObjectOf(Props{"name": String()}, "name")
+	s.AddExtension("x-a", 1)
+
+	d := s.WithDesc("desc")
+	d.Properties["id"] = Long()
+	d.Required = append(d.Required[:0], "id")
+	d.AddExtension("x-b", 2)
+
+	assert.Equal(t, "", s.Description)
+	assert.Equal(t, []string{"name"}, slices.Sorted(maps.Keys(s.Properties)))
+	assert.Equal(t, []string{"name"}, s.Required)
+	assert.Equal(t, map[string]interface{}{"x-a": 1}, s.Extensions)
+
+	validation := &SchemaValidation{Enum: []interface{}{"a"}}
+	e := String().WithValidation(validation)
+	e.Enum[0] = "b"
+	assert.Equal(t, []interface{}{"a"}, validation.Enum)
+
+	op := NewOperation("a").WithTags("x", "y")
+	op.Tags = op.Tags[:1]
+	op2 := op.WithTags("z")
+	op3 := op.WithTags("w")
+	assert.Equal(t, []string{"x", "z"}, op2.Tags)
+	assert.Equal(t, []string{"x", "w"}, op3.Tags)
+
+	p := QueryParameter("q", String(), false)
+	p2 := p.WithDesc("query").WithStyle(ParameterStyleForm, true)
+	p2.Schema.Format = "email"
+	assert.Equal(t, "", p.Description)
+	assert.Equal(t, "", p.Schema.Format)
+	assert.Nil(t, p.Explode)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("bundle", "inline external refs into components of a single document", runBundle)
+}
+
+func runBundle(e *env, args []string) error {
+	fs := newFlagSet(e, "bundle", "[flags] <file>")
+	o := &output{}
+	o.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	openapi, err := bundle(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return o.write(e, openapi)
+}
+
+// bundle bundles the document, files referred loaded by loadTree, since they could be fragments of documents
+func bundle(e *env, path string) (*oas.OpenAPI, error) {
+	b := &bundler{load: func(file string) (interface{}, error) {
+		return loadTree(e, file)
+	}}
+	return b.bundle(path)
+}
+
+// bundler moves values of external refs into components,
+// named by the component name of the pointer like /components/schemas/Pet,
+// or the last token of the pointer, or the file name, with numeric suffix when used.
+// Refs to path items are inlined, since path items could not be components.
+type bundler struct {
+	load func(file string) (interface{}, error)
+
+	root    string
+	openapi *oas.OpenAPI
+	docs    map[string]interface{}
+	// refs maps file#pointer to the ref of the component bundled
+	refs  map[string]oas.Refer
+	names map[string]bool
+	err   error
+}
+
+// bundle returns the bundled document of the file, files of refs are relative to the file referring.
+// Remote refs are not supported.
+func (b *bundler) bundle(file string) (*oas.OpenAPI, error) {
+	b.root, b.docs, b.refs, b.names = filepath.Clean(file), map[string]interface{}{}, map[string]oas.Refer{}, map[string]bool{}
+
+	tree, err := b.doc(b.root)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.inlinePathItems(tree); err != nil {
+		return nil, err
+	}
+
+	b.openapi = &oas.OpenAPI{}
+	if err := decodeValue(tree, b.openapi); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	for group := range componentMaps(&b.openapi.ComponentsObject) {
+		for _, name := range componentNames(&b.openapi.ComponentsObject, group) {
+			b.names[group+"/"+name] = true
+		}
+	}
+
+	visitRefs(reflect.ValueOf(b.openapi), b.visitor(b.root))
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.openapi, nil
+}
+
+func (b *bundler) doc(file string) (interface{}, error) {
+	if doc, ok := b.docs[file]; ok {
+		return doc, nil
+	}
+	doc, err := b.load(file)
+	if err != nil {
+		return nil, err
+	}
+	b.docs[file] = doc
+	return doc, nil
+}
+
+// resolve returns the file and pointer of the ref, relative to the file referring
+func (b *bundler) resolve(ref string, file string) (string, string, error) {
+	target, pointer, _ := strings.Cut(ref, "#")
+	if target == "" {
+		return file, pointer, nil
+	}
+	if strings.Contains(target, "://") {
+		return "", "", fmt.Errorf("remote ref %s not supported", ref)
+	}
+	return filepath.Join(filepath.Dir(file), target), pointer, nil
+}
+
+func (b *bundler) value(file string, pointer string) (interface{}, error) {
+	doc, err := b.doc(file)
+	if err != nil {
+		return nil, err
+	}
+	v, err := oas.ResolveJSONPointer(doc, pointer)
+	if err != nil {
+		return nil, fmt.Errorf("%s#%s: %w", file, pointer, err)
+	}
+	return v, nil
+}
+
+// inlinePathItems inlines refs of path items on json values, since PathItem holds no Reference,
+// refs of the values inlined rewritten to be relative to the root file.
+func (b *bundler) inlinePathItems(tree interface{}) error {
+	paths := object(tree, "paths")
+
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[path].(map[string]interface{})
+		for seen := map[string]bool{}; ; {
+			ref, ok := item["$ref"].(string)
+			if !ok {
+				break
+			}
+			file, pointer, err := b.resolve(ref, b.root)
+			if err != nil {
+				return err
+			}
+			if seen[file+"#"+pointer] {
+				return fmt.Errorf("circular ref %s of path item %s", ref, path)
+			}
+			seen[file+"#"+pointer] = true
+
+			v, err := b.value(file, pointer)
+			if err != nil {
+				return err
+			}
+			copied, _ := jsonValue(v).(map[string]interface{})
+			b.rebase(copied, file)
+			item = copied
+		}
+		if item != nil {
+			paths[path] = item
+		}
+	}
+	return nil
+}
+
+// rebase rewrites refs of the value from the file into ones relative to the root file
+func (b *bundler) rebase(v interface{}, file string) {
+	walkRefs(v, "", func(obj map[string]interface{}, ref string, pointer string) {
+		target, p, err := b.resolve(ref, file)
+		if err != nil {
+			return
+		}
+		if target == b.root {
+			obj["$ref"] = "#" + p
+		} else if rel, err := filepath.Rel(filepath.Dir(b.root), target); err == nil {
+			obj["$ref"] = filepath.ToSlash(rel) + "#" + p
+		}
+	})
+}
+
+// visitor moves values of refs of the file into components
+func (b *bundler) visitor(file string) refFunc {
+	return func(group string, ref *oas.Reference, _ reflect.Value) {
+		if b.err != nil {
+			return
+		}
+
+		r := ref.Refer.RefString()
+		if strings.HasPrefix(r, "#") && file == b.root {
+			return
+		}
+
+		target, pointer, err := b.resolve(r, file)
+		if err != nil {
+			b.err = err
+			return
+		}
+
+		key := target + "#" + pointer
+		if local, ok := b.refs[key]; ok {
+			ref.Refer = local
+			return
+		}
+
+		v, err := b.value(target, pointer)
+		if err != nil {
+			b.err = err
+			return
+		}
+		c := newComponent(group)
+		if err := decodeValue(v, c.Interface()); err != nil {
+			b.err = fmt.Errorf("%s: %w", r, err)
+			return
+		}
+
+		name := b.uniqueName(group, componentName(target, pointer))
+		b.refs[key] = oas.NewComponentRefer(group, name)
+		setComponent(&b.openapi.ComponentsObject, group, name, c)
+		ref.Refer = b.refs[key]
+
+		visitRefs(c, b.visitor(target))
+	}
+}
+
+func (b *bundler) uniqueName(group string, name string) string {
+	n := name
+	for i := 2; b.names[group+"/"+n]; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	b.names[group+"/"+n] = true
+	return n
+}
+
+// componentName returns the component name of the pointer, or the last token of it, or the file name
+func componentName(file string, pointer string) string {
+	if r := oas.ParseComponentRefer("#" + pointer); r != nil {
+		return r.ID
+	}
+	if tokens, err := oas.ParseJSONPointer(pointer); err == nil && len(tokens) > 0 {
+		return tokens[len(tokens)-1]
+	}
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle(t *testing.T) {
+	code, stdout, _ := runCommand("", "bundle", "testdata/bundle/root.yaml")
+	assert.Equal(t, ExitOK, code)
+
+	var tree interface{}
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &tree))
+
+	assert.Equal(t, "getUser", object(tree, "paths", "/users/{id}", "get")["operationId"])
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/parameters/Id"}, object(tree, "paths", "/users/{id}", "get")["parameters"].([]interface{})[0])
+	assert.Equal(t, "#/components/schemas/user", object(tree, "paths", "/users/{id}", "get", "responses", "200", "content", "application/json", "schema")["$ref"])
+	assert.Equal(t, "#/components/schemas/user", object(tree, "components", "schemas", "user", "properties", "friends", "items")["$ref"])
+	assert.NotNil(t, object(tree, "components", "schemas", "Node"))
+
+	walkRefs(tree, "", func(obj map[string]interface{}, ref string, pointer string) {
+		assert.Equal(t, "#", ref[:1], pointer)
+	})
+}
+
+func TestDeref(t *testing.T) {
+	code, stdout, stderr := runCommand("", "deref", "-drop-components", "testdata/bundle/root.yaml")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stderr, "circular ref #/components/schemas/user kept")
+
+	var tree interface{}
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &tree))
+
+	assert.Equal(t, "id", object(tree, "paths", "/users/{id}", "get")["parameters"].([]interface{})[0].(map[string]interface{})["name"])
+	assert.Nil(t, object(tree, "components", "parameters"))
+	assert.Equal(t, "#/components/schemas/user", object(tree, "components", "schemas", "user", "properties", "friends", "items")["$ref"])
+
+	schema := object(tree, "paths", "/users/{id}", "get", "responses", "200", "content", "application/json", "schema")
+	assert.Equal(t, "#/components/schemas/user", object(schema, "properties", "friends", "items")["$ref"])
+
+	code, stdout, _ = runCommand("", "deref", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+	assert.NotContains(t, stdout, "$ref")
+}
+
+func TestBundler(t *testing.T) {
+	files := map[string]string{
+		"api/root.json": `{
+			"openapi": "3.0.3",
+			"info": {"title": "Bundle", "version": "1.0.0"},
+			"paths": {"/users/{id}": {"$ref": "paths/users.json#/user"}},
+			"components": {"schemas": {"user": {"type": "string"}}}
+		}`,
+		"api/paths/users.json": `{"user": {"get": {
+			"parameters": [{"$ref": "../common.json#/components/parameters/Id"}],
+			"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "../user.json"}}}}}
+		}}}`,
+		"api/common.json": `{"components": {"parameters": {"Id": {"name": "id", "in": "path", "required": true, "schema": {"$ref": "#/definitions/Id"}}}}, "definitions": {"Id": {"type": "string"}}}`,
+		"api/user.json":   `{"type": "object", "properties": {"friends": {"type": "array", "items": {"$ref": "./user.json"}}}}`,
+	}
+
+	b := &bundler{load: func(file string) (interface{}, error) {
+		data, ok := files[filepath.ToSlash(file)]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		var v interface{}
+		err := json.Unmarshal([]byte(data), &v)
+		return v, err
+	}}
+
+	openapi, err := b.bundle("api/root.json")
+	assert.NoError(t, err)
+
+	op := openapi.Paths.Paths["/users/{id}"].Operations.Operations[oas.GET]
+	assert.NotNil(t, op)
+	assert.Equal(t, "#/components/parameters/Id", op.Parameters[0].Refer.RefString())
+	assert.Equal(t, "#/components/schemas/Id", openapi.Parameters["Id"].Schema.Refer.RefString())
+	assert.Equal(t, oas.TypeString, openapi.Schemas["Id"].Type)
+
+	// user used already
+	schema := op.Responses.Responses[200].Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/user2", schema.Refer.RefString())
+	assert.Equal(t, "#/components/schemas/user2", openapi.Schemas["user2"].Properties["friends"].Items.Refer.RefString())
+	assert.Equal(t, oas.TypeString, openapi.Schemas["user"].Type)
+
+	t.Run("missing file", func(t *testing.T) {
+		files["api/broken.json"] = `{"openapi": "3.0.3", "paths": {}, "components": {"schemas": {"Pet": {"$ref": "pet.json"}}}}`
+		_, err := b.bundle("api/broken.json")
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	})
+
+	t.Run("remote ref", func(t *testing.T) {
+		files["api/remote.json"] = `{"openapi": "3.0.3", "paths": {}, "components": {"schemas": {"Pet": {"$ref": "https://example.com/pet.json"}}}}`
+		_, err := b.bundle("api/remote.json")
+		assert.Error(t, err)
+	})
+}
+
+func TestDereference(t *testing.T) {
+	openapi := oas.NewOpenAPI()
+	openapi.AddSchema("Node", oas.ObjectOf(oas.Props{}))
+	openapi.Schemas["Node"].SetProperty("children", oas.ItemsOf(openapi.RefSchema("Node")), false)
+	openapi.AddSchema("Tree", oas.ObjectOf(oas.Props{"root": openapi.RefSchema("Node")}))
+	openapi.AddParameter("Id", oas.PathParameter("id", oas.String()))
+
+	op := oas.NewOperation("showTree")
+	op.AddParameter(openapi.RefParameter("Id"))
+	op.AddResponse(http.StatusOK, oas.NewResponse("ok").WithSchema("application/json", openapi.RefSchema("Tree")))
+	openapi.AddOperation(oas.GET, "/trees/{id}", op)
+
+	derefed, circular := dereference(openapi)
+	assert.Equal(t, []string{"#/components/schemas/Node"}, circular)
+
+	op = derefed.Paths.Paths["/trees/{id}"].Operations.Operations[oas.GET]
+	assert.Equal(t, "id", op.Parameters[0].Name)
+	assert.Nil(t, op.Parameters[0].Refer)
+
+	tree := op.Responses.Responses[200].Content["application/json"].Schema
+	assert.Nil(t, tree.Refer)
+	node := tree.Properties["root"]
+	assert.Nil(t, node.Refer)
+	assert.Equal(t, "#/components/schemas/Node", node.Properties["children"].Items.Refer.RefString())
+
+	// components expanded but refs to themselves
+	assert.Nil(t, derefed.Schemas["Tree"].Properties["root"].Refer)
+	assert.Equal(t, "#/components/schemas/Node", derefed.Schemas["Node"].Properties["children"].Items.Refer.RefString())
+
+	// source not modified
+	assert.NotNil(t, openapi.Paths.Paths["/trees/{id}"].Operations.Operations[oas.GET].Parameters[0].Refer)
+
+	pruned := derefed.PruneComponents()
+	assert.Len(t, pruned, 2)
+	data, _ := json.Marshal(derefed.Components)
+	assert.JSONEq(t, `{"schemas": {"Node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}}}}}`, string(data))
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("convert", "convert documents between json and yaml, and between swagger 2.0, openapi 3.0 and 3.1", runConvert)
+}
+
+func runConvert(e *env, args []string) error {
+	fs := newFlagSet(e, "convert", "[flags] <file>")
+	o := &output{}
+	o.register(fs)
+	to := fs.String("to", "", "target version 2.0, 3.0 or 3.1, version of the document kept when empty")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	tree, err := loadTree(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c := &converter{warn: func(format string, args ...interface{}) {
+		fmt.Fprintf(e.stderr, "oas convert: "+format+"\n", args...)
+	}}
+
+	converted, err := c.convert(tree, *to)
+	if err != nil {
+		return err
+	}
+
+	return o.write(e, converted)
+}
+
+type converter struct {
+	warn func(format string, args ...interface{})
+}
+
+func versionOf(tree interface{}) string {
+	root := object(tree)
+	if v, ok := root["swagger"].(string); ok && strings.HasPrefix(v, "2.") {
+		return "2.0"
+	}
+	if v, ok := root["openapi"].(string); ok {
+		switch {
+		case strings.HasPrefix(v, "3.0."):
+			return "3.0"
+		case strings.HasPrefix(v, "3.1."):
+			return "3.1"
+		}
+	}
+	return ""
+}
+
+// convert converts the document through the openapi model of oas.
+// swagger 2.0 and openapi 3.1 are not modeled, they are converted from or into the model by json values.
+func (c *converter) convert(tree interface{}, to string) (interface{}, error) {
+	from := versionOf(tree)
+	if from == "" {
+		return nil, fmt.Errorf("unknown document version, swagger 2.0, openapi 3.0.x or 3.1.x expected")
+	}
+	if to == "" || to == from {
+		return tree, nil
+	}
+	if to != "2.0" && to != "3.0" && to != "3.1" {
+		return nil, usagef("unsupported target version %q", to)
+	}
+
+	root := tree.(map[string]interface{})
+
+	openapi := &oas.OpenAPI{}
+	switch from {
+	case "2.0":
+		o, err := c.fromSwagger2(root)
+		if err != nil {
+			return nil, err
+		}
+		openapi = o
+	case "3.1":
+		c.openAPI31To30(root)
+		fallthrough
+	default:
+		if err := decodeValue(root, openapi); err != nil {
+			return nil, err
+		}
+	}
+
+	switch to {
+	case "2.0":
+		return c.toSwagger2(openapi), nil
+	case "3.1":
+		root := jsonObject(openapi)
+		c.openAPI30To31(root)
+		return root, nil
+	}
+	return openapi, nil
+}
+
+// walkSchemas calls fn with each schema of the document, children of schemas before their parents.
+// values of examples, defaults and extensions are not walked.
+func walkSchemas(v interface{}, fn func(schema map[string]interface{})) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for _, k := range slices.Sorted(maps.Keys(x)) {
+			switch {
+			case k == "example" || k == "examples" || k == "default" || strings.HasPrefix(k, "x-"):
+				continue
+			case k == "schema":
+				eachSchema(x[k], fn)
+			case k == "schemas" || k == "definitions":
+				for _, name := range slices.Sorted(maps.Keys(object(x[k]))) {
+					eachSchema(object(x[k])[name], fn)
+				}
+			default:
+				walkSchemas(x[k], fn)
+			}
+		}
+	case []interface{}:
+		for i := range x {
+			walkSchemas(x[i], fn)
+		}
+	}
+}
+
+func eachSchema(v interface{}, fn func(schema map[string]interface{})) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if _, ok := x["$ref"]; ok {
+			return
+		}
+		for _, name := range slices.Sorted(maps.Keys(object(x["properties"]))) {
+			eachSchema(object(x["properties"])[name], fn)
+		}
+		for _, k := range []string{"items", "additionalProperties", "not"} {
+			eachSchema(x[k], fn)
+		}
+		for _, k := range []string{"allOf", "anyOf", "oneOf", "prefixItems"} {
+			if list, ok := x[k].([]interface{}); ok {
+				for i := range list {
+					eachSchema(list[i], fn)
+				}
+			}
+		}
+		fn(x)
+	case []interface{}:
+		// items of swagger 2.0 could be list
+		for i := range x {
+			eachSchema(x[i], fn)
+		}
+	}
+}
+
+// rewriteRefs rewrites refs matched exactly, or by prefixes ends with slash
+func rewriteRefs(v interface{}, prefixes map[string]string) {
+	walkRefs(v, "", func(obj map[string]interface{}, ref string, pointer string) {
+		if to, ok := prefixes[ref]; ok {
+			obj["$ref"] = to
+			return
+		}
+		for from, to := range prefixes {
+			if strings.HasSuffix(from, "/") && strings.HasPrefix(ref, from) {
+				obj["$ref"] = to + strings.TrimPrefix(ref, from)
+				return
+			}
+		}
+	})
+}
+
+func stringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+func anyList(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i := range values {
+		list[i] = values[i]
+	}
+	return list
+}
+
+func moveFields(from map[string]interface{}, to map[string]interface{}, fields []string) {
+	for _, f := range fields {
+		if v, ok := from[f]; ok {
+			to[f] = v
+			delete(from, f)
+		}
+	}
+}
+
+func (c *converter) openAPI30To31(root map[string]interface{}) {
+	root["openapi"] = "3.1.0"
+
+	walkSchemas(root, func(schema map[string]interface{}) {
+		if nullable, _ := schema["nullable"].(bool); nullable {
+			if t, ok := schema["type"].(string); ok {
+				schema["type"] = []interface{}{t, "null"}
+			} else if enum, ok := schema["enum"].([]interface{}); ok {
+				schema["enum"] = append(enum, nil)
+			}
+		}
+		delete(schema, "nullable")
+
+		for _, k := range []string{"Minimum", "Maximum"} {
+			exclusive := "exclusive" + k
+			limit := strings.ToLower(k)
+			if b, ok := schema[exclusive].(bool); ok {
+				if b && schema[limit] != nil {
+					schema[exclusive] = schema[limit]
+					delete(schema, limit)
+				} else {
+					delete(schema, exclusive)
+				}
+			}
+		}
+
+		if example, ok := schema["example"]; ok {
+			schema["examples"] = []interface{}{example}
+			delete(schema, "example")
+		}
+	})
+}
+
+func (c *converter) openAPI31To30(root map[string]interface{}) {
+	root["openapi"] = "3.0.3"
+
+	if webhooks := object(root, "webhooks"); len(webhooks) > 0 {
+		c.warn("webhooks not supported, dropped")
+	}
+	delete(root, "webhooks")
+	delete(root, "jsonSchemaDialect")
+
+	if info := object(root, "info"); info != nil {
+		delete(info, "summary")
+		if license := object(info, "license"); license != nil {
+			delete(license, "identifier")
+		}
+	}
+
+	walkSchemas(root, func(schema map[string]interface{}) {
+		if types, ok := schema["type"].([]interface{}); ok {
+			rest := make([]interface{}, 0, len(types))
+			for _, t := range types {
+				if t == "null" {
+					schema["nullable"] = true
+				} else {
+					rest = append(rest, t)
+				}
+			}
+			switch len(rest) {
+			case 0:
+				delete(schema, "type")
+			case 1:
+				schema["type"] = rest[0]
+			default:
+				c.warn("multiple types %v not supported, %v used", rest, rest[0])
+				schema["type"] = rest[0]
+			}
+		}
+
+		for _, k := range []string{"Minimum", "Maximum"} {
+			exclusive := "exclusive" + k
+			limit := strings.ToLower(k)
+			if v, ok := schema[exclusive].(float64); ok {
+				schema[limit] = v
+				schema[exclusive] = true
+			}
+		}
+
+		if examples, ok := schema["examples"].([]interface{}); ok {
+			if len(examples) > 0 {
+				schema["example"] = examples[0]
+			}
+			delete(schema, "examples")
+		}
+
+		if v, ok := schema["const"]; ok {
+			schema["enum"] = []interface{}{v}
+			delete(schema, "const")
+		}
+
+		for _, k := range []string{"$schema", "$id", "$defs", "$comment", "prefixItems", "contentMediaType", "contentEncoding", "if", "then", "else", "dependentSchemas", "dependentRequired", "unevaluatedProperties", "unevaluatedItems", "patternProperties", "propertyNames", "contains"} {
+			if _, ok := schema[k]; ok {
+				c.warn("%s not supported, dropped", k)
+				delete(schema, k)
+			}
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	t.Run("swagger 2.0 to openapi 3.0", func(t *testing.T) {
+		code, stdout, _ := runCommand("", "convert", "-to", "3.0", "testdata/swagger.json")
+		assert.Equal(t, ExitOK, code)
+
+		code, _, _ = runCommand(stdout, "validate", "-")
+		assert.Equal(t, ExitOK, code)
+
+		openapi := decodeOpenAPI(t, stdout)
+		assert.Equal(t, "https://api.example.com/v1", openapi.Servers[0].URL)
+		assert.Equal(t, "http", string(openapi.SecuritySchemes["basic"].Type))
+		assert.NotNil(t, openapi.SecuritySchemes["oauth"].Flows.AuthorizationCode)
+		assert.True(t, openapi.Schemas["Thing"].Properties["name"].Nullable)
+
+		list := openapi.Paths.Paths["/things"].Operations.Operations[oas.GET]
+		assert.Equal(t, oas.ParameterStyleForm, list.Parameters[0].Style)
+		assert.Equal(t, "#/components/schemas/Thing", list.Responses.Responses[200].Content["application/json"].Schema.Items.Refer.RefString())
+
+		create := openapi.Paths.Paths["/things"].Operations.Operations[oas.POST]
+		assert.True(t, create.RequestBody.Required)
+		assert.NotNil(t, create.Responses.Responses[201].Headers["Location"].Schema)
+
+		upload := openapi.Paths.Paths["/things/{id}/photo"].Operations.Operations[oas.PUT]
+		file := upload.RequestBody.Content["multipart/form-data"].Schema.Properties["file"]
+		assert.Equal(t, "binary", file.Format)
+		assert.Equal(t, "#/components/parameters/Id", openapi.Paths.Paths["/things/{id}/photo"].Parameters[0].Refer.RefString())
+	})
+
+	t.Run("openapi 3.0 to swagger 2.0", func(t *testing.T) {
+		code, stdout, _ := runCommand("", "convert", "-to", "2.0", "testdata/petstore.yaml")
+		assert.Equal(t, ExitOK, code)
+
+		var tree interface{}
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &tree))
+
+		assert.Equal(t, "2.0", object(tree)["swagger"])
+		assert.Equal(t, "petstore.example.com", object(tree)["host"])
+		assert.Equal(t, "/v1", object(tree)["basePath"])
+		assert.Equal(t, true, object(tree, "definitions", "NewPet", "properties", "tag")["x-nullable"])
+		assert.Equal(t, "#/definitions/Pet", object(tree, "paths", "/pets/{petId}", "get", "responses", "200", "schema")["$ref"])
+
+		body := object(tree, "paths", "/pets", "post")["parameters"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "body", body["in"])
+		assert.Equal(t, "#/definitions/NewPet", object(body, "schema")["$ref"])
+
+		limit := object(tree, "paths", "/pets", "get")["parameters"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "integer", limit["type"])
+		assert.Nil(t, limit["schema"])
+
+		code, back, _ := runCommand(stdout, "convert", "-to", "3.0", "-")
+		assert.Equal(t, ExitOK, code)
+		code, _, _ = runCommand(back, "validate", "-")
+		assert.Equal(t, ExitOK, code)
+	})
+
+	t.Run("openapi 3.0 to 3.1 and back", func(t *testing.T) {
+		code, stdout, _ := runCommand("", "convert", "-to", "3.1", "-format", "yaml", "testdata/petstore.yaml")
+		assert.Equal(t, ExitOK, code)
+		assert.True(t, strings.HasPrefix(stdout, "components:"))
+
+		data, err := oas.YAMLToJSON([]byte(stdout))
+		assert.NoError(t, err)
+
+		var tree interface{}
+		assert.NoError(t, json.Unmarshal(data, &tree))
+		assert.Equal(t, "3.1.0", object(tree)["openapi"])
+		assert.Equal(t, []interface{}{"string", "null"}, object(tree, "components", "schemas", "NewPet", "properties", "tag")["type"])
+
+		code, back, _ := runCommand(string(data), "convert", "-to", "3.0", "-")
+		assert.Equal(t, ExitOK, code)
+
+		code, stdout, _ = runCommand(back, "diff", "testdata/petstore.yaml", "-")
+		assert.Equal(t, ExitOK, code)
+		assert.Empty(t, stdout)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		code, _, _ := runCommand("", "convert", "-to", "4.0", "testdata/petstore.yaml")
+		assert.Equal(t, ExitUsage, code)
+
+		code, _, _ = runCommand(`{"openapi": "4.0.0"}`, "convert", "-to", "3.0", "-")
+		assert.Equal(t, ExitError, code)
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("deref", "replace refs with values they point to", runDeref)
+}
+
+func runDeref(e *env, args []string) error {
+	fs := newFlagSet(e, "deref", "[flags] <file>")
+	o := &output{}
+	o.register(fs)
+	drop := fs.Bool("drop-components", false, "drop components not referred any more, targets of circular refs kept")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	openapi, err := bundle(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	derefed, circular := dereference(openapi)
+	for _, ref := range circular {
+		fmt.Fprintf(e.stderr, "oas deref: circular ref %s kept\n", ref)
+	}
+
+	if *drop {
+		derefed.PruneComponents()
+	}
+
+	return o.write(e, derefed)
+}
+
+// dereference returns a copy of the document with local refs replaced by copies of values they point to,
+// with refs to json pointers of the document out of components too.
+// Refs met again when expanding themselves are kept to break cycles, returned as circular refs sorted.
+func dereference(openapi *oas.OpenAPI) (*oas.OpenAPI, []string) {
+	d := &dereferencer{source: openapi, circular: map[string]bool{}}
+	result := openapi.Clone()
+
+	visitRefs(reflect.ValueOf(&result.Paths), d.visitor(nil))
+	for group := range componentMaps(&result.ComponentsObject) {
+		for _, name := range componentNames(&result.ComponentsObject, group) {
+			// refs of components to themselves kept
+			visitRefs(component(&result.ComponentsObject, group, name), d.visitor([]string{oas.NewComponentRefer(group, name).RefString()}))
+		}
+	}
+
+	return result, slices.Sorted(maps.Keys(d.circular))
+}
+
+type dereferencer struct {
+	source   *oas.OpenAPI
+	tree     interface{}
+	circular map[string]bool
+}
+
+// visitor replaces owners of refs with values referred, expanding holds refs being expanded
+func (d *dereferencer) visitor(expanding []string) refFunc {
+	return func(group string, ref *oas.Reference, owner reflect.Value) {
+		r := ref.Refer.RefString()
+		if !owner.IsValid() || !strings.HasPrefix(r, "#") {
+			return
+		}
+		if slices.Contains(expanding, r) {
+			d.circular[r] = true
+			return
+		}
+		target := d.resolve(group, r)
+		if target == nil {
+			return
+		}
+		value := reflect.New(owner.Type().Elem())
+		if err := decodeValue(target, value.Interface()); err != nil {
+			return
+		}
+		owner.Elem().Set(value.Elem())
+		// refs of the value replaced, or the value itself when a ref too
+		visitRefs(owner, d.visitor(append(expanding[:len(expanding):len(expanding)], r)))
+	}
+}
+
+// resolve returns the component referred, or json value of the pointer of the document
+func (d *dereferencer) resolve(group string, ref string) interface{} {
+	if r := oas.ParseComponentRefer(ref); r != nil && r.Group == group {
+		if c := component(&d.source.ComponentsObject, group, r.ID); c.IsValid() {
+			return c.Interface()
+		}
+		return nil
+	}
+	if d.tree == nil {
+		d.tree = jsonValue(d.source)
+	}
+	v, err := oas.ResolveJSONPointer(d.tree, ref[1:])
+	if err != nil {
+		return nil
+	}
+	return v
+}
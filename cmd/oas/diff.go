@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("diff", "report changes between two documents, breaking changes as errors", runDiff)
+}
+
+func runDiff(e *env, args []string) error {
+	fs := newFlagSet(e, "diff", "[flags] <base> <revision>")
+	r := &report{}
+	r.register(fs, false)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 2); err != nil {
+		return err
+	}
+
+	base, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	revision, err := loadOpenAPI(e, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	return r.write(e, diff(base, revision))
+}
+
+type differ struct {
+	base     *oas.OpenAPI
+	revision *oas.OpenAPI
+	findings []*Finding
+}
+
+func (d *differ) report(breaking bool, rule string, pointer string, format string, args ...interface{}) {
+	severity := SeverityWarning
+	if breaking {
+		severity = SeverityError
+	}
+	d.findings = append(d.findings, &Finding{Severity: severity, Rule: rule, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+func diff(base *oas.OpenAPI, revision *oas.OpenAPI) []*Finding {
+	d := &differ{base: base, revision: revision, findings: make([]*Finding, 0)}
+
+	eachOperation(base, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+		pointer := pointerOf("paths", path, string(method))
+
+		revisionPathItem := revision.Paths.Paths[path]
+		if revisionPathItem == nil || revisionPathItem.Operations.Operations[method] == nil {
+			d.report(true, "operation-removed", pointer, "operation %s removed", operationName(method, path, op))
+			return
+		}
+
+		d.diffOperation(pointer, pathItem, op, revisionPathItem, revisionPathItem.Operations.Operations[method])
+	})
+
+	eachOperation(revision, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+		basePathItem := base.Paths.Paths[path]
+		if basePathItem == nil || basePathItem.Operations.Operations[method] == nil {
+			d.report(false, "operation-added", pointerOf("paths", path, string(method)), "operation %s added", operationName(method, path, op))
+		}
+	})
+
+	return d.findings
+}
+
+func operationName(method oas.HttpMethod, path string, op *oas.Operation) string {
+	if op.OperationId != "" {
+		return op.OperationId
+	}
+	return fmt.Sprintf("%s %s", method, path)
+}
+
+func (d *differ) diffOperation(pointer string, basePathItem *oas.PathItem, base *oas.Operation, revisionPathItem *oas.PathItem, revision *oas.Operation) {
+	if !base.Deprecated && revision.Deprecated {
+		d.report(false, "operation-deprecated", pointer, "operation deprecated")
+	}
+
+	baseParams := map[string]*oas.Parameter{}
+	for _, p := range effectiveParameters(d.base, basePathItem, base) {
+		baseParams[string(p.In)+"."+p.Name] = p
+	}
+
+	revisionParams := map[string]*oas.Parameter{}
+	for _, p := range effectiveParameters(d.revision, revisionPathItem, revision) {
+		revisionParams[string(p.In)+"."+p.Name] = p
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(baseParams)) {
+		bp := baseParams[key]
+		rp, ok := revisionParams[key]
+		if !ok {
+			d.report(false, "parameter-removed", pointer, "parameter %s removed", key)
+			continue
+		}
+		if !bp.Required && rp.Required {
+			d.report(true, "parameter-required", pointer, "parameter %s becomes required", key)
+		}
+		if !d.sameSchema(bp.Schema, rp.Schema) {
+			d.report(true, "parameter-schema-changed", pointer, "schema of parameter %s changed", key)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(revisionParams)) {
+		if _, ok := baseParams[key]; !ok {
+			d.report(revisionParams[key].Required, "parameter-added", pointer, "parameter %s added", key)
+		}
+	}
+
+	d.diffRequestBody(pointer+"/requestBody", d.base.ResolveRequestBody(base.RequestBody), d.revision.ResolveRequestBody(revision.RequestBody))
+	d.diffResponses(pointer+"/responses", &base.Responses.ResponsesObject, &revision.Responses.ResponsesObject)
+}
+
+func (d *differ) diffRequestBody(pointer string, base *oas.RequestBody, revision *oas.RequestBody) {
+	switch {
+	case base == nil && revision == nil:
+		return
+	case base == nil:
+		d.report(revision.Required, "request-body-added", pointer, "request body added")
+		return
+	case revision == nil:
+		d.report(false, "request-body-removed", pointer, "request body removed")
+		return
+	}
+
+	if !base.Required && revision.Required {
+		d.report(true, "request-body-required", pointer, "request body becomes required")
+	}
+
+	for _, ct := range slices.Sorted(maps.Keys(base.Content)) {
+		rmt, ok := revision.Content[ct]
+		if !ok {
+			d.report(true, "request-content-removed", pointer+"/content/"+escapePointer(ct), "content type %s removed", ct)
+			continue
+		}
+		if base.Content[ct] != nil && rmt != nil {
+			// new servers read what old clients send
+			d.diffSchema("request-schema-changed", pointer+"/content/"+escapePointer(ct), ct, base.Content[ct].Schema, rmt.Schema, oas.CompatibilityBackward)
+		}
+	}
+}
+
+func (d *differ) diffResponses(pointer string, base *oas.ResponsesObject, revision *oas.ResponsesObject) {
+	baseResponses, revisionResponses := responsesByKey(base), responsesByKey(revision)
+
+	for _, key := range slices.Sorted(maps.Keys(baseResponses)) {
+		rr, ok := revisionResponses[key]
+		if !ok {
+			d.report(strings.HasPrefix(key, "2"), "response-removed", pointer+"/"+key, "response %s removed", key)
+			continue
+		}
+		d.diffResponse(pointer+"/"+key, d.base.ResolveResponse(baseResponses[key]), d.revision.ResolveResponse(rr))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(revisionResponses)) {
+		if _, ok := baseResponses[key]; !ok {
+			d.report(false, "response-added", pointer+"/"+key, "response %s added", key)
+		}
+	}
+}
+
+// responsesByKey returns responses by keys of the document, like default, 2XX and 200
+func responsesByKey(o *oas.ResponsesObject) map[string]*oas.Response {
+	responses := map[string]*oas.Response{}
+	if o.Default != nil {
+		responses["default"] = o.Default
+	}
+	for class, r := range o.Ranges {
+		responses[fmt.Sprintf("%dXX", class)] = r
+	}
+	for status, r := range o.Responses {
+		responses[strconv.Itoa(status)] = r
+	}
+	return responses
+}
+
+func (d *differ) diffResponse(pointer string, base *oas.Response, revision *oas.Response) {
+	if base == nil || revision == nil {
+		return
+	}
+	for _, ct := range slices.Sorted(maps.Keys(base.Content)) {
+		rmt, ok := revision.Content[ct]
+		if !ok {
+			d.report(true, "response-content-removed", pointer+"/content/"+escapePointer(ct), "content type %s removed", ct)
+			continue
+		}
+		if base.Content[ct] != nil && rmt != nil {
+			// old clients read what new servers send
+			d.diffSchema("response-schema-changed", pointer+"/content/"+escapePointer(ct), ct, base.Content[ct].Schema, rmt.Schema, oas.CompatibilityForward)
+		}
+	}
+}
+
+// diffSchema reports changed schema, breaking when incompatible in the direction
+func (d *differ) diffSchema(rule string, pointer string, ct string, base *oas.Schema, revision *oas.Schema, direction oas.Compatibility) {
+	if d.sameSchema(base, revision) {
+		return
+	}
+
+	checker := &oas.CompatibilityChecker{Old: &d.base.ComponentsObject, New: &d.revision.ComponentsObject}
+
+	breaking := false
+	for _, i := range checker.Check(base, revision).Incompatibilities {
+		if i.Direction == direction {
+			breaking = true
+			d.report(true, rule, pointer+"/schema"+i.Pointer, "schema of %s changed incompatibly: %s", ct, i.Message)
+		}
+	}
+	if !breaking {
+		d.report(false, rule, pointer, "schema of %s changed", ct)
+	}
+}
+
+// sameSchema compares schemas with refs resolved at the top level
+func (d *differ) sameSchema(base *oas.Schema, revision *oas.Schema) bool {
+	return oas.SchemaEqual(d.base.ResolveSchema(base), d.revision.ResolveSchema(revision))
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	_, stdout, _ := runCommand("", "convert", "testdata/petstore.yaml")
+	revision := decodeOpenAPI(t, stdout)
+
+	delete(revision.Paths.Paths["/pets/{petId}"].Operations.Operations, oas.DELETE)
+
+	list := revision.Paths.Paths["/pets"].Operations.Operations[oas.GET]
+	list.Parameters[0].Required = true
+	list.AddParameter(oas.QueryParameter("sort", oas.String(), false))
+	list.Deprecated = true
+
+	create := revision.Paths.Paths["/pets"].Operations.Operations[oas.POST]
+	delete(create.Responses.Responses, 201)
+	create.AddResponse(200, oas.NewResponse("ok"))
+
+	revision.AddOperation(oas.GET, "/stores", oas.NewOperation("listStores"))
+
+	data, _ := json.Marshal(revision)
+
+	code, stdout, _ := runCommand(string(data), "diff", "-format", "json", "testdata/petstore.yaml", "-")
+	assert.Equal(t, ExitFindings, code)
+
+	findings := make([]*Finding, 0)
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &findings))
+
+	changes := map[string]string{}
+	for _, f := range findings {
+		changes[f.Pointer+" "+f.Rule] = f.Severity
+	}
+
+	assert.Equal(t, map[string]string{
+		"/paths/~1pets/get operation-deprecated":            SeverityWarning,
+		"/paths/~1pets/get parameter-required":              SeverityError,
+		"/paths/~1pets/get parameter-added":                 SeverityWarning,
+		"/paths/~1pets/post/responses/201 response-removed": SeverityError,
+		"/paths/~1pets/post/responses/200 response-added":   SeverityWarning,
+		"/paths/~1pets~1{petId}/delete operation-removed":   SeverityError,
+		"/paths/~1stores/get operation-added":               SeverityWarning,
+	}, changes)
+
+	code, stdout, _ = runCommand("", "diff", "testdata/petstore.yaml", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+}
+
+func TestDiffSchemaOrders(t *testing.T) {
+	_, stdout, _ := runCommand("", "convert", "testdata/petstore.yaml")
+	base := decodeOpenAPI(t, stdout)
+	base.Components.Schemas["NewPet"].Required = []string{"name", "tag"}
+
+	file := filepath.Join(t.TempDir(), "base.json")
+	data, _ := json.Marshal(base)
+	assert.NoError(t, os.WriteFile(file, data, 0o644))
+
+	revision := decodeOpenAPI(t, string(data))
+	revision.Components.Schemas["NewPet"].Required = []string{"tag", "name"}
+	data, _ = json.Marshal(revision)
+
+	code, stdout, _ := runCommand(string(data), "diff", file, "-")
+	assert.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+}
+
+func TestDiffResponsesAndRequestSchemas(t *testing.T) {
+	_, stdout, _ := runCommand("", "convert", "testdata/petstore.yaml")
+	base := decodeOpenAPI(t, stdout)
+	base.Paths.Paths["/pets"].Operations.Operations[oas.GET].AddResponseRange(2, oas.NewResponse("ok"))
+
+	file := filepath.Join(t.TempDir(), "base.json")
+	data, _ := json.Marshal(base)
+	assert.NoError(t, os.WriteFile(file, data, 0o644))
+
+	diffWith := func(revision *oas.OpenAPI) map[string]string {
+		data, _ := json.Marshal(revision)
+		_, stdout, _ := runCommand(string(data), "diff", "-format", "json", file, "-")
+
+		findings := make([]*Finding, 0)
+		assert.NoError(t, json.Unmarshal([]byte(stdout), &findings))
+
+		changes := map[string]string{}
+		for _, f := range findings {
+			changes[f.Pointer+" "+f.Rule] = f.Severity
+		}
+		return changes
+	}
+
+	t.Run("default and range responses", func(t *testing.T) {
+		revision := decodeOpenAPI(t, string(data))
+		list := revision.Paths.Paths["/pets"].Operations.Operations[oas.GET]
+		list.Responses.Default = nil
+		list.Responses.Ranges = nil
+		revision.Paths.Paths["/pets/{petId}"].Operations.Operations[oas.DELETE].AddResponseRange(4, oas.NewResponse("failed"))
+
+		assert.Equal(t, map[string]string{
+			"/paths/~1pets/get/responses/default response-removed":       SeverityWarning,
+			"/paths/~1pets/get/responses/2XX response-removed":           SeverityError,
+			"/paths/~1pets~1{petId}/delete/responses/4XX response-added": SeverityWarning,
+		}, diffWith(revision))
+	})
+
+	t.Run("request schema not satisfied by old clients", func(t *testing.T) {
+		revision := decodeOpenAPI(t, string(data))
+		revision.Components.Schemas["NewPet"].Required = []string{"name", "tag"}
+
+		assert.Equal(t, map[string]string{
+			"/paths/~1pets/post/requestBody/content/application~1json/schema/required request-schema-changed": SeverityError,
+		}, diffWith(revision))
+	})
+
+	t.Run("request schema loosened", func(t *testing.T) {
+		revision := decodeOpenAPI(t, string(data))
+		revision.Components.Schemas["NewPet"].Required = nil
+
+		assert.Equal(t, map[string]string{
+			"/paths/~1pets/post/requestBody/content/application~1json request-schema-changed": SeverityWarning,
+		}, diffWith(revision))
+	})
+}
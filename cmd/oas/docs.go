@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-courier/oas/htmldoc"
+	"github.com/go-courier/oas/markdown"
+)
+
+func init() {
+	register("docs", "render markdown reference, or serve html documentation", runDocs)
+}
+
+func runDocs(e *env, args []string) error {
+	fs := newFlagSet(e, "docs", "[flags] <file>")
+	o := &output{}
+	fs.StringVar(&o.path, "o", "", "output file of markdown, stdout when empty")
+	templates := fs.String("templates", "", "file of templates to override the markdown ones")
+	serve := fs.String("serve", "", "address to serve html documentation, like localhost:8080")
+	title := fs.String("title", "", "title of html documentation, title of the document when empty")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	openapi, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *serve != "" {
+		h := htmldoc.NewHandler(openapi)
+		h.Title = *title
+		fmt.Fprintf(e.stderr, "oas docs: serving %s on http://%s\n", fs.Arg(0), *serve)
+		return http.ListenAndServe(*serve, h)
+	}
+
+	r := markdown.NewRenderer()
+	if *templates != "" {
+		text, err := os.ReadFile(*templates)
+		if err != nil {
+			return err
+		}
+		if err := r.Override(string(text)); err != nil {
+			return err
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := r.Render(buf, openapi); err != nil {
+		return err
+	}
+	return o.writeBytes(e, buf.Bytes())
+}
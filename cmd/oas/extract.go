@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("extract", "move repeated inline schemas into components", runExtract)
+}
+
+func runExtract(e *env, args []string) error {
+	fs := newFlagSet(e, "extract", "[flags] <file>")
+	o := &output{}
+	o.register(fs)
+	minOccurrences := fs.Int("min", 2, "min occurrences of inline schemas to extract")
+	ignoreDocs := fs.Bool("ignore-docs", false, "ignore description and example when comparing schemas")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+	if *minOccurrences < 1 {
+		return usagef("min occurrences should be at least 1")
+	}
+
+	openapi, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	extractor := &oas.SchemaExtractor{
+		SchemaComparer: oas.SchemaComparer{IgnoreDocs: *ignoreDocs},
+		MinOccurrences: *minOccurrences,
+	}
+	refactored, extracted, err := extractor.Extract(openapi)
+	if err != nil {
+		return err
+	}
+	for _, x := range extracted {
+		fmt.Fprintf(e.stderr, "#/components/schemas/%s: %d occurrences replaced\n", x.Name, len(x.Pointers))
+	}
+
+	return o.write(e, refactored)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("filter", "keep operations matched by tags, paths, operationIds or extensions, with components they refer", runFilter)
+}
+
+// listFlag collects values of repeated flag, values separated by comma accepted too
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(v string) error {
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// extensionsFlag collects extensions like x-internal or x-audience=partner, value parsed as json when valid
+type extensionsFlag map[string]interface{}
+
+func (f extensionsFlag) String() string {
+	return strings.Join(slices.Sorted(maps.Keys(f)), ",")
+}
+
+func (f extensionsFlag) Set(v string) error {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok {
+		f[key] = nil
+		return nil
+	}
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		parsed = value
+	}
+	f[key] = parsed
+	return nil
+}
+
+func runFilter(e *env, args []string) error {
+	fs := newFlagSet(e, "filter", "[flags] <file>")
+	o := &output{}
+	o.register(fs)
+	tags, paths, operationIds := listFlag{}, listFlag{}, listFlag{}
+	extensions, excludeExtensions := extensionsFlag{}, extensionsFlag{}
+	fs.Var(&tags, "tag", "keep operations with the tag, repeatable")
+	fs.Var(&paths, "path", "keep operations of paths matched the glob like /pets/*, repeatable")
+	fs.Var(&operationIds, "operation", "keep operations with the operationId, repeatable")
+	fs.Var(extensions, "extension", "keep operations with the extension like x-public or x-audience=partner, repeatable")
+	fs.Var(excludeExtensions, "exclude-extension", "drop operations with the extension like x-internal or x-internal=true, repeatable")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	for _, pattern := range paths {
+		if _, err := path.Match(pattern, "/"); err != nil {
+			return usagef("invalid path glob %q", pattern)
+		}
+	}
+
+	openapi, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	filtered, err := (&oas.Filter{
+		Tags:              tags,
+		Paths:             paths,
+		OperationIds:      operationIds,
+		Extensions:        extensions,
+		ExcludeExtensions: excludeExtensions,
+	}).Apply(openapi)
+	if err != nil {
+		return err
+	}
+
+	return o.write(e, filtered)
+}
@@ -0,0 +1,697 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("gen", "generate go models, client or server of the document", runGen)
+}
+
+func runGen(e *env, args []string) error {
+	fs := newFlagSet(e, "gen", "[flags] <file>")
+	o := &output{}
+	fs.StringVar(&o.path, "o", "", "output file, stdout when empty")
+	pkg := fs.String("package", "api", "package name of generated code")
+	mode := fs.String("mode", "models", "code to generate, models, client or server, separated by comma")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	modes := map[string]bool{}
+	for _, m := range strings.Split(*mode, ",") {
+		m = strings.TrimSpace(m)
+		switch m {
+		case "models", "client", "server":
+			modes[m] = true
+		default:
+			return usagef("unsupported mode %q", m)
+		}
+	}
+
+	openapi, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	g := newGenerator(openapi, *pkg)
+	g.warn = func(format string, args ...interface{}) {
+		fmt.Fprintf(e.stderr, "oas gen: "+format+"\n", args...)
+	}
+
+	code, err := g.generate(modes)
+	if err != nil {
+		return err
+	}
+
+	return o.writeBytes(e, code)
+}
+
+type generator struct {
+	openapi *oas.OpenAPI
+	pkg     string
+	// names of component schemas
+	names map[string]string
+	buf   *bytes.Buffer
+	warn  func(format string, args ...interface{})
+}
+
+func newGenerator(openapi *oas.OpenAPI, pkg string) *generator {
+	g := &generator{
+		openapi: openapi,
+		pkg:     pkg,
+		names:   map[string]string{},
+		buf:     bytes.NewBuffer(nil),
+		warn:    func(format string, args ...interface{}) {},
+	}
+
+	used := map[string]bool{}
+	for _, name := range slices.Sorted(maps.Keys(openapi.Schemas)) {
+		g.names[name] = uniqueGoName(used, goName(name))
+	}
+
+	return g
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(g.buf, format, args...)
+}
+
+// generate returns formatted code, imports added by packages used
+func (g *generator) generate(modes map[string]bool) ([]byte, error) {
+	if modes["models"] {
+		g.models()
+	}
+
+	ops := g.operations()
+
+	if modes["client"] || modes["server"] {
+		for _, op := range ops {
+			g.params(op)
+		}
+	}
+	if modes["client"] {
+		g.client(ops)
+	}
+	if modes["server"] {
+		g.server(ops)
+	}
+
+	body := g.buf.String()
+
+	imports, err := usedImports(body)
+	if err != nil {
+		return nil, err
+	}
+
+	src := bytes.NewBuffer(nil)
+	fmt.Fprintf(src, "// Code generated by oas gen. DO NOT EDIT.\n\npackage %s\n\n", g.pkg)
+	if len(imports) > 0 {
+		src.WriteString("import (\n")
+		for _, p := range imports {
+			fmt.Fprintf(src, "%q\n", p)
+		}
+		src.WriteString(")\n\n")
+	}
+	src.WriteString(body)
+
+	code, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return code, nil
+}
+
+// runtime code of generated client and server, used by code generated for operations
+
+//go:embed runtime/client.go.txt
+var clientRuntime string
+
+//go:embed runtime/server.go.txt
+var serverRuntime string
+
+var knownImports = map[string]string{
+	"bytes":   "bytes",
+	"context": "context",
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"io":      "io",
+	"reflect": "reflect",
+	"json":    "encoding/json",
+	"http":    "net/http",
+	"url":     "net/url",
+	"strconv": "strconv",
+	"strings": "strings",
+	"time":    "time",
+}
+
+// usedImports returns imports of packages referred by the code
+func usedImports(body string) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+body, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse generated code: %w", err)
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				if p, ok := knownImports[ident.Name]; ok {
+					used[p] = true
+				}
+			}
+		}
+		return true
+	})
+
+	return slices.Sorted(maps.Keys(used)), nil
+}
+
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "TLS": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goName converts name like pet_id or petId to exported go name PetID
+func goName(name string) string {
+	words := make([]string, 0)
+	current := make([]rune, 0)
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	b := strings.Builder{}
+	for _, w := range words {
+		upper := strings.ToUpper(w)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		rs := []rune(strings.ToLower(w))
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+
+	s := b.String()
+	if s == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(s)[0]) {
+		return "X" + s
+	}
+	return s
+}
+
+func uniqueGoName(used map[string]bool, name string) string {
+	n := name
+	for i := 2; used[n]; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	used[n] = true
+	return n
+}
+
+func writeComment(buf *bytes.Buffer, prefix string, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for i, line := range strings.Split(text, "\n") {
+		if i == 0 && prefix != "" && !strings.HasPrefix(line, prefix+" ") {
+			line = prefix + " " + line
+		}
+		fmt.Fprintf(buf, "// %s\n", strings.TrimRightFunc(line, unicode.IsSpace))
+	}
+}
+
+func (g *generator) models() {
+	for _, name := range slices.Sorted(maps.Keys(g.openapi.Schemas)) {
+		s := g.openapi.Schemas[name]
+		if s == nil {
+			continue
+		}
+		typeName := g.names[name]
+
+		desc := s.Description
+		if desc == "" {
+			desc = s.Title
+		}
+		writeComment(g.buf, typeName, desc)
+
+		if s.Refer == nil && s.Type == oas.TypeString && len(s.Enum) > 0 {
+			g.printf("type %s string\n\n", typeName)
+			g.printf("const (\n")
+			used := map[string]bool{}
+			for _, v := range s.Enum {
+				str, ok := v.(string)
+				if !ok {
+					continue
+				}
+				g.printf("%s %s = %q\n", uniqueGoName(used, typeName+goName(str)), typeName, str)
+			}
+			g.printf(")\n\n")
+			continue
+		}
+
+		g.printf("type %s %s\n\n", typeName, g.goType(s))
+	}
+}
+
+// goType returns go type expression of the schema
+func (g *generator) goType(s *oas.Schema) string {
+	if s == nil {
+		return "interface{}"
+	}
+
+	if s.Refer != nil {
+		if ref := oas.ParseComponentRefer(s.Refer.RefString()); ref != nil && ref.Group == "schemas" {
+			if name, ok := g.names[ref.ID]; ok {
+				return name
+			}
+		}
+		g.warn("ref %s not supported, interface{} used", s.Refer.RefString())
+		return "interface{}"
+	}
+
+	if len(s.AllOf) > 0 {
+		if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+			return g.goType(s.AllOf[0])
+		}
+		return g.structType(s)
+	}
+
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return "json.RawMessage"
+	}
+
+	switch s.Type {
+	case oas.TypeString:
+		switch s.Format {
+		case "date-time":
+			return "time.Time"
+		case "binary":
+			return "[]byte"
+		}
+		return "string"
+	case oas.TypeInteger:
+		switch s.Format {
+		case "int32":
+			return "int32"
+		case "int64":
+			return "int64"
+		}
+		return "int"
+	case oas.TypeNumber:
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case oas.TypeBoolean:
+		return "bool"
+	case oas.TypeArray:
+		return "[]" + g.goType(s.Items)
+	}
+
+	if len(s.Properties) > 0 {
+		return g.structType(s)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		return "map[string]" + g.goType(s.AdditionalProperties.Schema)
+	}
+	if s.Type == oas.TypeObject {
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// structType returns struct of properties, schemas of allOf embedded
+func (g *generator) structType(s *oas.Schema) string {
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("struct {\n")
+
+	properties := map[string]*oas.Schema{}
+	required := map[string]bool{}
+
+	for _, sub := range s.AllOf {
+		if sub != nil && sub.Refer != nil {
+			fmt.Fprintf(buf, "%s\n", g.goType(sub))
+			continue
+		}
+		if sub != nil {
+			for name := range sub.Properties {
+				properties[name] = sub.Properties[name]
+			}
+			for _, name := range sub.Required {
+				required[name] = true
+			}
+		}
+	}
+
+	for name := range s.Properties {
+		properties[name] = s.Properties[name]
+	}
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	used := map[string]bool{}
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		prop := properties[name]
+		if prop != nil {
+			writeComment(buf, "", prop.Description)
+		}
+		tag := name
+		if !required[name] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "%s %s `json:%q`\n", uniqueGoName(used, goName(name)), g.fieldType(prop, required[name]), tag)
+	}
+
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// fieldType returns type of field, pointer used for optional or nullable values except slices, maps and interfaces
+func (g *generator) fieldType(s *oas.Schema, required bool) string {
+	t := g.goType(s)
+	resolved := g.openapi.ResolveSchema(s)
+	optional := !required || (resolved != nil && resolved.Nullable)
+	if optional && !strings.HasPrefix(t, "[]") && !strings.HasPrefix(t, "map[") && t != "interface{}" && t != "json.RawMessage" {
+		return "*" + t
+	}
+	return t
+}
+
+type genParam struct {
+	field    string
+	name     string
+	in       oas.Position
+	required bool
+	schema   *oas.Schema
+}
+
+type genOperation struct {
+	name     string
+	method   oas.HttpMethod
+	path     string
+	op       *oas.Operation
+	params   []*genParam
+	body     *oas.Schema
+	bodyType string
+	// bodyRequired true when the request body required
+	bodyRequired bool
+	status       int
+	resultType   string
+}
+
+func (o *genOperation) hasParams() bool {
+	return len(o.params) > 0 || o.body != nil
+}
+
+func (g *generator) operations() []*genOperation {
+	ops := make([]*genOperation, 0)
+	used := map[string]bool{}
+
+	eachOperation(g.openapi, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+		name := op.OperationId
+		if name == "" {
+			name = string(method) + " " + path
+		}
+
+		o := &genOperation{
+			name:   uniqueGoName(used, goName(name)),
+			method: method,
+			path:   path,
+			op:     op,
+			status: http.StatusOK,
+		}
+
+		fields := map[string]bool{"Body": true}
+		for _, p := range effectiveParameters(g.openapi, pathItem, op) {
+			o.params = append(o.params, &genParam{
+				field:    uniqueGoName(fields, goName(p.Name)),
+				name:     p.Name,
+				in:       p.In,
+				required: p.Required || p.In == oas.PositionPath,
+				schema:   p.Schema,
+			})
+		}
+
+		if rb := g.openapi.ResolveRequestBody(op.RequestBody); rb != nil {
+			if s, ok := jsonSchemaOf(rb.Content); ok {
+				o.body = s
+				o.bodyRequired = rb.Required
+				o.bodyType = g.fieldType(s, rb.Required)
+			} else if len(rb.Content) > 0 {
+				g.warn("request body of %s %s not json, skipped", method, path)
+			}
+		}
+
+		statuses := make([]int, 0)
+		for status := range op.Responses.Responses {
+			if status >= 200 && status < 300 {
+				statuses = append(statuses, status)
+			}
+		}
+		sort.Ints(statuses)
+
+		var r *oas.Response
+		if len(statuses) > 0 {
+			o.status = statuses[0]
+			r = g.openapi.ResolveResponse(op.Responses.Responses[statuses[0]])
+		} else if op.Responses.Ranges[2] != nil {
+			r = g.openapi.ResolveResponse(op.Responses.Ranges[2])
+		}
+		if r != nil {
+			if s, ok := jsonSchemaOf(r.Content); ok {
+				o.resultType = g.goType(s)
+			}
+		}
+
+		ops = append(ops, o)
+	})
+
+	return ops
+}
+
+func jsonSchemaOf(content map[string]*oas.MediaType) (*oas.Schema, bool) {
+	for _, ct := range slices.Sorted(maps.Keys(content)) {
+		if isJSONContentType(ct) && content[ct] != nil {
+			return content[ct].Schema, true
+		}
+	}
+	return nil, false
+}
+
+func (g *generator) params(o *genOperation) {
+	if !o.hasParams() {
+		return
+	}
+	g.printf("// %sParams are parameters of %s %s\n", o.name, strings.ToUpper(string(o.method)), o.path)
+	g.printf("type %sParams struct {\n", o.name)
+	for _, p := range o.params {
+		g.printf("%s %s // %s %s\n", p.field, g.fieldType(p.schema, p.required), p.in, p.name)
+	}
+	if o.body != nil {
+		g.printf("Body %s\n", o.bodyType)
+	}
+	g.printf("}\n\n")
+}
+
+func (o *genOperation) signature() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%s(ctx context.Context", o.name)
+	if o.hasParams() {
+		fmt.Fprintf(&b, ", params *%sParams", o.name)
+	}
+	b.WriteString(")")
+	if o.resultType != "" {
+		fmt.Fprintf(&b, " (%s, error)", o.resultType)
+	} else {
+		b.WriteString(" error")
+	}
+	return b.String()
+}
+
+func (o *genOperation) comment() string {
+	text := o.op.Summary
+	if text == "" {
+		text = strings.ToUpper(string(o.method)) + " " + o.path
+	}
+	if o.op.Deprecated {
+		text += "\n\nDeprecated: " + o.name + " is deprecated."
+	}
+	return text
+}
+
+var reAnyParamInPath = regexp.MustCompile(`\{([^}]+)\}`)
+
+func (g *generator) client(ops []*genOperation) {
+	g.printf("// Client calls operations of %s\n", strconv.Quote(g.openapi.Title))
+	g.buf.WriteString(clientRuntime)
+
+	for _, o := range ops {
+		writeComment(g.buf, o.name, o.comment())
+		g.printf("func (c *Client) %s {\n", o.signature())
+
+		path := strconv.Quote(o.path)
+		for _, p := range o.params {
+			if p.in == oas.PositionPath {
+				path = strings.ReplaceAll(path, "{"+p.name+"}", `" + url.PathEscape(fmt.Sprint(params.`+p.field+`)) + "`)
+			}
+		}
+		path = strings.ReplaceAll(path, ` + ""`, "")
+		g.printf("path := %s\n", path)
+		g.printf("query, header := url.Values{}, http.Header{}\n")
+
+		for _, p := range o.params {
+			switch p.in {
+			case oas.PositionQuery:
+				g.printf("addParam(query.Add, %q, params.%s)\n", p.name, p.field)
+			case oas.PositionHeader:
+				g.printf("addParam(header.Add, %q, params.%s)\n", p.name, p.field)
+			case oas.PositionCookie:
+				g.printf("addParam(addCookie(header), %q, params.%s)\n", p.name, p.field)
+			}
+		}
+
+		body := "nil"
+		if o.body != nil {
+			body = "params.Body"
+		}
+		method := strconv.Quote(strings.ToUpper(string(o.method)))
+
+		if o.resultType != "" {
+			g.printf("var result %s\n", o.resultType)
+			g.printf("err := c.do(ctx, %s, path, query, header, %s, &result)\n", method, body)
+			g.printf("return result, err\n")
+		} else {
+			g.printf("return c.do(ctx, %s, path, query, header, %s, nil)\n", method, body)
+		}
+
+		g.printf("}\n\n")
+	}
+}
+
+var reInvalidIdent = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// servePattern returns pattern of http.ServeMux, false when the path template could not be routed
+func servePattern(method oas.HttpMethod, path string, wildcards map[string]string) (string, bool) {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if !strings.Contains(seg, "{") {
+			continue
+		}
+		m := reAnyParamInPath.FindStringSubmatch(seg)
+		if m == nil || m[0] != seg {
+			return "", false
+		}
+		segments[i] = "{" + wildcards[m[1]] + "}"
+	}
+	p := strings.Join(segments, "/")
+	if strings.HasSuffix(p, "/") {
+		p += "{$}"
+	}
+	return strings.ToUpper(string(method)) + " " + p, true
+}
+
+func (g *generator) server(ops []*genOperation) {
+	g.printf("// Server implements operations of %s\n", strconv.Quote(g.openapi.Title))
+	g.printf("type Server interface {\n")
+	for _, o := range ops {
+		writeComment(g.buf, o.name, o.comment())
+		g.printf("%s\n", o.signature())
+	}
+	g.printf("}\n\n")
+
+	g.printf("// NewHandler routes requests to operations of the server\n")
+	g.printf("func NewHandler(s Server) http.Handler {\n")
+	g.printf("mux := http.NewServeMux()\n\n")
+
+	for _, o := range ops {
+		wildcards := map[string]string{}
+		for _, p := range o.params {
+			if p.in == oas.PositionPath {
+				wildcards[p.name] = reInvalidIdent.ReplaceAllString(p.name, "_")
+			}
+		}
+
+		pattern, ok := servePattern(o.method, o.path, wildcards)
+		if !ok {
+			g.warn("path %s could not be routed by http.ServeMux, %s skipped", o.path, o.name)
+			g.printf("// %s not routed, since http.ServeMux could not match %s\n\n", o.name, o.path)
+			continue
+		}
+
+		g.printf("mux.HandleFunc(%q, func(w http.ResponseWriter, r *http.Request) {\n", pattern)
+
+		args := "r.Context()"
+		if o.hasParams() {
+			args += ", params"
+			g.printf("params := &%sParams{}\n", o.name)
+			g.printf("if err := errors.Join(\n")
+			for _, p := range o.params {
+				name := p.name
+				if p.in == oas.PositionPath {
+					name = wildcards[p.name]
+				}
+				g.printf("bindParam(r, %q, %q, %t, &params.%s),\n", p.in, name, p.required, p.field)
+			}
+			if o.body != nil {
+				g.printf("decodeBody(r, &params.Body, %t),\n", o.bodyRequired)
+			}
+			g.printf("); err != nil {\nwriteError(w, err)\nreturn\n}\n")
+		}
+
+		if o.resultType != "" {
+			g.printf("result, err := s.%s(%s)\n", o.name, args)
+			g.printf("if err != nil {\nwriteError(w, err)\nreturn\n}\n")
+			g.printf("writeJSON(w, %d, result)\n", o.status)
+		} else {
+			g.printf("if err := s.%s(%s); err != nil {\nwriteError(w, err)\nreturn\n}\n", o.name, args)
+			g.printf("w.WriteHeader(%d)\n", o.status)
+		}
+
+		g.printf("})\n\n")
+	}
+
+	g.printf("return mux\n}\n\n")
+	g.buf.WriteString(serverRuntime)
+}
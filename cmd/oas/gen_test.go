@@ -0,0 +1,60 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoName(t *testing.T) {
+	cases := map[string]string{
+		"petId":        "PetID",
+		"pet_id":       "PetID",
+		"HTTPServer":   "HTTPServer",
+		"x-request-id": "XRequestID",
+		"listPets":     "ListPets",
+		"get /pets":    "GetPets",
+		"2fa":          "X2fa",
+		"":             "X",
+	}
+	for name, expect := range cases {
+		assert.Equal(t, expect, goName(name), name)
+	}
+}
+
+func TestGen(t *testing.T) {
+	code, stdout, _ := runCommand("", "gen", "-mode", "models,client,server", "-package", "petstore", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "petstore.go", stdout, parser.ParseComments)
+	assert.NoError(t, err)
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("petstore", fset, []*ast.File{f}, nil)
+	assert.NoError(t, err)
+
+	for _, name := range []string{"Pet", "NewPet", "Status", "StatusAvailable", "Client", "NewClient", "Server", "NewHandler", "ListPetsParams"} {
+		assert.NotNil(t, pkg.Scope().Lookup(name), name)
+	}
+
+	server := pkg.Scope().Lookup("Server").Type().Underlying().(*types.Interface)
+	assert.Equal(t, 4, server.NumMethods())
+
+	pet := pkg.Scope().Lookup("Pet").Type().Underlying().(*types.Struct)
+	assert.Equal(t, "NewPet", pet.Field(0).Name())
+	assert.True(t, pet.Field(0).Embedded())
+	assert.Equal(t, `json:"id"`, pet.Tag(1))
+
+	code, stdout, _ = runCommand("", "gen", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+	assert.NotContains(t, stdout, "Client")
+
+	code, _, _ = runCommand("", "gen", "-mode", "sdk", "testdata/petstore.yaml")
+	assert.Equal(t, ExitUsage, code)
+}
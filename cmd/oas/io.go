@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+func readInput(e *env, path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(e.stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// toJSON converts yaml to json, json returned as is
+func toJSON(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return trimmed, nil
+	}
+	return oas.YAMLToJSON(data)
+}
+
+func readJSON(e *env, path string) ([]byte, error) {
+	data, err := readInput(e, path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = toJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
+
+func loadOpenAPI(e *env, path string) (*oas.OpenAPI, error) {
+	data, err := readJSON(e, path)
+	if err != nil {
+		return nil, err
+	}
+	openapi := &oas.OpenAPI{}
+	if err := json.Unmarshal(data, openapi); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return openapi, nil
+}
+
+// loadTree loads document as generic json values
+func loadTree(e *env, path string) (interface{}, error) {
+	data, err := readJSON(e, path)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return v, nil
+}
+
+// output writes documents as json or yaml to stdout or file
+type output struct {
+	path   string
+	format string
+}
+
+func (o *output) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.path, "o", "", "output file, stdout when empty")
+	fs.StringVar(&o.format, "format", "", "output format json or yaml, decided by extension of output file when empty, json by default")
+}
+
+func (o *output) resolveFormat() (string, error) {
+	format := o.format
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(o.path)) {
+		case ".yaml", ".yml":
+			format = "yaml"
+		default:
+			format = "json"
+		}
+	}
+	if format != "json" && format != "yaml" {
+		return "", usagef("unsupported format %q", format)
+	}
+	return format, nil
+}
+
+func (o *output) write(e *env, v interface{}) error {
+	format, err := o.resolveFormat()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if format == "yaml" {
+		data, err = oas.JSONToYAML(data)
+	} else {
+		buf := bytes.NewBuffer(nil)
+		err = json.Indent(buf, data, "", "  ")
+		buf.WriteByte('\n')
+		data = buf.Bytes()
+	}
+	if err != nil {
+		return err
+	}
+
+	return o.writeBytes(e, data)
+}
+
+func (o *output) writeBytes(e *env, data []byte) error {
+	if o.path == "" || o.path == "-" {
+		_, err := e.stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(o.path, data, 0o644)
+}
+
+func requireArgs(fs *flag.FlagSet, n int) error {
+	if fs.NArg() != n {
+		fs.Usage()
+		return usagef("expect %d file(s), but got %d", n, fs.NArg())
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("lint", "check style rules of documents", runLint)
+}
+
+type lintContext struct {
+	openapi *oas.OpenAPI
+	tree    interface{}
+}
+
+type lintRule struct {
+	name        string
+	severity    string
+	description string
+	check       func(c *lintContext, report func(pointer string, format string, args ...interface{}))
+}
+
+var lintRules = []*lintRule{
+	{
+		name:        "operation-id",
+		severity:    SeverityError,
+		description: "operations should have operationId",
+		check: func(c *lintContext, report func(pointer string, format string, args ...interface{})) {
+			eachOperation(c.openapi, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+				if op.OperationId == "" {
+					report(pointerOf("paths", path, string(method)), "operationId missing")
+				}
+			})
+		},
+	},
+	{
+		name:        "operation-summary",
+		severity:    SeverityWarning,
+		description: "operations should have summary or description",
+		check: func(c *lintContext, report func(pointer string, format string, args ...interface{})) {
+			eachOperation(c.openapi, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+				if op.Summary == "" && op.Description == "" {
+					report(pointerOf("paths", path, string(method)), "summary or description missing")
+				}
+			})
+		},
+	},
+	{
+		name:        "operation-tags",
+		severity:    SeverityWarning,
+		description: "operations should have tags declared by the document",
+		check: func(c *lintContext, report func(pointer string, format string, args ...interface{})) {
+			declared := map[string]bool{}
+			for _, t := range c.openapi.Tags {
+				if t != nil {
+					declared[t.Name] = true
+				}
+			}
+			eachOperation(c.openapi, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+				pointer := pointerOf("paths", path, string(method))
+				if len(op.Tags) == 0 {
+					report(pointer, "tags missing")
+				}
+				for i, name := range op.Tags {
+					if !declared[name] {
+						report(fmt.Sprintf("%s/tags/%d", pointer, i), "tag %q not declared", name)
+					}
+				}
+			})
+		},
+	},
+	{
+		name:        "tag-unused",
+		severity:    SeverityWarning,
+		description: "declared tags should be used by operations",
+		check: func(c *lintContext, report func(pointer string, format string, args ...interface{})) {
+			used := map[string]bool{}
+			eachOperation(c.openapi, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+				for _, name := range op.Tags {
+					used[name] = true
+				}
+			})
+			for i, t := range c.openapi.Tags {
+				if t != nil && !used[t.Name] {
+					report(fmt.Sprintf("/tags/%d", i), "tag %q not used", t.Name)
+				}
+			}
+		},
+	},
+	{
+		name:        "success-response",
+		severity:    SeverityWarning,
+		description: "operations should have success response",
+		check: func(c *lintContext, report func(pointer string, format string, args ...interface{})) {
+			eachOperation(c.openapi, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+				if op.Responses.Ranges[2] != nil || op.Responses.Default != nil {
+					return
+				}
+				for status := range op.Responses.Responses {
+					if status >= 200 && status < 300 {
+						return
+					}
+				}
+				report(pointerOf("paths", path, string(method), "responses"), "2xx response missing")
+			})
+		},
+	},
+	{
+		name:        "path-trailing-slash",
+		severity:    SeverityWarning,
+		description: "paths should not end with slash",
+		check: func(c *lintContext, report func(pointer string, format string, args ...interface{})) {
+			for _, path := range slices.Sorted(maps.Keys(c.openapi.Paths.Paths)) {
+				if len(path) > 1 && strings.HasSuffix(path, "/") {
+					report(pointerOf("paths", path), "path ends with slash")
+				}
+			}
+		},
+	},
+	{
+		name:        "parameter-description",
+		severity:    SeverityWarning,
+		description: "parameters should have description",
+		check: func(c *lintContext, report func(pointer string, format string, args ...interface{})) {
+			eachOperation(c.openapi, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+				for _, p := range effectiveParameters(c.openapi, pathItem, op) {
+					if p.Description == "" {
+						report(pointerOf("paths", path, string(method)), "description of %s parameter %q missing", p.In, p.Name)
+					}
+				}
+			})
+		},
+	},
+	{
+		name:        "component-unused",
+		severity:    SeverityWarning,
+		description: "components should be referenced from paths or security requirements",
+		check: func(c *lintContext, report func(pointer string, format string, args ...interface{})) {
+			for _, u := range c.openapi.UnusedComponents() {
+				if u.Count > 0 {
+					report(u.Pointer(), "%s %q only referenced by unused components", u.Group, u.Name)
+					continue
+				}
+				report(u.Pointer(), "%s %q not referenced", u.Group, u.Name)
+			}
+		},
+	},
+}
+
+func runLint(e *env, args []string) error {
+	fs := newFlagSet(e, "lint", "[flags] <file>")
+	r := &report{}
+	r.register(fs, true)
+	disable := fs.String("disable", "", "rules to disable, separated by comma")
+	list := fs.Bool("rules", false, "list rules")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *list {
+		for _, rule := range lintRules {
+			fmt.Fprintf(e.stdout, "%-22s %-8s %s\n", rule.name, rule.severity, rule.description)
+		}
+		return nil
+	}
+
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	disabled := map[string]bool{}
+	for _, name := range strings.Split(*disable, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			disabled[name] = true
+		}
+	}
+
+	openapi, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	findings, err := lint(openapi, disabled)
+	if err != nil {
+		return err
+	}
+
+	return r.write(e, findings)
+}
+
+func lint(openapi *oas.OpenAPI, disabled map[string]bool) ([]*Finding, error) {
+	data, err := json.Marshal(openapi)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &lintContext{openapi: openapi}
+	if err := json.Unmarshal(data, &c.tree); err != nil {
+		return nil, err
+	}
+
+	findings := make([]*Finding, 0)
+
+	for _, rule := range lintRules {
+		if disabled[rule.name] {
+			continue
+		}
+		rule.check(c, func(pointer string, format string, args ...interface{}) {
+			findings = append(findings, &Finding{Severity: rule.severity, Rule: rule.name, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+		})
+	}
+
+	return findings, nil
+}
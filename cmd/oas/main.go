@@ -0,0 +1,141 @@
+// Command oas validates, lints, transforms and serves OpenAPI documents.
+//
+// Usage:
+//
+//	oas <command> [flags] [files]
+//
+// Exit codes:
+//
+//	0  success
+//	1  findings reported, like validation errors, lint errors or breaking changes
+//	2  invalid usage
+//	3  failed to read, parse or write documents
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+const (
+	ExitOK       = 0
+	ExitFindings = 1
+	ExitUsage    = 2
+	ExitError    = 3
+)
+
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type command struct {
+	summary string
+	run     func(e *env, args []string) error
+}
+
+var commands = map[string]*command{}
+
+func register(name string, summary string, run func(e *env, args []string) error) {
+	commands[name] = &command{summary: summary, run: run}
+}
+
+// errFindings is returned when the command completed and reported findings
+var errFindings = errors.New("findings reported")
+
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}))
+}
+
+func run(args []string, e *env) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printUsage(e.stderr)
+		if len(args) == 0 {
+			return ExitUsage
+		}
+		return ExitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "oas: unknown command %q\n\n", args[0])
+		printUsage(e.stderr)
+		return ExitUsage
+	}
+
+	err := cmd.run(e, args[1:])
+
+	var ue *usageError
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, errFindings):
+		return ExitFindings
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.As(err, &ue):
+		fmt.Fprintf(e.stderr, "oas %s: %s\n", args[0], err)
+		return ExitUsage
+	default:
+		fmt.Fprintf(e.stderr, "oas %s: %s\n", args[0], err)
+		return ExitError
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: oas <command> [flags] [files]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "oas <command> -h" for flags of the command.`)
+}
+
+// newFlagSet creates flag set reports errors as usage errors
+func newFlagSet(e *env, name string, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: oas %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+	return nil
+}
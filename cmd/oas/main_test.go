@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	stdout, stderr := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
+	code := run(args, &env{stdin: strings.NewReader(stdin), stdout: stdout, stderr: stderr})
+	return code, stdout.String(), stderr.String()
+}
+
+func decodeOpenAPI(t *testing.T, data string) *oas.OpenAPI {
+	openapi := &oas.OpenAPI{}
+	assert.NoError(t, json.Unmarshal([]byte(data), openapi))
+	return openapi
+}
+
+func TestRun(t *testing.T) {
+	code, _, stderr := runCommand("")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, "validate")
+
+	code, _, _ = runCommand("", "help")
+	assert.Equal(t, ExitOK, code)
+
+	code, _, stderr = runCommand("", "unknown")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, stderr, `unknown command "unknown"`)
+
+	code, _, _ = runCommand("", "validate", "-unknown", "testdata/petstore.yaml")
+	assert.Equal(t, ExitUsage, code)
+
+	code, _, _ = runCommand("", "bundle", "testdata/petstore.yaml", "testdata/swagger.json")
+	assert.Equal(t, ExitUsage, code)
+
+	code, _, stderr = runCommand("", "bundle", "testdata/missing.yaml")
+	assert.Equal(t, ExitError, code)
+	assert.Contains(t, stderr, "missing.yaml")
+
+	code, _, _ = runCommand("", "lint", "-h")
+	assert.Equal(t, ExitOK, code)
+}
+
+func TestLint(t *testing.T) {
+	code, stdout, _ := runCommand("", "lint", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+
+	doc := `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "tags": [{"name": "unused"}],
+		"paths": {"/items/": {"get": {"responses": {"404": {"description": "not found"}}}}},
+		"components": {"schemas": {"Unused": {"type": "string"}}}}`
+
+	code, stdout, _ = runCommand(doc, "lint", "-format", "json", "-")
+	assert.Equal(t, ExitFindings, code)
+
+	findings := make([]*Finding, 0)
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &findings))
+
+	rules := map[string]bool{}
+	for _, f := range findings {
+		rules[f.Rule] = true
+	}
+	assert.Equal(t, map[string]bool{
+		"operation-id":        true,
+		"operation-summary":   true,
+		"operation-tags":      true,
+		"tag-unused":          true,
+		"success-response":    true,
+		"path-trailing-slash": true,
+		"component-unused":    true,
+	}, rules)
+
+	code, _, _ = runCommand(doc, "lint", "-disable", "operation-id", "-")
+	assert.Equal(t, ExitOK, code)
+
+	code, _, _ = runCommand(doc, "lint", "-disable", "operation-id", "-strict", "-")
+	assert.Equal(t, ExitFindings, code)
+}
+
+func TestFilter(t *testing.T) {
+	code, stdout, _ := runCommand("", "filter", "-path", "/pets/*", "-operation", "deletePet,showPetById", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+
+	openapi := decodeOpenAPI(t, stdout)
+	assert.Equal(t, []string{"/pets/{petId}"}, slices.Sorted(maps.Keys(openapi.Paths.Paths)))
+	assert.Len(t, openapi.Paths.Paths["/pets/{petId}"].Operations.Operations, 2)
+
+	code, stdout, _ = runCommand("", "filter", "-tag", "unknown", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+	assert.Empty(t, decodeOpenAPI(t, stdout).Paths.Paths)
+
+	code, _, _ = runCommand("", "filter", "-path", "[", "testdata/petstore.yaml")
+	assert.Equal(t, ExitUsage, code)
+
+	code, stdout, _ = runCommand("", "filter", "-operation", "deletePet", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+
+	openapi = decodeOpenAPI(t, stdout)
+	assert.Equal(t, []string{"PetId"}, slices.Sorted(maps.Keys(openapi.Parameters)))
+	assert.Empty(t, openapi.Schemas)
+	assert.Empty(t, openapi.Responses)
+
+	internal := filepath.Join(t.TempDir(), "internal.json")
+	assert.NoError(t, os.WriteFile(internal, []byte(`{"openapi": "3.0.3", "info": {"title": "internal", "version": "1"},
+		"paths": {
+			"/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "ok"}}}},
+			"/admin": {"x-internal": true, "get": {"operationId": "admin", "responses": {"200": {"description": "ok"}}}}
+		}}`), 0o644))
+
+	code, stdout, _ = runCommand("", "filter", "-exclude-extension", "x-internal=true", internal)
+	assert.Equal(t, ExitOK, code)
+	assert.Equal(t, []string{"/pets"}, slices.Sorted(maps.Keys(decodeOpenAPI(t, stdout).Paths.Paths)))
+
+	code, stdout, _ = runCommand("", "filter", "-extension", "x-internal", internal)
+	assert.Equal(t, ExitOK, code)
+	assert.Equal(t, []string{"/admin"}, slices.Sorted(maps.Keys(decodeOpenAPI(t, stdout).Paths.Paths)))
+}
+
+func TestMerge(t *testing.T) {
+	dir := t.TempDir()
+
+	other := filepath.Join(dir, "other.json")
+	assert.NoError(t, os.WriteFile(other, []byte(`{"openapi": "3.0.3", "info": {"title": "other", "version": "1"},
+		"tags": [{"name": "pets"}, {"name": "stores"}],
+		"paths": {"/stores": {"get": {"operationId": "listStores", "responses": {"200": {"description": "ok"}}}}},
+		"components": {"schemas": {"Store": {"type": "object"}, "Status": {"type": "string", "description": "Status of the pet", "enum": ["available", "sold"]}}}}`), 0o644))
+
+	code, stdout, _ := runCommand("", "merge", "testdata/petstore.yaml", other)
+	assert.Equal(t, ExitOK, code)
+
+	openapi := decodeOpenAPI(t, stdout)
+	assert.Equal(t, "Petstore", openapi.Title)
+	assert.Equal(t, []string{"/pets", "/pets/{petId}", "/stores"}, slices.Sorted(maps.Keys(openapi.Paths.Paths)))
+	assert.Len(t, openapi.Tags, 2)
+	assert.NotNil(t, openapi.Schemas["Store"])
+
+	conflicted := filepath.Join(dir, "conflicted.json")
+	assert.NoError(t, os.WriteFile(conflicted, []byte(`{"openapi": "3.0.3", "info": {"title": "other", "version": "1"},
+		"paths": {"/pets": {"get": {"operationId": "listAllPets", "responses": {"200": {"description": "ok"}}}}},
+		"components": {"schemas": {"Pet": {"type": "object"}}}}`), 0o644))
+
+	code, _, stderr := runCommand("", "merge", "testdata/petstore.yaml", conflicted)
+	assert.Equal(t, ExitError, code)
+	assert.Contains(t, stderr, "/paths/~1pets/get")
+	assert.Contains(t, stderr, "/components/schemas/Pet")
+
+	renamed := filepath.Join(dir, "renamed.json")
+	assert.NoError(t, os.WriteFile(renamed, []byte(`{"openapi": "3.0.3", "info": {"title": "other", "version": "1"},
+		"paths": {"/others": {"get": {"operationId": "listOthers", "responses": {"200": {"description": "ok",
+			"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}}}},
+		"components": {"schemas": {"Pet": {"type": "object"}}}}`), 0o644))
+
+	code, stdout, _ = runCommand("", "merge", "-collision", "rename", "testdata/petstore.yaml", renamed)
+	assert.Equal(t, ExitOK, code)
+
+	openapi = decodeOpenAPI(t, stdout)
+	assert.NotNil(t, openapi.Schemas["Pet2"])
+	schema := openapi.Paths.Paths["/others"].Operations.Operations[oas.GET].Responses.Responses[200].Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/Pet2", schema.Refer.RefString())
+
+	code, _, _ = runCommand("", "merge", "-collision", "unknown", "testdata/petstore.yaml", renamed)
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestDocs(t *testing.T) {
+	code, stdout, _ := runCommand("", "docs", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "# Petstore (1.0.0)")
+	assert.Contains(t, stdout, "GET /pets/{petId}")
+}
+
+func TestNormalize(t *testing.T) {
+	doc := `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {},
+		"components": {"schemas": {
+			"Base": {"type": "object", "properties": {"id": {"type": "integer"}}},
+			"Pet": {"allOf": [{"$ref": "#/components/schemas/Base"}, {"type": "object", "properties": {"name": {"type": "string"}}}]}
+		}}}`
+
+	code, stdout, _ := runCommand(doc, "normalize", "-")
+	assert.Equal(t, ExitOK, code)
+
+	pet := decodeOpenAPI(t, stdout).Schemas["Pet"]
+	assert.Empty(t, pet.AllOf)
+	assert.Equal(t, []string{"id", "name"}, slices.Sorted(maps.Keys(pet.Properties)))
+
+	conflicted := `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {},
+		"components": {"schemas": {"Id": {"allOf": [{"type": "integer"}, {"type": "string"}]}}}}`
+
+	code, _, stderr := runCommand(conflicted, "normalize", "-")
+	assert.Equal(t, ExitError, code)
+	assert.Contains(t, stderr, "/components/schemas/Id/allOf/1/type: type string conflicts with integer")
+}
+
+func TestExtract(t *testing.T) {
+	doc := `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {},
+		"components": {"schemas": {
+			"User": {"type": "object", "properties": {"address": {"type": "object", "properties": {"city": {"type": "string"}}}}},
+			"Company": {"type": "object", "properties": {"address": {"type": "object", "properties": {"city": {"type": "string"}}}}}
+		}}}`
+
+	code, stdout, stderr := runCommand(doc, "extract", "-")
+	assert.Equal(t, ExitOK, code)
+	assert.Equal(t, "#/components/schemas/Address: 2 occurrences replaced\n", stderr)
+
+	openapi := decodeOpenAPI(t, stdout)
+	assert.Equal(t, "#/components/schemas/Address", openapi.Schemas["User"].Properties["address"].Refer.RefString())
+
+	code, _, _ = runCommand(doc, "extract", "-min", "0", "-")
+	assert.Equal(t, ExitUsage, code)
+}
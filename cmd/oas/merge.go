@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("merge", "merge paths, components, tags and servers of documents", runMerge)
+}
+
+var collisionPolicies = map[string]oas.CollisionPolicy{
+	"report": oas.CollisionReport,
+	"prefix": oas.CollisionPrefix,
+	"rename": oas.CollisionRename,
+}
+
+var extensionPolicies = map[string]oas.ExtensionPolicy{
+	"first":    oas.ExtensionKeepFirst,
+	"override": oas.ExtensionOverride,
+	"report":   oas.ExtensionReport,
+}
+
+func runMerge(e *env, args []string) error {
+	fs := newFlagSet(e, "merge", "[flags] <file> <file>...")
+	o := &output{}
+	o.register(fs)
+	collision := fs.String("collision", "report", "resolve components with same name but different values by report, prefix or rename")
+	extensions := fs.String("extensions", "first", "merge values of same extension by first, override or report")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return usagef("expect at least 2 files, but got %d", fs.NArg())
+	}
+
+	m := &oas.Merger{}
+	ok := false
+	if m.Collision, ok = collisionPolicies[*collision]; !ok {
+		return usagef("unsupported collision policy %q", *collision)
+	}
+	if m.Extensions, ok = extensionPolicies[*extensions]; !ok {
+		return usagef("unsupported extensions policy %q", *extensions)
+	}
+
+	docs := make([]*oas.OpenAPI, fs.NArg())
+	for i, path := range fs.Args() {
+		openapi, err := loadOpenAPI(e, path)
+		if err != nil {
+			return err
+		}
+		docs[i] = openapi
+	}
+
+	merged, err := m.Merge(docs...)
+	if err != nil {
+		conflicts := oas.MergeConflicts{}
+		if !errors.As(err, &conflicts) {
+			return err
+		}
+		messages := make([]string, len(conflicts))
+		for i, c := range conflicts {
+			messages[i] = fmt.Sprintf("%s: %s: %s", fs.Arg(c.Document), c.Pointer, c.Message)
+		}
+		return fmt.Errorf("conflicts:\n%s", strings.Join(messages, "\n"))
+	}
+
+	return o.write(e, merged)
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("mock", "serve responses by examples or schemas of the document", runMock)
+}
+
+func runMock(e *env, args []string) error {
+	fs := newFlagSet(e, "mock", "[flags] <file>")
+	addr := fs.String("addr", "localhost:8080", "address to listen")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	openapi, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stderr, "oas mock: serving %s on http://%s\n", fs.Arg(0), *addr)
+
+	return http.ListenAndServe(*addr, &mockHandler{openapi: openapi})
+}
+
+// mockHandler responds operations matched, status code could be picked by header "Prefer: code=404".
+// json request bodies are validated by schemas.
+type mockHandler struct {
+	openapi *oas.OpenAPI
+}
+
+func (h *mockHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	op, tpl := h.findOperation(req)
+	if op == nil {
+		if tpl != "" {
+			writeMockError(rw, http.StatusMethodNotAllowed, "method %s not allowed", req.Method)
+			return
+		}
+		writeMockError(rw, http.StatusNotFound, "no operation matched %s %s", req.Method, req.URL.Path)
+		return
+	}
+
+	if err := h.validateRequestBody(req, op); err != nil {
+		writeMockError(rw, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	status, r := h.pickResponse(req, op)
+	if r == nil {
+		writeMockError(rw, http.StatusNotImplemented, "no response of %s defined", tpl)
+		return
+	}
+
+	contentType, mt := pickContent(req.Header.Get("Accept"), r.Content)
+	if mt == nil {
+		rw.WriteHeader(status)
+		return
+	}
+
+	body := h.sample(mt)
+
+	rw.Header().Set("Content-Type", contentType)
+	rw.WriteHeader(status)
+
+	if s, ok := body.(string); ok && !isJSONContentType(contentType) {
+		_, _ = rw.Write([]byte(s))
+		return
+	}
+	_ = json.NewEncoder(rw).Encode(body)
+}
+
+// findOperation returns operation and the path template matched, path prefixes of servers stripped if needed
+func (h *mockHandler) findOperation(req *http.Request) (*oas.Operation, string) {
+	candidates := []string{req.URL.Path}
+	for _, s := range h.openapi.Servers {
+		if s == nil {
+			continue
+		}
+		u, err := s.Expand(nil)
+		if err != nil || u.Path == "" || u.Path == "/" {
+			continue
+		}
+		if p := strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(u.Path, "/")); p != req.URL.Path {
+			candidates = append(candidates, p)
+		}
+	}
+
+	tpl := ""
+	for _, p := range candidates {
+		op, matched, _ := h.openapi.Paths.FindOperation(req.Method, p)
+		if op != nil {
+			return op, matched
+		}
+		if _, _, ok := h.openapi.Paths.MatchPath(p); ok {
+			tpl = p
+		}
+	}
+	return nil, tpl
+}
+
+func (h *mockHandler) validateRequestBody(req *http.Request, op *oas.Operation) error {
+	rb := h.openapi.ResolveRequestBody(op.RequestBody)
+	if rb == nil {
+		return nil
+	}
+
+	if req.ContentLength == 0 && req.Body == http.NoBody {
+		if rb.Required {
+			return errors.New("request body required")
+		}
+		return nil
+	}
+
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	mt, ok := rb.Content[contentType]
+	if !ok {
+		return fmt.Errorf("content type %q not supported", contentType)
+	}
+	if mt == nil || mt.Schema == nil || !isJSONContentType(contentType) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.NewDecoder(req.Body).Decode(&value); err != nil {
+		return fmt.Errorf("invalid json: %w", err)
+	}
+	return h.openapi.ValidateValue(mt.Schema, value)
+}
+
+// pickResponse picks the response of preferred status code, or the first success one
+func (h *mockHandler) pickResponse(req *http.Request, op *oas.Operation) (int, *oas.Response) {
+	responses := &op.Responses.ResponsesObject
+
+	for _, pref := range strings.Split(req.Header.Get("Prefer"), ",") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(pref), "code="); ok {
+			if status, err := strconv.Atoi(v); err == nil {
+				return status, h.openapi.ResolveResponse(responses.ResponseFor(status))
+			}
+		}
+	}
+
+	statuses := make([]int, 0, len(responses.Responses))
+	for status := range responses.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	for _, status := range statuses {
+		if status >= 200 && status < 300 {
+			return status, h.openapi.ResolveResponse(responses.Responses[status])
+		}
+	}
+	if r := responses.Ranges[2]; r != nil {
+		return http.StatusOK, h.openapi.ResolveResponse(r)
+	}
+	if responses.Default != nil {
+		return http.StatusOK, h.openapi.ResolveResponse(responses.Default)
+	}
+	if len(statuses) > 0 {
+		return statuses[0], h.openapi.ResolveResponse(responses.Responses[statuses[0]])
+	}
+	return 0, nil
+}
+
+// pickContent picks the media type accepted, json preferred
+func pickContent(accept string, content map[string]*oas.MediaType) (string, *oas.MediaType) {
+	if len(content) == 0 {
+		return "", nil
+	}
+
+	contentTypes := slices.Sorted(maps.Keys(content))
+
+	for _, part := range strings.Split(accept, ",") {
+		accepted, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || accepted == "*/*" {
+			continue
+		}
+		for _, ct := range contentTypes {
+			if ct == accepted || (strings.HasSuffix(accepted, "/*") && strings.HasPrefix(ct, strings.TrimSuffix(accepted, "*"))) {
+				return ct, content[ct]
+			}
+		}
+	}
+
+	for _, ct := range contentTypes {
+		if isJSONContentType(ct) {
+			return ct, content[ct]
+		}
+	}
+	return contentTypes[0], content[contentTypes[0]]
+}
+
+func isJSONContentType(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+func (h *mockHandler) sample(mt *oas.MediaType) interface{} {
+	if mt.Example.Present {
+		return mt.Example.Value
+	}
+	for _, name := range slices.Sorted(maps.Keys(mt.Examples)) {
+		if e := h.openapi.ResolveExample(mt.Examples[name]); e != nil && e.Value.Present {
+			return e.Value.Value
+		}
+	}
+	return sampleValue(&h.openapi.ComponentsObject, mt.Schema, nil)
+}
+
+// sampleValue generates value of the schema, by example, default, enum or type.
+// refs expanding kept to stop at circular refs.
+func sampleValue(components *oas.ComponentsObject, s *oas.Schema, expanding []string) interface{} {
+	if s == nil {
+		return nil
+	}
+
+	if s.Refer != nil {
+		ref := s.Refer.RefString()
+		for _, r := range expanding {
+			if r == ref {
+				return nil
+			}
+		}
+		return sampleValue(components, components.ResolveSchema(s), append(expanding[:len(expanding):len(expanding)], ref))
+	}
+
+	switch {
+	case s.Example.Present:
+		return s.Example.Value
+	case s.Default.Present:
+		return s.Default.Value
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	case len(s.AllOf) > 0:
+		merged := map[string]interface{}{}
+		for _, sub := range s.AllOf {
+			if m, ok := sampleValue(components, sub, expanding).(map[string]interface{}); ok {
+				for k := range m {
+					merged[k] = m[k]
+				}
+			}
+		}
+		if props, ok := sampleValue(components, &oas.Schema{SchemaObject: oas.SchemaObject{Type: oas.TypeObject, Properties: s.Properties}}, expanding).(map[string]interface{}); ok {
+			for k := range props {
+				merged[k] = props[k]
+			}
+		}
+		return merged
+	case len(s.OneOf) > 0:
+		return sampleValue(components, s.OneOf[0], expanding)
+	case len(s.AnyOf) > 0:
+		return sampleValue(components, s.AnyOf[0], expanding)
+	}
+
+	switch s.Type {
+	case oas.TypeString:
+		return sampleString(s.Format)
+	case oas.TypeInteger, oas.TypeNumber:
+		if s.Minimum != nil {
+			return *s.Minimum
+		}
+		return 0
+	case oas.TypeBoolean:
+		return true
+	case oas.TypeArray:
+		if s.Items == nil {
+			return []interface{}{}
+		}
+		return []interface{}{sampleValue(components, s.Items, expanding)}
+	}
+
+	if s.Type == oas.TypeObject || len(s.Properties) > 0 || s.AdditionalProperties != nil {
+		obj := map[string]interface{}{}
+		for name, prop := range s.Properties {
+			if prop != nil && prop.WriteOnly {
+				continue
+			}
+			obj[name] = sampleValue(components, prop, expanding)
+		}
+		return obj
+	}
+
+	return nil
+}
+
+var sampleStrings = map[string]string{
+	"date":      "2006-01-02",
+	"date-time": "2006-01-02T15:04:05Z",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c3RyaW5n",
+}
+
+func sampleString(format string) string {
+	if s, ok := sampleStrings[format]; ok {
+		return s
+	}
+	return "string"
+}
+
+func writeMockError(rw http.ResponseWriter, status int, format string, args ...interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(map[string]string{"error": fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockHandler(t *testing.T) {
+	e := &env{}
+	openapi, err := loadOpenAPI(e, "testdata/petstore.yaml")
+	assert.NoError(t, err)
+
+	h := &mockHandler{openapi: openapi}
+
+	serve := func(method string, target string, body string, header http.Header) (*http.Response, interface{}) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if body == "" {
+			req = httptest.NewRequest(method, target, nil)
+		}
+		for k := range header {
+			req.Header[k] = header[k]
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		var v interface{}
+		_ = json.Unmarshal(rw.Body.Bytes(), &v)
+		return rw.Result(), v
+	}
+
+	t.Run("example", func(t *testing.T) {
+		resp, v := serve(http.MethodGet, "/pets/1", "", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, map[string]interface{}{"id": 1.0, "name": "doggie", "status": "available"}, v)
+	})
+
+	t.Run("generated by schema with base path of server", func(t *testing.T) {
+		resp, v := serve(http.MethodGet, "/v1/pets", "", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []interface{}{map[string]interface{}{"id": 0.0, "name": "string", "status": "available", "tag": "string"}}, v)
+	})
+
+	t.Run("preferred status", func(t *testing.T) {
+		resp, v := serve(http.MethodGet, "/pets/1", "", http.Header{"Prefer": {"code=500"}})
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, map[string]interface{}{"code": 0.0, "message": "string"}, v)
+	})
+
+	t.Run("request body validated", func(t *testing.T) {
+		resp, _ := serve(http.MethodPost, "/pets", `{"name": "kitty"}`, http.Header{"Content-Type": {"application/json"}})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp, v := serve(http.MethodPost, "/pets", `{"tag": "cat"}`, http.Header{"Content-Type": {"application/json"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, v.(map[string]interface{})["error"], "name")
+
+		resp, _ = serve(http.MethodPost, "/pets", "", nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		resp, _ := serve(http.MethodDelete, "/pets/1", "", nil)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("not matched", func(t *testing.T) {
+		resp, _ := serve(http.MethodGet, "/stores", "", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, _ = serve(http.MethodPatch, "/pets/1", "", nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-courier/oas"
+)
+
+func init() {
+	register("normalize", "flatten allOf and simplify schemas", runNormalize)
+}
+
+func runNormalize(e *env, args []string) error {
+	fs := newFlagSet(e, "normalize", "[flags] <file>")
+	o := &output{}
+	o.register(fs)
+	inlineRefs := fs.Bool("inline-refs", false, "inline refs to trivial schemas")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	openapi, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	normalized, err := (&oas.Normalizer{InlineRefs: *inlineRefs}).Normalize(openapi)
+	if err != nil {
+		conflicts := oas.SchemaConflicts{}
+		if !errors.As(err, &conflicts) {
+			return err
+		}
+		return fmt.Errorf("conflicts:\n%s", conflicts.Error())
+	}
+
+	return o.write(e, normalized)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+// helpers of refs of typed documents, objects hold refs found by reflection,
+// since the walker of the library is not exported
+
+var (
+	typeReference      = reflect.TypeOf(oas.Reference{})
+	typeDiscriminator  = reflect.TypeOf(oas.Discriminator{})
+	typeSpecExtensions = reflect.TypeOf(oas.SpecExtensions{})
+	typeAny            = reflect.TypeOf(oas.Any{})
+
+	// groupOfType maps types of components like *oas.Schema to their groups
+	groupOfType = map[reflect.Type]string{}
+)
+
+func init() {
+	for group, m := range componentMaps(&oas.ComponentsObject{}) {
+		groupOfType[m.Type().Elem()] = group
+	}
+}
+
+// refFunc is called with each ref and the object holds it, like *oas.Schema.
+// The owner could be replaced, it is invalid for refs of discriminator mapping.
+type refFunc func(group string, ref *oas.Reference, owner reflect.Value)
+
+// visitRefs calls fn with refs of v in stable order, refs are not followed
+func visitRefs(v reflect.Value, fn refFunc) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			visitRefs(v.Elem(), fn)
+		}
+	case reflect.Struct:
+		switch v.Type() {
+		case typeSpecExtensions, typeAny:
+			return
+		case typeDiscriminator:
+			mapping := v.FieldByName("Mapping").Interface().(map[string]string)
+			for _, value := range slices.Sorted(maps.Keys(mapping)) {
+				ref := &oas.Reference{Refer: &oas.StringRefer{Ref: mapping[value]}}
+				fn("schemas", ref, reflect.Value{})
+				mapping[value] = ref.Refer.RefString()
+			}
+			return
+		}
+		if ref := referenceOf(v); ref != nil && ref.Refer != nil {
+			fn(groupOfType[v.Addr().Type()], ref, v.Addr())
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				visitRefs(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			visitRefs(v.Index(i), fn)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, k := range keys {
+			visitRefs(v.MapIndex(k), fn)
+		}
+	}
+}
+
+// referenceOf returns the embedded oas.Reference of the addressable struct, nil when not embedded
+func referenceOf(v reflect.Value) *oas.Reference {
+	if !v.CanAddr() {
+		return nil
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Type().Field(i); f.Anonymous && f.Type == typeReference {
+			return v.Field(i).Addr().Interface().(*oas.Reference)
+		}
+	}
+	return nil
+}
+
+// componentMaps returns maps of components holding refs by groups, like Schemas of schemas
+func componentMaps(c *oas.ComponentsObject) map[string]reflect.Value {
+	maps := map[string]reflect.Value{}
+
+	var collect func(v reflect.Value)
+	collect = func(v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			switch {
+			case f.Anonymous && f.Type.Kind() == reflect.Struct:
+				collect(v.Field(i))
+			case f.Type.Kind() == reflect.Map && f.Type.Elem().Kind() == reflect.Ptr && referenceOf(reflect.New(f.Type.Elem().Elem()).Elem()) != nil:
+				group, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+				maps[group] = v.Field(i)
+			}
+		}
+	}
+	collect(reflect.ValueOf(c).Elem())
+
+	return maps
+}
+
+// componentNames returns sorted names of components of the group
+func componentNames(c *oas.ComponentsObject, group string) []string {
+	names := make([]string, 0)
+	if m, ok := componentMaps(c)[group]; ok {
+		for _, k := range m.MapKeys() {
+			names = append(names, k.String())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// component returns the component of the group, like *oas.Schema, invalid when not found
+func component(c *oas.ComponentsObject, group string, name string) reflect.Value {
+	m, ok := componentMaps(c)[group]
+	if !ok {
+		return reflect.Value{}
+	}
+	v := m.MapIndex(reflect.ValueOf(name))
+	if !v.IsValid() || v.IsNil() {
+		return reflect.Value{}
+	}
+	return v
+}
+
+// setComponent sets the component, value should be in type of the group
+func setComponent(c *oas.ComponentsObject, group string, name string, value reflect.Value) {
+	m := componentMaps(c)[group]
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	m.SetMapIndex(reflect.ValueOf(name), value)
+}
+
+// newComponent returns empty component of the group, like &oas.Schema{}
+func newComponent(group string) reflect.Value {
+	for t, g := range groupOfType {
+		if g == group {
+			return reflect.New(t.Elem())
+		}
+	}
+	return reflect.New(reflect.TypeOf(oas.Schema{}))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Finding is reported by validate, lint and diff
+type Finding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule,omitempty"`
+	Pointer  string `json:"pointer"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (f *Finding) String() string {
+	location := f.Pointer
+	if location == "" {
+		location = "/"
+	}
+	if f.Line > 0 {
+		location = fmt.Sprintf("%d:%d %s", f.Line, f.Column, location)
+	}
+	rule := ""
+	if f.Rule != "" {
+		rule = " [" + f.Rule + "]"
+	}
+	return fmt.Sprintf("%s: %s: %s%s", f.Severity, location, f.Message, rule)
+}
+
+type report struct {
+	format string
+	// strict fails on warnings too
+	strict bool
+}
+
+func (r *report) register(fs *flag.FlagSet, withStrict bool) {
+	fs.StringVar(&r.format, "format", "text", "report format text or json")
+	if withStrict {
+		fs.BoolVar(&r.strict, "strict", false, "exit with findings code on warnings too")
+	}
+}
+
+// write writes findings, returns errFindings when any of them fails the command
+func (r *report) write(e *env, findings []*Finding) error {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Pointer < findings[j].Pointer
+	})
+
+	switch r.format {
+	case "json":
+		if findings == nil {
+			findings = []*Finding{}
+		}
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return err
+		}
+	case "text":
+		for _, f := range findings {
+			fmt.Fprintln(e.stdout, f.String())
+		}
+	default:
+		return usagef("unsupported format %q", r.format)
+	}
+
+	for _, f := range findings {
+		if f.Severity == SeverityError || r.strict {
+			return errFindings
+		}
+	}
+	return nil
+}
@@ -0,0 +1,757 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+// swagger 2.0 is not modeled by oas, so it is read and written as json values.
+// parts in the same shape of openapi 3.0, like info, schemas and operations, are decoded from or encoded by the model directly.
+
+var swagger2Refs = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/responses/":   "#/components/responses/",
+	"#/parameters/":  "#/components/parameters/",
+}
+
+// schemaFields are fields of swagger 2.0 non-body parameters and headers moved into schema
+var schemaFields = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+var collectionFormatStyles = map[string]oas.ParameterStyle{
+	"csv":   oas.ParameterStyleForm,
+	"ssv":   oas.ParameterStyleSpaceDelimited,
+	"pipes": oas.ParameterStylePipeDelimited,
+	"multi": oas.ParameterStyleForm,
+}
+
+// oauth2Flows are swagger 2.0 flows, in the order to pick one from openapi 3.0 flows
+var oauth2Flows = []string{"accessCode", "implicit", "password", "application"}
+
+func (c *converter) fromSwagger2(root map[string]interface{}) (*oas.OpenAPI, error) {
+	consumes := stringList(root["consumes"])
+	produces := stringList(root["produces"])
+
+	refs := map[string]string{}
+	for from, to := range swagger2Refs {
+		refs[from] = to
+	}
+	for _, name := range slices.Sorted(maps.Keys(object(root, "parameters"))) {
+		if in := object(root, "parameters", name)["in"]; in == "body" || in == "formData" {
+			refs["#/parameters/"+escapePointer(name)] = "#/components/requestBodies/" + escapePointer(name)
+		}
+	}
+	rewriteRefs(root, refs)
+
+	walkSchemas(root, func(schema map[string]interface{}) {
+		if nullable, ok := schema["x-nullable"]; ok {
+			schema["nullable"] = nullable
+			delete(schema, "x-nullable")
+		}
+		if schema["type"] == "file" {
+			schema["type"] = "string"
+			schema["format"] = "binary"
+		}
+	})
+
+	openapi := oas.NewOpenAPI()
+	if err := decodeValue(fieldsOf(root, "info", "tags", "security"), openapi); err != nil {
+		return nil, err
+	}
+
+	for _, u := range swagger2Servers(root) {
+		openapi.AddServer(oas.NewServer(u))
+	}
+
+	definitions := object(root, "definitions")
+	for _, name := range slices.Sorted(maps.Keys(definitions)) {
+		s := &oas.Schema{}
+		if err := decodeValue(definitions[name], s); err != nil {
+			return nil, err
+		}
+		openapi.AddSchema(name, s)
+	}
+
+	parameters := object(root, "parameters")
+	for _, name := range slices.Sorted(maps.Keys(parameters)) {
+		p := object(parameters[name])
+		if p["in"] == "body" || p["in"] == "formData" {
+			openapi.AddRequestBody(name, c.swagger2RequestBody([]map[string]interface{}{p}, consumes))
+			continue
+		}
+		openapi.AddParameter(name, c.swagger2Parameter(p))
+	}
+
+	responses := object(root, "responses")
+	for _, name := range slices.Sorted(maps.Keys(responses)) {
+		openapi.AddResponse(name, c.swagger2Response(object(responses[name]), produces))
+	}
+
+	securityDefinitions := object(root, "securityDefinitions")
+	for _, name := range slices.Sorted(maps.Keys(securityDefinitions)) {
+		openapi.AddSecurityScheme(name, c.swagger2SecurityScheme(name, object(securityDefinitions[name])))
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(object(root, "paths"))) {
+		raw := object(root, "paths", path)
+
+		item := &oas.PathItem{}
+		if err := decodeValue(fieldsOf(raw), &item.SpecExtensions); err != nil {
+			return nil, err
+		}
+		// body and form parameters of path item merged into operations
+		params, pathBodyParams := c.swagger2SplitParameters(raw["parameters"])
+		item.Parameters = params
+		openapi.Paths.Paths[path] = item
+
+		for _, method := range methodOrder {
+			rawOp := object(raw, string(method))
+			if rawOp == nil {
+				continue
+			}
+
+			op := &oas.Operation{}
+			if err := decodeValue(fieldsOf(rawOp, "tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security"), op); err != nil {
+				return nil, err
+			}
+
+			opConsumes, opProduces := consumes, produces
+			if v, ok := rawOp["consumes"]; ok {
+				opConsumes = stringList(v)
+			}
+			if v, ok := rawOp["produces"]; ok {
+				opProduces = stringList(v)
+			}
+
+			params, bodyParams := c.swagger2SplitParameters(rawOp["parameters"])
+			bodyParams = append(pathBodyParams[:len(pathBodyParams):len(pathBodyParams)], bodyParams...)
+			op.Parameters = params
+
+			if len(bodyParams) == 1 && bodyParams[0]["$ref"] != nil {
+				rb := &oas.RequestBody{}
+				if err := decodeValue(bodyParams[0], rb); err != nil {
+					return nil, err
+				}
+				op.SetRequestBody(rb)
+			} else if len(bodyParams) > 0 {
+				op.SetRequestBody(c.swagger2RequestBody(bodyParams, opConsumes))
+			}
+
+			for _, status := range slices.Sorted(maps.Keys(object(rawOp, "responses"))) {
+				r := c.swagger2Response(object(rawOp, "responses", status), opProduces)
+				if status == "default" {
+					op.SetDefaultResponse(r)
+				} else if code, err := strconv.Atoi(status); err == nil {
+					op.AddResponse(code, r)
+				}
+			}
+
+			item.AddOperation(method, op)
+		}
+	}
+
+	return openapi, nil
+}
+
+func swagger2Servers(root map[string]interface{}) []string {
+	host, _ := root["host"].(string)
+	basePath, _ := root["basePath"].(string)
+	schemes := stringList(root["schemes"])
+
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []string{basePath}
+	}
+
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+
+	servers := make([]string, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, scheme+"://"+host+basePath)
+	}
+	return servers
+}
+
+// swagger2SplitParameters returns converted parameters, and body or form parameters for request body
+func (c *converter) swagger2SplitParameters(v interface{}) ([]*oas.Parameter, []map[string]interface{}) {
+	list, _ := v.([]interface{})
+
+	params := make([]*oas.Parameter, 0, len(list))
+	bodyParams := make([]map[string]interface{}, 0)
+
+	for _, item := range list {
+		p := object(item)
+		ref, _ := p["$ref"].(string)
+		if strings.HasPrefix(ref, "#/components/requestBodies/") || p["in"] == "body" || p["in"] == "formData" {
+			bodyParams = append(bodyParams, p)
+			continue
+		}
+		params = append(params, c.swagger2Parameter(p))
+	}
+
+	if len(params) == 0 {
+		params = nil
+	}
+	return params, bodyParams
+}
+
+func (c *converter) swagger2Parameter(p map[string]interface{}) *oas.Parameter {
+	param := &oas.Parameter{}
+	if _, ok := p["$ref"]; ok {
+		_ = decodeValue(p, param)
+		return param
+	}
+
+	_ = decodeValue(fieldsOf(p, "name", "in", "description", "required", "allowEmptyValue"), param)
+	param.Schema = swagger2Schema(p)
+
+	if format, ok := p["collectionFormat"].(string); ok {
+		if param.In == oas.PositionQuery {
+			if style, ok := collectionFormatStyles[format]; ok {
+				param = param.WithStyle(style, format == "multi")
+			}
+		} else if format != "csv" {
+			c.warn("collectionFormat %s of %s parameter %s not supported, dropped", format, param.In, param.Name)
+		}
+	}
+
+	if param.In != oas.PositionQuery {
+		param.AllowEmptyValue = false
+	}
+
+	return param
+}
+
+// swagger2Schema returns schema of fields of non-body parameter or header, nil when no fields
+func swagger2Schema(p map[string]interface{}) *oas.Schema {
+	fields := map[string]interface{}{}
+	moveFields(p, fields, schemaFields)
+	if len(fields) == 0 {
+		return nil
+	}
+	if fields["type"] == "file" {
+		fields["type"] = "string"
+		fields["format"] = "binary"
+	}
+	s := &oas.Schema{}
+	_ = decodeValue(fields, s)
+	return s
+}
+
+func (c *converter) swagger2RequestBody(params []map[string]interface{}, consumes []string) *oas.RequestBody {
+	props := oas.Props{}
+	required := make([]string, 0)
+	hasFile := false
+
+	for _, p := range params {
+		if ref, ok := p["$ref"].(string); ok {
+			c.warn("ref %s merged into request body could not be kept, dropped", ref)
+			continue
+		}
+
+		desc, _ := p["description"].(string)
+		isRequired, _ := p["required"].(bool)
+
+		if p["in"] == "body" {
+			types := consumes
+			if len(types) == 0 {
+				types = []string{"application/json"}
+			}
+			s := &oas.Schema{}
+			_ = decodeValue(p["schema"], s)
+			rb := oas.NewRequestBody(desc, isRequired)
+			for _, ct := range types {
+				rb = rb.WithSchema(ct, s)
+			}
+			return rb
+		}
+
+		name, _ := p["name"].(string)
+		hasFile = hasFile || p["type"] == "file"
+		s := swagger2Schema(p)
+		if s == nil {
+			s = &oas.Schema{}
+		}
+		if desc != "" {
+			s = s.WithDesc(desc)
+		}
+		props[name] = s
+		if isRequired {
+			required = append(required, name)
+		}
+	}
+
+	types := make([]string, 0)
+	for _, ct := range consumes {
+		if ct == "application/x-www-form-urlencoded" || ct == "multipart/form-data" {
+			types = append(types, ct)
+		}
+	}
+	if len(types) == 0 {
+		if hasFile {
+			types = []string{"multipart/form-data"}
+		} else {
+			types = []string{"application/x-www-form-urlencoded"}
+		}
+	}
+
+	rb := oas.NewRequestBody("", len(required) > 0)
+	for _, ct := range types {
+		rb = rb.WithSchema(ct, oas.ObjectOf(props, required...))
+	}
+	return rb
+}
+
+func (c *converter) swagger2Response(r map[string]interface{}, produces []string) *oas.Response {
+	if _, ok := r["$ref"]; ok {
+		resp := &oas.Response{}
+		_ = decodeValue(r, resp)
+		return resp
+	}
+
+	desc, _ := r["description"].(string)
+	resp := oas.NewResponse(desc)
+	_ = decodeValue(fieldsOf(r), &resp.SpecExtensions)
+
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+
+	if schema, ok := r["schema"]; ok {
+		s := &oas.Schema{}
+		_ = decodeValue(schema, s)
+		examples := object(r, "examples")
+		for _, ct := range produces {
+			mt := oas.NewMediaTypeWithSchema(s)
+			if example, ok := examples[ct]; ok {
+				mt = mt.WithExample(example)
+			}
+			resp = resp.WithContentOf(ct, mt)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(object(r, "headers"))) {
+		h := object(r, "headers", name)
+		header := oas.NewHeaderWithSchema(swagger2Schema(h))
+		if d, ok := h["description"].(string); ok {
+			header = header.WithDesc(d)
+		}
+		resp.AddHeader(name, header)
+	}
+
+	return resp
+}
+
+func (c *converter) swagger2SecurityScheme(name string, d map[string]interface{}) *oas.SecurityScheme {
+	var scheme *oas.SecurityScheme
+
+	switch d["type"] {
+	case "basic":
+		scheme = oas.NewHTTPSecurityScheme("basic", "")
+	case "apiKey":
+		key, _ := d["name"].(string)
+		in, _ := d["in"].(string)
+		scheme = oas.NewAPIKeySecurityScheme(key, oas.Position(in))
+	case "oauth2":
+		authorizationURL, _ := d["authorizationUrl"].(string)
+		tokenURL, _ := d["tokenUrl"].(string)
+		scopes := map[string]string{}
+		_ = decodeValue(d["scopes"], &scopes)
+		flow := oas.NewOAuthFlow(authorizationURL, tokenURL, "", scopes)
+
+		flows := oas.OAuthFlowsObject{}
+		switch d["flow"] {
+		case "implicit":
+			flows.Implicit = flow
+		case "password":
+			flows.Password = flow
+		case "application":
+			flows.ClientCredentials = flow
+		default:
+			flows.AuthorizationCode = flow
+		}
+		scheme = oas.NewOAuth2SecurityScheme(flows)
+	default:
+		c.warn("security definition %s of type %v not supported, dropped", name, d["type"])
+		return nil
+	}
+
+	if desc, ok := d["description"].(string); ok {
+		scheme.Description = desc
+	}
+	_ = decodeValue(fieldsOf(d), &scheme.SpecExtensions)
+	return scheme
+}
+
+func (c *converter) toSwagger2(openapi *oas.OpenAPI) map[string]interface{} {
+	root := fieldsOf(jsonObject(openapi), "info", "tags", "security")
+	root["swagger"] = "2.0"
+
+	schemes := make([]string, 0)
+	for i, s := range openapi.Servers {
+		if s == nil {
+			continue
+		}
+		if len(s.Variables) > 0 {
+			c.warn("variables of server %s not supported, defaults used", s.URL)
+		}
+		u, err := s.Expand(nil)
+		if err != nil {
+			c.warn("invalid server url %s", s.URL)
+			continue
+		}
+		if i == 0 {
+			if u.Host != "" {
+				root["host"] = u.Host
+			}
+			if u.Path != "" {
+				root["basePath"] = u.Path
+			}
+		}
+		if u.Scheme != "" && !slices.Contains(schemes, u.Scheme) {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+	if len(openapi.Servers) > 1 {
+		c.warn("only host and base path of the first server kept")
+	}
+	if len(schemes) > 0 {
+		sort.Strings(schemes)
+		root["schemes"] = anyList(schemes)
+	}
+
+	if len(openapi.Schemas) > 0 {
+		definitions := map[string]interface{}{}
+		for _, name := range slices.Sorted(maps.Keys(openapi.Schemas)) {
+			definitions[name] = jsonValue(openapi.Schemas[name])
+		}
+		root["definitions"] = definitions
+	}
+
+	if len(openapi.Parameters) > 0 {
+		parameters := map[string]interface{}{}
+		for _, name := range slices.Sorted(maps.Keys(openapi.Parameters)) {
+			parameters[name] = c.toSwagger2Parameter(openapi, openapi.Parameters[name])
+		}
+		root["parameters"] = parameters
+	}
+
+	if len(openapi.SecuritySchemes) > 0 {
+		definitions := map[string]interface{}{}
+		for _, name := range slices.Sorted(maps.Keys(openapi.SecuritySchemes)) {
+			if d := c.toSwagger2SecurityScheme(name, openapi.SecuritySchemes[name]); d != nil {
+				definitions[name] = d
+			}
+		}
+		root["securityDefinitions"] = definitions
+	}
+
+	unsupported := map[string]int{"examples": len(openapi.Examples), "headers": len(openapi.Headers), "links": len(openapi.Links), "callbacks": len(openapi.Callbacks)}
+	for _, group := range slices.Sorted(maps.Keys(unsupported)) {
+		if unsupported[group] > 0 {
+			c.warn("components %s not supported, dropped", group)
+		}
+	}
+
+	if len(openapi.Responses) > 0 {
+		responses := map[string]interface{}{}
+		for _, name := range slices.Sorted(maps.Keys(openapi.Responses)) {
+			responses[name] = c.toSwagger2Response(openapi, openapi.Responses[name], map[string]bool{})
+		}
+		root["responses"] = responses
+	}
+
+	paths := map[string]interface{}{}
+	for _, path := range slices.Sorted(maps.Keys(openapi.Paths.Paths)) {
+		item := openapi.Paths.Paths[path]
+		if item == nil {
+			continue
+		}
+		pathItem := fieldsOf(jsonObject(item))
+		if params := c.toSwagger2Parameters(openapi, item.Parameters); len(params) > 0 {
+			pathItem["parameters"] = params
+		}
+
+		for _, method := range methodOrder {
+			op := item.Operations.Operations[method]
+			if op == nil {
+				continue
+			}
+
+			o := fieldsOf(jsonObject(op), "tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security")
+			params := c.toSwagger2Parameters(openapi, op.Parameters)
+
+			if rb := openapi.ResolveRequestBody(op.RequestBody); rb != nil {
+				bodyParams, types := c.toSwagger2RequestBody(openapi, rb)
+				params = append(params, bodyParams...)
+				if len(types) > 0 {
+					o["consumes"] = anyList(types)
+				}
+			}
+			if len(params) > 0 {
+				o["parameters"] = params
+			}
+
+			produces := map[string]bool{}
+			responses := map[string]interface{}{}
+			if r := op.Responses.Default; r != nil {
+				responses["default"] = c.toSwagger2Response(openapi, r, produces)
+			}
+			for status, r := range op.Responses.Responses {
+				if r != nil {
+					responses[strconv.Itoa(status)] = c.toSwagger2Response(openapi, r, produces)
+				}
+			}
+			if len(op.Responses.Ranges) > 0 {
+				c.warn("status code ranges of responses of %s %s not supported, dropped", method, path)
+			}
+			o["responses"] = responses
+			if len(produces) > 0 {
+				o["produces"] = anyList(slices.Sorted(maps.Keys(produces)))
+			}
+
+			if len(op.Callbacks) > 0 {
+				c.warn("callbacks of %s %s not supported, dropped", method, path)
+			}
+
+			pathItem[string(method)] = o
+		}
+
+		paths[path] = pathItem
+	}
+	root["paths"] = paths
+
+	refs := map[string]string{}
+	for from, to := range swagger2Refs {
+		refs[to] = from
+	}
+	rewriteRefs(root, refs)
+
+	walkSchemas(root, func(schema map[string]interface{}) {
+		if nullable, ok := schema["nullable"]; ok {
+			schema["x-nullable"] = nullable
+			delete(schema, "nullable")
+		}
+		for _, k := range []string{"oneOf", "anyOf"} {
+			if _, ok := schema[k]; ok {
+				c.warn("%s not supported, dropped", k)
+				delete(schema, k)
+			}
+		}
+		delete(schema, "writeOnly")
+		delete(schema, "deprecated")
+	})
+
+	return root
+}
+
+func (c *converter) toSwagger2Parameters(openapi *oas.OpenAPI, params []*oas.Parameter) []interface{} {
+	list := make([]interface{}, 0, len(params))
+	for _, p := range params {
+		if p != nil {
+			list = append(list, c.toSwagger2Parameter(openapi, p))
+		}
+	}
+	return list
+}
+
+func (c *converter) toSwagger2Parameter(openapi *oas.OpenAPI, p *oas.Parameter) map[string]interface{} {
+	if p.Refer != nil {
+		return map[string]interface{}{"$ref": p.Refer.RefString()}
+	}
+
+	out := fieldsOf(jsonObject(p), "name", "in", "description", "required", "allowEmptyValue")
+
+	if s := p.Schema; s != nil {
+		if resolved := openapi.ResolveSchema(s); resolved != nil && resolved.Type != oas.TypeObject {
+			moveFields(jsonObject(resolved), out, schemaFields)
+		} else {
+			c.warn("schema of parameter %s could not be kept, type string used", p.Name)
+			out["type"] = "string"
+		}
+	}
+
+	if len(p.Content) > 0 {
+		c.warn("content of parameter %s not supported, type string used", p.Name)
+		out["type"] = "string"
+	}
+
+	if out["type"] == "array" {
+		switch p.StyleOrDefault() {
+		case oas.ParameterStyleForm:
+			if p.ExplodeOrDefault() {
+				out["collectionFormat"] = "multi"
+			} else {
+				out["collectionFormat"] = "csv"
+			}
+		case oas.ParameterStyleSpaceDelimited:
+			out["collectionFormat"] = "ssv"
+		case oas.ParameterStylePipeDelimited:
+			out["collectionFormat"] = "pipes"
+		default:
+			out["collectionFormat"] = "csv"
+		}
+	}
+
+	return out
+}
+
+func (c *converter) toSwagger2RequestBody(openapi *oas.OpenAPI, rb *oas.RequestBody) ([]interface{}, []string) {
+	types := slices.Sorted(maps.Keys(rb.Content))
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	for _, ct := range types {
+		if ct != "application/x-www-form-urlencoded" && ct != "multipart/form-data" {
+			continue
+		}
+		schema := openapi.ResolveSchema(rb.Content[ct].Schema)
+		if schema == nil {
+			continue
+		}
+		params := make([]interface{}, 0)
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			prop := openapi.ResolveSchema(schema.Properties[name])
+			if prop == nil {
+				continue
+			}
+			p := map[string]interface{}{"name": name, "in": "formData", "required": slices.Contains(schema.Required, name)}
+			if prop.Description != "" {
+				p["description"] = prop.Description
+			}
+			moveFields(jsonObject(prop), p, schemaFields)
+			if p["type"] == "string" && p["format"] == "binary" {
+				p["type"] = "file"
+				delete(p, "format")
+			}
+			params = append(params, p)
+		}
+		return params, []string{ct}
+	}
+
+	body := map[string]interface{}{"name": "body", "in": "body", "required": rb.Required, "schema": jsonValue(rb.Content[types[0]].Schema)}
+	if rb.Description != "" {
+		body["description"] = rb.Description
+	}
+	return []interface{}{body}, types
+}
+
+func (c *converter) toSwagger2Response(openapi *oas.OpenAPI, r *oas.Response, produces map[string]bool) map[string]interface{} {
+	if r.Refer != nil {
+		return map[string]interface{}{"$ref": r.Refer.RefString()}
+	}
+
+	out := fieldsOf(jsonObject(r), "description")
+	out["description"] = r.Description
+
+	types := slices.Sorted(maps.Keys(r.Content))
+	if len(types) > 0 {
+		if s := r.Content[types[0]].Schema; s != nil {
+			out["schema"] = jsonValue(s)
+		}
+		examples := map[string]interface{}{}
+		for _, ct := range types {
+			produces[ct] = true
+			if example, ok := jsonObject(r.Content[ct])["example"]; ok {
+				examples[ct] = example
+			}
+		}
+		if len(examples) > 0 {
+			out["examples"] = examples
+		}
+	}
+
+	if len(r.Headers) > 0 {
+		headers := map[string]interface{}{}
+		for _, name := range slices.Sorted(maps.Keys(r.Headers)) {
+			h := openapi.ResolveHeader(r.Headers[name])
+			if h == nil {
+				continue
+			}
+			header := fieldsOf(jsonObject(h), "description")
+			if s := openapi.ResolveSchema(h.Schema); s != nil {
+				moveFields(jsonObject(s), header, schemaFields)
+			}
+			headers[name] = header
+		}
+		out["headers"] = headers
+	}
+
+	return out
+}
+
+func (c *converter) toSwagger2SecurityScheme(name string, s *oas.SecurityScheme) map[string]interface{} {
+	d := fieldsOf(jsonObject(s), "description")
+
+	switch s.Type {
+	case oas.SecurityTypeHttp:
+		if s.Scheme == "basic" {
+			d["type"] = "basic"
+			return d
+		}
+		c.warn("http %s security scheme %s as apiKey of Authorization header", s.Scheme, name)
+		d["type"] = "apiKey"
+		d["name"] = "Authorization"
+		d["in"] = "header"
+		return d
+	case oas.SecurityTypeAPIKey:
+		if s.In == oas.PositionCookie {
+			c.warn("apiKey security scheme %s in cookie not supported, dropped", name)
+			return nil
+		}
+		d["type"] = "apiKey"
+		d["name"] = s.Name
+		d["in"] = s.In
+		return d
+	case oas.SecurityTypeOAuth2:
+		if s.Flows == nil {
+			break
+		}
+		flows := map[string]*oas.OAuthFlow{
+			"accessCode":  s.Flows.AuthorizationCode,
+			"implicit":    s.Flows.Implicit,
+			"password":    s.Flows.Password,
+			"application": s.Flows.ClientCredentials,
+		}
+		count := 0
+		for _, flow := range flows {
+			if flow != nil {
+				count++
+			}
+		}
+		for _, swaggerFlow := range oauth2Flows {
+			flow := flows[swaggerFlow]
+			if flow == nil {
+				continue
+			}
+			if count > 1 {
+				c.warn("only %s flow of oauth2 security scheme %s kept", swaggerFlow, name)
+			}
+			d["type"] = "oauth2"
+			d["flow"] = swaggerFlow
+			if flow.AuthorizationURL != "" {
+				d["authorizationUrl"] = flow.AuthorizationURL
+			}
+			if flow.TokenURL != "" {
+				d["tokenUrl"] = flow.TokenURL
+			}
+			d["scopes"] = flow.Scopes
+			return d
+		}
+	}
+
+	c.warn("%s security scheme %s not supported, dropped", s.Type, name)
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+// helpers of generic json values decoded by encoding/json
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func pointerOf(tokens ...string) string {
+	b := strings.Builder{}
+	for _, t := range tokens {
+		b.WriteString("/")
+		b.WriteString(escapePointer(t))
+	}
+	return b.String()
+}
+
+// walkRefs calls fn with each object contains $ref, children of the object not walked
+func walkRefs(v interface{}, pointer string, fn func(obj map[string]interface{}, ref string, pointer string)) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := x["$ref"].(string); ok {
+			fn(x, ref, pointer)
+			return
+		}
+		for _, k := range slices.Sorted(maps.Keys(x)) {
+			walkRefs(x[k], pointer+"/"+escapePointer(k), fn)
+		}
+	case []interface{}:
+		for i := range x {
+			walkRefs(x[i], pointer+"/"+strconv.Itoa(i), fn)
+		}
+	}
+}
+
+// deletePointer deletes the value of the pointer from its parent object or array
+func deletePointer(v interface{}, pointer string) {
+	tokens, err := oas.ParseJSONPointer(pointer)
+	if err != nil || len(tokens) == 0 {
+		return
+	}
+	parent, err := oas.ResolveJSONPointer(v, pointerOf(tokens[:len(tokens)-1]...))
+	if err != nil {
+		return
+	}
+	last := tokens[len(tokens)-1]
+	switch x := parent.(type) {
+	case map[string]interface{}:
+		delete(x, last)
+	case []interface{}:
+		if i, err := strconv.Atoi(last); err == nil && i >= 0 && i < len(x) {
+			x[i] = nil
+		}
+	}
+}
+
+func object(v interface{}, keys ...string) map[string]interface{} {
+	for _, k := range keys {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func ensureObject(m map[string]interface{}, key string) map[string]interface{} {
+	if child, ok := m[key].(map[string]interface{}); ok {
+		return child
+	}
+	child := map[string]interface{}{}
+	m[key] = child
+	return child
+}
+
+// jsonValue returns json values of v encoded by encoding/json
+func jsonValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+func jsonObject(v interface{}) map[string]interface{} {
+	m, _ := jsonValue(v).(map[string]interface{})
+	return m
+}
+
+// decodeValue decodes json values into the typed value
+func decodeValue(v interface{}, typed interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, typed)
+}
+
+// fieldsOf returns the object with the keys and extensions only
+func fieldsOf(m map[string]interface{}, keys ...string) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for k, v := range m {
+		if strings.HasPrefix(k, "x-") || slices.Contains(keys, k) {
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	register("usage", "report reference counts and users of components", runUsage)
+	register("prune", "remove components not referenced from paths or security requirements", runPrune)
+}
+
+type componentUsage struct {
+	Pointer string   `json:"pointer"`
+	Count   int      `json:"count"`
+	UsedBy  []string `json:"usedBy"`
+	Unused  bool     `json:"unused,omitempty"`
+}
+
+func runUsage(e *env, args []string) error {
+	fs := newFlagSet(e, "usage", "[flags] <file>")
+	format := fs.String("format", "text", "report format text or json")
+	unusedOnly := fs.Bool("unused", false, "only report unused components")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return usagef("unsupported format %q", *format)
+	}
+
+	openapi, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	usages := make([]*componentUsage, 0)
+	for _, u := range openapi.ComponentUsages() {
+		if *unusedOnly && !u.Unused {
+			continue
+		}
+		usedBy := u.UsedBy
+		if usedBy == nil {
+			usedBy = []string{}
+		}
+		usages = append(usages, &componentUsage{Pointer: u.Pointer(), Count: u.Count, UsedBy: usedBy, Unused: u.Unused})
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(usages)
+	}
+
+	for _, u := range usages {
+		line := fmt.Sprintf("%s: %d refs", u.Pointer, u.Count)
+		if len(u.UsedBy) > 0 {
+			line += ", used by " + strings.Join(u.UsedBy, ", ")
+		}
+		if u.Unused {
+			line += " (unused)"
+		}
+		fmt.Fprintln(e.stdout, line)
+	}
+	return nil
+}
+
+func runPrune(e *env, args []string) error {
+	fs := newFlagSet(e, "prune", "[flags] <file>")
+	o := &output{}
+	o.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs, 1); err != nil {
+		return err
+	}
+
+	openapi, err := loadOpenAPI(e, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	for _, u := range openapi.PruneComponents() {
+		fmt.Fprintf(e.stderr, "removed %s\n", u.Pointer())
+	}
+
+	return o.write(e, openapi)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const usageDoc = `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"},
+	"paths": {"/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "ok",
+		"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pets"}}}}}}}},
+	"components": {"schemas": {
+		"Pet": {"type": "object"},
+		"Pets": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}},
+		"Unused": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}
+	}}}`
+
+func TestUsage(t *testing.T) {
+	code, stdout, _ := runCommand(usageDoc, "usage", "-")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, stdout, "/components/schemas/Pet: 2 refs, used by /components/schemas/Pets, /components/schemas/Unused\n")
+	assert.Contains(t, stdout, "/components/schemas/Unused: 0 refs (unused)\n")
+
+	code, stdout, _ = runCommand(usageDoc, "usage", "-unused", "-format", "json", "-")
+	assert.Equal(t, ExitOK, code)
+
+	usages := make([]*componentUsage, 0)
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &usages))
+	assert.Equal(t, []*componentUsage{{Pointer: "/components/schemas/Unused", UsedBy: []string{}, Unused: true}}, usages)
+
+	code, _, _ = runCommand(usageDoc, "usage", "-format", "xml", "-")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestPrune(t *testing.T) {
+	code, stdout, stderr := runCommand(usageDoc, "prune", "-")
+	assert.Equal(t, ExitOK, code)
+	assert.Equal(t, "removed /components/schemas/Unused\n", stderr)
+	assert.Equal(t, []string{"Pet", "Pets"}, slices.Sorted(maps.Keys(decodeOpenAPI(t, stdout).Schemas)))
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"github.com/go-courier/oas/workflow"
+)
+
+func init() {
+	register("validate", "validate documents against the OpenAPI 3.0 spec", runValidate)
+}
+
+func runValidate(e *env, args []string) error {
+	fs := newFlagSet(e, "validate", "[flags] <file>...")
+	r := &report{}
+	r.register(fs, true)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return usagef("expect file(s)")
+	}
+
+	findings := make([]*Finding, 0)
+
+	for _, path := range fs.Args() {
+		data, err := readInput(e, path)
+		if err != nil {
+			return err
+		}
+		list, err := validateDocument(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if fs.NArg() > 1 {
+			for _, f := range list {
+				f.Pointer = path + "#" + f.Pointer
+			}
+		}
+		findings = append(findings, list...)
+	}
+
+	return r.write(e, findings)
+}
+
+// validateDocument returns findings of the document, error returned when the document could not be parsed
+func validateDocument(data []byte) ([]*Finding, error) {
+	isJSON := bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+
+	data, err := toJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	findings := make([]*Finding, 0)
+
+	add := func(pointer string, rule string, format string, args ...interface{}) {
+		findings = append(findings, &Finding{Severity: SeverityError, Rule: rule, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if version, _ := object(tree)["openapi"].(string); !strings.HasPrefix(version, "3.0.") {
+		if swagger, ok := object(tree)["swagger"].(string); ok {
+			add("/swagger", "openapi-version", "swagger %s not supported, convert it to 3.0 first", swagger)
+		} else {
+			add("/openapi", "openapi-version", "openapi version should be 3.0.x, but got %q", version)
+		}
+		return findings, nil
+	}
+
+	openapi := &oas.OpenAPI{}
+
+	if err := oas.UnmarshalStrict(data, openapi); err != nil {
+		var decodeErrors oas.DecodeErrors
+		if !errors.As(err, &decodeErrors) {
+			return nil, err
+		}
+		for _, de := range decodeErrors {
+			f := &Finding{Severity: SeverityError, Rule: "schema", Pointer: de.Pointer, Message: de.Message}
+			if isJSON {
+				f.Line, f.Column = de.Line, de.Column
+			}
+			findings = append(findings, f)
+		}
+		// semantic checks need the document decoded, invalid values dropped
+		pruned := jsonValue(tree)
+		for _, de := range decodeErrors {
+			deletePointer(pruned, de.Pointer)
+		}
+		prunedData, _ := json.Marshal(pruned)
+		openapi = &oas.OpenAPI{}
+		if err := json.Unmarshal(prunedData, openapi); err != nil {
+			return findings, nil
+		}
+	}
+
+	walkRefs(tree, "", func(obj map[string]interface{}, ref string, pointer string) {
+		if !strings.HasPrefix(ref, "#") {
+			findings = append(findings, &Finding{Severity: SeverityWarning, Rule: "external-ref", Pointer: pointer, Message: fmt.Sprintf("external ref %s, bundle the document first", ref)})
+			return
+		}
+		if _, err := oas.ResolveJSONPointer(tree, ref[1:]); err != nil {
+			add(pointer, "unresolved-ref", "unresolved ref %s", ref)
+		}
+	})
+
+	if openapi.Title == "" {
+		add("/info/title", "info", "title required")
+	}
+	if openapi.Version == "" {
+		add("/info/version", "info", "version required")
+	}
+
+	checkServers := func(pointer string, servers []*oas.Server) {
+		for i, s := range servers {
+			if s == nil {
+				continue
+			}
+			if err := s.Validate(); err != nil {
+				for _, line := range strings.Split(err.Error(), "\n") {
+					add(fmt.Sprintf("%s/servers/%d", pointer, i), "server", "%s", line)
+				}
+			}
+		}
+	}
+
+	checkServers("", openapi.Servers)
+
+	checkSecurity := func(pointer string, requirements []*oas.SecurityRequirement) {
+		for i, sr := range requirements {
+			if sr == nil {
+				continue
+			}
+			for _, name := range slices.Sorted(maps.Keys(*sr)) {
+				if openapi.SecuritySchemes[name] == nil {
+					add(fmt.Sprintf("%s/security/%d", pointer, i), "security", "security scheme %q not defined", name)
+				}
+			}
+		}
+	}
+
+	checkSecurity("", openapi.Security)
+
+	operationIds := map[string]string{}
+
+	eachOperation(openapi, func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) {
+		pointer := pointerOf("paths", path, string(method))
+
+		if op.OperationId != "" {
+			if prev, ok := operationIds[op.OperationId]; ok {
+				add(pointer+"/operationId", "operation-id", "operationId %q duplicated with %s", op.OperationId, prev)
+			} else {
+				operationIds[op.OperationId] = pointer
+			}
+		}
+
+		declared := map[string]bool{}
+		for _, p := range effectiveParameters(openapi, pathItem, op) {
+			if p.In != oas.PositionPath {
+				continue
+			}
+			declared[p.Name] = true
+			if !p.Required {
+				add(pointer, "path-param", "path parameter %q should be required", p.Name)
+			}
+		}
+
+		inTemplate := map[string]bool{}
+		for _, name := range oas.PathParamNames(path) {
+			inTemplate[name] = true
+			if !declared[name] {
+				add(pointer, "path-param", "path parameter %q not declared", name)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(declared)) {
+			if !inTemplate[name] {
+				add(pointer, "path-param", "path parameter %q not in path template", name)
+			}
+		}
+
+		if op.Responses.Default == nil && len(op.Responses.Responses) == 0 && len(op.Responses.Ranges) == 0 {
+			add(pointer+"/responses", "responses", "at least one response required")
+		}
+
+		checkServers(pointerOf("paths", path), pathItem.Servers)
+		checkServers(pointer, op.Servers)
+		checkSecurity(pointer, op.Security)
+	})
+
+	return findings, nil
+}
+
+var methodOrder = []oas.HttpMethod{oas.GET, oas.PUT, oas.POST, oas.DELETE, oas.OPTIONS, oas.HEAD, oas.PATCH, oas.TRACE}
+
+// eachOperation iterates operations sorted by path then method
+func eachOperation(openapi *oas.OpenAPI, fn func(method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation)) {
+	for _, path := range slices.Sorted(maps.Keys(openapi.Paths.Paths)) {
+		pathItem := openapi.Paths.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range methodOrder {
+			if op := pathItem.Operations.Operations[method]; op != nil {
+				fn(method, path, pathItem, op)
+			}
+		}
+	}
+}
+
+// effectiveParameters resolves parameters of path item and operation, operation level ones override path item level ones
+func effectiveParameters(openapi *oas.OpenAPI, pathItem *oas.PathItem, op *oas.Operation) []*oas.Parameter {
+	t := &workflow.Target{PathItem: pathItem, Operation: op}
+	return t.Parameters(&openapi.ComponentsObject)
+}
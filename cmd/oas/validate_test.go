@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	code, stdout, _ := runCommand("", "validate", "testdata/petstore.yaml")
+	assert.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+
+	code, stdout, _ = runCommand("", "validate", "-format", "json", "testdata/invalid.json")
+	assert.Equal(t, ExitFindings, code)
+
+	findings := make([]*Finding, 0)
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &findings))
+
+	messages := map[string]bool{}
+	for _, f := range findings {
+		messages[f.Pointer+": "+f.Message] = true
+		if f.Rule == "schema" {
+			assert.Equal(t, 14, f.Line)
+		}
+	}
+
+	assert.Equal(t, map[string]bool{
+		`/paths/~1items~1{id}/delete/summary: cannot unmarshal number into string`:                                true,
+		`/paths/~1items~1{id}/delete/operationId: operationId "getItem" duplicated with /paths/~1items~1{id}/get`: true,
+		`/paths/~1items~1{id}/delete/responses: at least one response required`:                                   true,
+		`/paths/~1items~1{id}/delete: path parameter "id" not declared`:                                           true,
+		`/paths/~1items~1{id}/get: path parameter "id" not declared`:                                              true,
+		`/paths/~1items~1{id}/get/responses/200: unresolved ref #/components/responses/Missing`:                   true,
+	}, messages)
+
+	code, stdout, _ = runCommand("", "validate", "testdata/swagger.json")
+	assert.Equal(t, ExitFindings, code)
+	assert.Contains(t, stdout, "convert it to 3.0 first")
+
+	_, stdout, _ = runCommand("", "validate", "testdata/bundle/root.yaml")
+	assert.Contains(t, stdout, "warning: /paths/~1users~1{id}: external ref ./paths.yaml#/user")
+
+	_, bundled, _ := runCommand("", "bundle", "testdata/bundle/root.yaml")
+	code, stdout, _ = runCommand(bundled, "validate", "-")
+	assert.Equal(t, ExitOK, code)
+	assert.Empty(t, stdout)
+}
@@ -6,11 +6,69 @@ import (
 	"strings"
 )
 
+func NewComponents() *Components {
+	return &Components{}
+}
+
 type Components struct {
 	ComponentsObject
 	SpecExtensions
 }
 
+func (i Components) WithSchema(id string, s *Schema) *Components {
+	c := i.Clone()
+	c.AddSchema(id, s.Clone())
+	return c
+}
+
+func (i Components) WithResponse(id string, r *Response) *Components {
+	c := i.Clone()
+	c.AddResponse(id, r.Clone())
+	return c
+}
+
+func (i Components) WithParameter(id string, p *Parameter) *Components {
+	c := i.Clone()
+	c.AddParameter(id, p.Clone())
+	return c
+}
+
+func (i Components) WithExample(id string, e *Example) *Components {
+	c := i.Clone()
+	c.AddExample(id, e.Clone())
+	return c
+}
+
+func (i Components) WithRequestBody(id string, rb *RequestBody) *Components {
+	c := i.Clone()
+	c.AddRequestBody(id, rb.Clone())
+	return c
+}
+
+func (i Components) WithHeader(id string, h *Header) *Components {
+	c := i.Clone()
+	c.AddHeader(id, h.Clone())
+	return c
+}
+
+func (i Components) WithSecurityScheme(id string, ss *SecurityScheme) *Components {
+	c := i.Clone()
+	c.AddSecurityScheme(id, ss.Clone())
+	return c
+}
+
+func (i Components) WithLink(id string, l *Link) *Components {
+	c := i.Clone()
+	c.AddLink(id, l.Clone())
+	return c
+}
+
+func (i Components) WithCallback(id string, cb *Callback) *Components {
+	c := i.Clone()
+	c.AddCallback(id, cb.Clone())
+	return c
+}
+
 func (i Components) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.ComponentsObject, i.SpecExtensions)
 }
@@ -124,7 +182,7 @@ func (object *ComponentsObject) RefHeader(id string) *Header {
 }
 
 func (object *ComponentsObject) RefLink(id string) *Link {
-	if object.Links == nil || object.Headers[id] == nil {
+	if object.Links == nil || object.Links[id] == nil {
 		return nil
 	}
 	s := &Link{}
@@ -160,6 +218,10 @@ type Reference struct {
 	Refer Refer
 }
 
+func (ref Reference) refer() Refer {
+	return ref.Refer
+}
+
 type Refer interface {
 	RefString() string
 }
@@ -226,3 +288,61 @@ func (ref *Reference) UnmarshalJSONRefFirst(data []byte, values ...interface{})
 	}
 	return flattenUnmarshalJSON(data, values...)
 }
+
+func (object *ComponentsObject) ResolveSchema(s *Schema) *Schema {
+	return resolveComponent(object.Schemas, "schemas", s)
+}
+
+func (object *ComponentsObject) ResolveResponse(r *Response) *Response {
+	return resolveComponent(object.Responses, "responses", r)
+}
+
+func (object *ComponentsObject) ResolveParameter(p *Parameter) *Parameter {
+	return resolveComponent(object.Parameters, "parameters", p)
+}
+
+func (object *ComponentsObject) ResolveExample(e *Example) *Example {
+	return resolveComponent(object.Examples, "examples", e)
+}
+
+func (object *ComponentsObject) ResolveRequestBody(rb *RequestBody) *RequestBody {
+	return resolveComponent(object.RequestBodies, "requestBodies", rb)
+}
+
+func (object *ComponentsObject) ResolveHeader(h *Header) *Header {
+	return resolveComponent(object.Headers, "headers", h)
+}
+
+func (object *ComponentsObject) ResolveLink(l *Link) *Link {
+	return resolveComponent(object.Links, "links", l)
+}
+
+func (object *ComponentsObject) ResolveCallback(c *Callback) *Callback {
+	return resolveComponent(object.Callbacks, "callbacks", c)
+}
+
+// resolveComponent follows refs of v to the component of the group, nil when not found
+func resolveComponent[T any, PT interface {
+	*T
+	refer() Refer
+}](components map[string]*T, group string, v *T) *T {
+	for i := 0; v != nil && PT(v).refer() != nil && i < maxRefDepth; i++ {
+		v = components[componentID(PT(v).refer(), group)]
+	}
+	return v
+}
+
+// maxRefDepth limits resolving of ref chains, to avoid looping on circular refs
+const maxRefDepth = 32
+
+// componentID returns id of the ref in the components group, empty when ref not points to the group
+func componentID(refer Refer, group string) string {
+	ref, ok := refer.(*ComponentRefer)
+	if !ok {
+		ref = ParseComponentRefer(refer.RefString())
+	}
+	if ref == nil || ref.Group != group {
+		return ""
+	}
+	return ref.ID
+}
@@ -59,5 +59,44 @@ func TestComponents(t *testing.T) {
 	assert.Nil(t, components.RefExample("not_found"))
 	assert.Nil(t, components.RefRequestBody("not_found"))
 
+	g.It("with builders", `{"schemas":{"key":{"type":"string"}},"responses":{"key":{"description":"ok"}},"parameters":{"key":{"name":"key","in":"query","schema":{"type":"string"}}},"examples":{"key":{"value":"x"}},"requestBodies":{"key":{"description":"desc","content":{"application/json":{"schema":{"type":"string"}}}}},"headers":{"key":{"schema":{"type":"string"}}},"securitySchemes":{"key":{"type":"http","description":"desc","scheme":"basic"}},"links":{"key":{"operationId":"link"}},"callbacks":{"key":{"callback":{"post":{"operationId":"op","responses":{}}}}}}`,
+		NewComponents().
+			WithSchema("key", String()).
+			WithResponse("key", NewResponse("ok")).
+			WithParameter("key", QueryParameter("key", String(), false)).
+			WithExample("key", NewExample().WithValue("x")).
+			WithRequestBody("key", NewRequestBody("", false).WithDesc("desc").WithSchema("application/json", String())).
+			WithHeader("key", NewHeaderWithSchema(String())).
+			WithSecurityScheme("key", NewHTTPSecurityScheme("basic", "").WithDesc("desc")).
+			WithLink("key", NewLink("link")).
+			WithCallback("key", NewCallback(POST, "callback", NewOperation("op"))),
+	)
+
 	g.Run(t)
 }
+
+func TestRefLinkLooksUpLinks(t *testing.T) {
+	components := &Components{}
+	components.AddLink("onlyLink", NewLink("link"))
+	components.AddHeader("onlyHeader", NewHeaderWithSchema(String()))
+
+	assert.NotNil(t, components.RefLink("onlyLink"))
+	assert.Nil(t, components.RefLink("onlyHeader"))
+}
+
+func TestComponentsResolve(t *testing.T) {
+	components := &Components{}
+	components.AddSchema("Pet", String())
+	components.AddSchema("Alias", components.RefSchema("Pet"))
+	components.AddSchema("Loop", RefSchemaByRefer(NewComponentRefer("schemas", "Loop")))
+	components.AddLink("key", NewLink("link"))
+
+	assert.Equal(t, TypeString, components.ResolveSchema(components.RefSchema("Alias")).Type)
+	assert.Equal(t, TypeString, components.ResolveSchema(RefSchema("#/components/schemas/Pet")).Type)
+	assert.Nil(t, components.ResolveSchema(RefSchema("#/components/schemas/Unknown")))
+	assert.Nil(t, components.ResolveSchema(RefSchema("#/components/responses/Pet")))
+	assert.NotPanics(t, func() {
+		components.ResolveSchema(components.RefSchema("Loop"))
+	})
+	assert.Equal(t, "link", components.ResolveLink(components.RefLink("key")).OperationId)
+}
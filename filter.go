@@ -0,0 +1,128 @@
+package oas
+
+import (
+	"maps"
+	"path"
+	"slices"
+)
+
+// Filter selects operations of a document.
+//
+// Every criterion set must be matched, any of its values matched is enough.
+// Extensions of the path item apply to its operations too.
+type Filter struct {
+	// Tags keeps operations with any of the tags
+	Tags []string
+	// Paths keeps operations of paths matched any of the globs like /pets/*, see path.Match
+	Paths []string
+	// OperationIds keeps operations with any of the operationIds
+	OperationIds []string
+	// Extensions keeps operations with any of the extensions, nil value matches any value
+	Extensions map[string]interface{}
+	// ExcludeExtensions drops operations with any of the extensions, like {"x-internal": true}
+	ExcludeExtensions map[string]interface{}
+}
+
+// Match returns true when the operation is selected
+func (f *Filter) Match(path string, item *PathItem, op *Operation) bool {
+	extensions := map[string]interface{}{}
+	if item != nil {
+		for k, v := range item.Extensions {
+			extensions[k] = v
+		}
+	}
+	for k, v := range op.Extensions {
+		extensions[k] = v
+	}
+
+	if len(f.ExcludeExtensions) > 0 && matchExtensions(f.ExcludeExtensions, extensions) {
+		return false
+	}
+	if len(f.Extensions) > 0 && !matchExtensions(f.Extensions, extensions) {
+		return false
+	}
+	if len(f.Tags) > 0 && !matchAny(f.Tags, op.Tags, func(a, b string) bool { return a == b }) {
+		return false
+	}
+	if len(f.Paths) > 0 && !matchAny(f.Paths, []string{path}, matchPath) {
+		return false
+	}
+	if len(f.OperationIds) > 0 && !matchAny(f.OperationIds, []string{op.OperationId}, func(a, b string) bool { return a == b }) {
+		return false
+	}
+	return true
+}
+
+// Apply returns a copy of the document with only the selected operations,
+// components referred by them transitively, and tags used by them.
+func (f *Filter) Apply(openapi *OpenAPI) (*OpenAPI, error) {
+	o := &OpenAPI{}
+	if err := cloneByJSON(openapi, o); err != nil {
+		return nil, err
+	}
+
+	for _, p := range slices.Sorted(maps.Keys(o.Paths.Paths)) {
+		item := o.Paths.Paths[p]
+		if item == nil {
+			delete(o.Paths.Paths, p)
+			continue
+		}
+		for _, method := range slices.Sorted(maps.Keys(item.Operations.Operations)) {
+			if op := item.Operations.Operations[method]; op == nil || !f.Match(p, item, op) {
+				delete(item.Operations.Operations, method)
+			}
+		}
+		if len(item.Operations.Operations) == 0 {
+			delete(o.Paths.Paths, p)
+		}
+	}
+
+	o.PruneComponents()
+
+	tags := map[string]bool{}
+	for _, item := range o.Paths.Paths {
+		for _, op := range item.Operations.Operations {
+			for _, tag := range op.Tags {
+				tags[tag] = true
+			}
+		}
+	}
+	kept := make([]*Tag, 0, len(o.Tags))
+	for _, t := range o.Tags {
+		if t != nil && tags[t.Name] {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	o.Tags = kept
+
+	return o, nil
+}
+
+func matchExtensions(expected map[string]interface{}, extensions map[string]interface{}) bool {
+	for k, v := range expected {
+		actual, ok := extensions[k]
+		if ok && (v == nil || jsonEqual(v, actual)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPath(pattern string, p string) bool {
+	matched, _ := path.Match(pattern, p)
+	return matched
+}
+
+func matchAny(patterns []string, values []string, match func(pattern string, value string) bool) bool {
+	for _, pattern := range patterns {
+		for _, v := range values {
+			if match(pattern, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package oas
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFilterDoc() *OpenAPI {
+	openapi := NewOpenAPI()
+	openapi.AddTag(NewTag("pets"))
+	openapi.AddTag(NewTag("stores"))
+	openapi.AddTag(NewTag("admin"))
+
+	openapi.AddSecurityScheme("token", NewHTTPSecurityScheme("bearer", "JWT"))
+	openapi.AddSecurityScheme("apiKey", NewAPIKeySecurityScheme("key", PositionHeader))
+
+	openapi.AddSchema("Base", ObjectOf(Props{"id": Long()}))
+	openapi.AddSchema("User", AllOf(openapi.RefSchema("Base"), ObjectOf(Props{"name": String()})))
+	openapi.AddSchema("Pet", ObjectOf(Props{"owner": openapi.RefSchema("User")}))
+	openapi.AddSchema("Pets", ItemsOf(openapi.RefSchema("Pet")))
+	openapi.AddSchema("Store", ObjectOf(Props{"name": String()}))
+	openapi.AddSchema("Audit", ObjectOf(Props{"by": openapi.RefSchema("User")}))
+
+	openapi.AddParameter("Limit", QueryParameter("limit", Integer(), false))
+
+	example := NewExample()
+	example.Value = AnyValue(10)
+	openapi.AddExample("RateLimit", example)
+	header := NewHeaderWithSchema(Integer())
+	header.AddExample("default", openapi.RefExample("RateLimit"))
+	openapi.AddHeader("RateLimit", header)
+
+	notFound := NewResponse("not found")
+	notFound.AddHeader("X-RateLimit", openapi.RefHeader("RateLimit"))
+	openapi.AddResponse("NotFound", notFound)
+
+	listPets := NewOperation("listPets").WithTags("pets")
+	listPets.AddParameter(openapi.RefParameter("Limit"))
+	ok := NewResponse("ok")
+	ok.AddContent("application/json", NewMediaTypeWithSchema(openapi.RefSchema("Pets")))
+	listPets.AddResponse(http.StatusOK, ok)
+	listPets.AddResponse(http.StatusNotFound, openapi.RefResponse("NotFound"))
+	token := openapi.RequireSecurity("token")
+	listPets.AddSecurityRequirement(&token)
+	openapi.AddOperation(GET, "/pets", listPets)
+
+	listStores := NewOperation("listStores").WithTags("stores")
+	ok = NewResponse("ok")
+	ok.AddContent("application/json", NewMediaTypeWithSchema(ItemsOf(openapi.RefSchema("Store"))))
+	listStores.AddResponse(http.StatusOK, ok)
+	apiKey := openapi.RequireSecurity("apiKey")
+	listStores.AddSecurityRequirement(&apiKey)
+	openapi.AddOperation(GET, "/stores", listStores)
+
+	audit := NewOperation("listAudits").WithTags("admin", "stores")
+	audit.AddExtension("x-internal", true)
+	ok = NewResponse("ok")
+	ok.AddContent("application/json", NewMediaTypeWithSchema(openapi.RefSchema("Audit")))
+	audit.AddResponse(http.StatusOK, ok)
+	openapi.AddOperation(GET, "/stores/audits", audit)
+
+	return openapi
+}
+
+func tagNames(tags []*Tag) []string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+func TestFilter(t *testing.T) {
+	t.Run("by tag with dependency closure", func(t *testing.T) {
+		openapi := newFilterDoc()
+
+		filtered, err := (&Filter{Tags: []string{"pets"}}).Apply(openapi)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"/pets"}, slices.Sorted(maps.Keys(filtered.Paths.Paths)))
+		assert.Equal(t, []string{"Base", "Pet", "Pets", "User"}, slices.Sorted(maps.Keys(filtered.Schemas)))
+		assert.Equal(t, []string{"Limit"}, slices.Sorted(maps.Keys(filtered.Parameters)))
+		assert.Equal(t, []string{"NotFound"}, slices.Sorted(maps.Keys(filtered.Responses)))
+		assert.Equal(t, []string{"RateLimit"}, slices.Sorted(maps.Keys(filtered.Headers)))
+		assert.Equal(t, []string{"RateLimit"}, slices.Sorted(maps.Keys(filtered.Examples)))
+		assert.Equal(t, []string{"token"}, slices.Sorted(maps.Keys(filtered.SecuritySchemes)))
+		assert.Equal(t, []string{"pets"}, tagNames(filtered.Tags))
+
+		// source not touched
+		assert.Len(t, openapi.Paths.Paths, 3)
+		assert.Len(t, openapi.Schemas, 6)
+	})
+
+	t.Run("exclude internal", func(t *testing.T) {
+		filtered, err := (&Filter{ExcludeExtensions: map[string]interface{}{"x-internal": true}}).Apply(newFilterDoc())
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"/pets", "/stores"}, slices.Sorted(maps.Keys(filtered.Paths.Paths)))
+		assert.Equal(t, []string{"Base", "Pet", "Pets", "Store", "User"}, slices.Sorted(maps.Keys(filtered.Schemas)))
+		assert.Equal(t, []string{"apiKey", "token"}, slices.Sorted(maps.Keys(filtered.SecuritySchemes)))
+		assert.Equal(t, []string{"pets", "stores"}, tagNames(filtered.Tags))
+	})
+
+	t.Run("by marker", func(t *testing.T) {
+		filtered, err := (&Filter{Extensions: map[string]interface{}{"x-internal": nil}}).Apply(newFilterDoc())
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"/stores/audits"}, slices.Sorted(maps.Keys(filtered.Paths.Paths)))
+		assert.Equal(t, []string{"Audit", "Base", "User"}, slices.Sorted(maps.Keys(filtered.Schemas)))
+		assert.Empty(t, filtered.SecuritySchemes)
+		assert.Equal(t, []string{"stores", "admin"}, tagNames(filtered.Tags))
+	})
+
+	t.Run("by path and operationId", func(t *testing.T) {
+		filtered, err := (&Filter{Paths: []string{"/stores*", "/stores/*"}, OperationIds: []string{"listStores"}}).Apply(newFilterDoc())
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"/stores"}, slices.Sorted(maps.Keys(filtered.Paths.Paths)))
+		assert.Equal(t, []string{"Store"}, slices.Sorted(maps.Keys(filtered.Schemas)))
+		assert.Empty(t, filtered.Parameters)
+		assert.Empty(t, filtered.Responses)
+	})
+
+	t.Run("top-level security kept", func(t *testing.T) {
+		openapi := newFilterDoc()
+		token := openapi.RequireSecurity("token")
+		openapi.AddSecurityRequirement(&token)
+
+		filtered, err := (&Filter{OperationIds: []string{"listStores"}}).Apply(openapi)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"apiKey", "token"}, slices.Sorted(maps.Keys(filtered.SecuritySchemes)))
+	})
+
+	t.Run("discriminator mapping", func(t *testing.T) {
+		openapi := NewOpenAPI()
+		openapi.AddSchema("Cat", ObjectOf(Props{"kind": String()}))
+		openapi.AddSchema("Dog", ObjectOf(Props{"kind": String()}))
+		openapi.AddSchema("Animal", OneOf(openapi.RefSchema("Cat")).WithDiscriminator(&Discriminator{
+			PropertyName: "kind",
+			Mapping:      map[string]string{"cat": "#/components/schemas/Cat", "dog": "#/components/schemas/Dog"},
+		}))
+
+		op := NewOperation("getAnimal")
+		ok := NewResponse("ok")
+		ok.AddContent("application/json", NewMediaTypeWithSchema(openapi.RefSchema("Animal")))
+		op.AddResponse(http.StatusOK, ok)
+		openapi.AddOperation(GET, "/animal", op)
+
+		filtered, err := (&Filter{}).Apply(openapi)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Animal", "Cat", "Dog"}, slices.Sorted(maps.Keys(filtered.Schemas)))
+	})
+}
@@ -1,10 +1,14 @@
 module github.com/go-courier/oas
 
-go 1.16
+go 1.24
 
 require (
-	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/go-courier/ptr v1.0.1
 	github.com/stretchr/testify v1.7.0
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 )
@@ -0,0 +1,145 @@
+package gosrc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+// EnumValue is a constant of the enum type
+type EnumValue struct {
+	Name  string
+	Value interface{}
+	// Label is read from doc or line comment of the constant, name of the constant when no comment
+	Label string
+}
+
+// Enum returns constants of the named type like "github.com/x/y.Status", in declaration order,
+// constants declared in multiple files ordered by file names first.
+// Constants with the same value as a previous one are skipped.
+func (s *Scanner) Enum(typeName string) ([]*EnumValue, error) {
+	pkg, named, err := s.lookupType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := named.Underlying().(*types.Basic); !ok {
+		return nil, fmt.Errorf("type %q is not of basic type", typeName)
+	}
+
+	values := make([]*EnumValue, 0)
+	seen := map[string]bool{}
+
+	files := slices.Clone(pkg.Syntax)
+	slices.SortFunc(files, func(a, b *ast.File) int {
+		return strings.Compare(pkg.Fset.File(a.Pos()).Name(), pkg.Fset.File(b.Pos()).Name())
+	})
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for _, ident := range vs.Names {
+					c, ok := pkg.TypesInfo.Defs[ident].(*types.Const)
+					if !ok || ident.Name == "_" || !types.Identical(c.Type(), named) {
+						continue
+					}
+					if key := c.Val().ExactString(); !seen[key] {
+						seen[key] = true
+						values = append(values, &EnumValue{
+							Name:  ident.Name,
+							Value: constantValue(c.Val()),
+							Label: labelOf(ident.Name, vs, gen),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no constants of type %q", typeName)
+	}
+	return values, nil
+}
+
+// EnumSchema returns schema of the named type, with enum values and labels in x-enum-labels
+func (s *Scanner) EnumSchema(typeName string) (*oas.Schema, error) {
+	values, err := s.Enum(typeName)
+	if err != nil {
+		return nil, err
+	}
+	_, named, _ := s.lookupType(typeName)
+
+	schema := oas.NewSchema(typeOf(named.Underlying().(*types.Basic)), "")
+	enum := make([]interface{}, len(values))
+	labels := make([]string, len(values))
+	for i, v := range values {
+		enum[i] = v.Value
+		labels[i] = v.Label
+	}
+	schema.Enum = enum
+	schema.AddExtension(oas.ExtensionEnumLabels, labels)
+	schema.AddExtension(oas.ExtensionGoType, typeName)
+	return schema, nil
+}
+
+func typeOf(b *types.Basic) oas.Type {
+	switch info := b.Info(); {
+	case info&types.IsString != 0:
+		return oas.TypeString
+	case info&types.IsBoolean != 0:
+		return oas.TypeBoolean
+	case info&types.IsInteger != 0:
+		return oas.TypeInteger
+	case info&types.IsNumeric != 0:
+		return oas.TypeNumber
+	}
+	return ""
+}
+
+func constantValue(v constant.Value) interface{} {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Bool:
+		return constant.BoolVal(v)
+	case constant.Int:
+		if i, ok := constant.Int64Val(v); ok {
+			return i
+		}
+		if u, ok := constant.Uint64Val(v); ok {
+			return u
+		}
+	}
+	f, _ := constant.Float64Val(v)
+	return f
+}
+
+// labelOf reads doc of the spec, or doc of the declaration with single spec, or line comment
+func labelOf(name string, vs *ast.ValueSpec, gen *ast.GenDecl) string {
+	doc := vs.Doc
+	if doc == nil && len(gen.Specs) == 1 {
+		doc = gen.Doc
+	}
+	if doc == nil {
+		doc = vs.Comment
+	}
+	if doc == nil {
+		return name
+	}
+	label := strings.Join(strings.Fields(doc.Text()), " ")
+	if label == "" {
+		return name
+	}
+	return label
+}
@@ -0,0 +1,59 @@
+package gosrc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+const enumsPkg = "github.com/go-courier/oas/gosrc/testdata/enums"
+
+func TestScannerEnum(t *testing.T) {
+	s, err := NewScanner(".", "./testdata/enums")
+	assert.NoError(t, err)
+
+	t.Run("string", func(t *testing.T) {
+		values, err := s.Enum(enumsPkg + ".Status")
+		assert.NoError(t, err)
+		assert.Equal(t, []*EnumValue{
+			{Name: "StatusAvailable", Value: "available", Label: "StatusAvailable is available for sale"},
+			{Name: "StatusPending", Value: "pending", Label: "pending in an order"},
+			{Name: "StatusSold", Value: "sold", Label: "sold already"},
+		}, values)
+	})
+
+	t.Run("files in name order", func(t *testing.T) {
+		values, err := s.Enum(enumsPkg + ".Size")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SizeSmall", "SizeLarge"}, []string{values[0].Name, values[1].Name})
+	})
+
+	t.Run("int with iota", func(t *testing.T) {
+		schema, err := s.EnumSchema(enumsPkg + ".Level")
+		assert.NoError(t, err)
+
+		data, err := json.Marshal(schema)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"type":"integer","enum":[1,2,4],"x-enum-labels":["LevelLow","LevelMiddle","the highest level"],"x-go-type":"`+enumsPkg+`.Level"}`, string(data))
+
+		labels, ok := oas.Extension[[]string](schema.SpecExtensions, oas.ExtensionEnumLabels)
+		assert.True(t, ok)
+		assert.Len(t, labels, 3)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := s.Enum(enumsPkg + ".Empty")
+		assert.EqualError(t, err, `no constants of type "`+enumsPkg+`.Empty"`)
+
+		_, err = s.Enum(enumsPkg + ".Missing")
+		assert.EqualError(t, err, `type "`+enumsPkg+`.Missing" not found`)
+
+		_, err = s.Enum("Status")
+		assert.Error(t, err)
+
+		_, err = s.Enum("example.com/unknown.Status")
+		assert.Error(t, err)
+	})
+}
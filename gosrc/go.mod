@@ -0,0 +1,21 @@
+module github.com/go-courier/oas/gosrc
+
+go 1.24
+
+require (
+	github.com/go-courier/oas v1.3.0
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/tools v0.33.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-courier/ptr v1.0.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.24.0 // indirect
+	golang.org/x/sync v0.14.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// the root module of the same commit, dropped when releasing with the root module tagged
+replace github.com/go-courier/oas => ..
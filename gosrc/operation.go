@@ -0,0 +1,332 @@
+package gosrc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"golang.org/x/tools/go/packages"
+)
+
+// Generate adds operations of annotated funcs in the packages into openapi,
+// named struct and enum types used by them added into components schemas.
+//
+// Funcs are annotated by directives in doc comments, summary and description read from the rest of the doc:
+//
+//	// ListPets lists pets
+//	//
+//	// Pets are sorted by name.
+//	//
+//	//oas:route GET /pets
+//	//oas:id listPets
+//	//oas:tags pets
+//	//oas:status 200
+//	//oas:response 404 Error
+//	//oas:deprecated
+//	func ListPets(ctx context.Context, req *ListPetsRequest) (*PetList, error)
+//
+// The first struct parameter is the request, fields of which tagged by `in:"query"`, `in:"path"`, `in:"header"`,
+// `in:"cookie"` are parameters, and `in:"body"` is the request body.
+// Parameter names are read from tag `name`, json tag, or field name, and required by tag `required:"true"` or in path.
+// The first non-error result is the response of status by //oas:status, 200 by default.
+// Types of //oas:response are looked up in the package of the func, - for no content.
+func (s *Scanner) Generate(openapi *oas.OpenAPI, pkgPaths ...string) error {
+	g := &generator{scanner: s, openapi: openapi, names: map[string]string{}, docs: map[token.Pos]string{}}
+	for _, pkg := range s.packages {
+		if s.inMainModule(pkg.PkgPath) {
+			g.collectDocs(pkg)
+		}
+	}
+
+	errs := make([]string, 0)
+	for _, pkgPath := range pkgPaths {
+		pkg := s.packages[pkgPath]
+		if pkg == nil {
+			return fmt.Errorf("package %q not loaded", pkgPath)
+		}
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Doc == nil {
+					continue
+				}
+				if err := g.operation(pkg, fn); err != nil {
+					errs = append(errs, fmt.Sprintf("%s: %s", pkg.Fset.Position(fn.Pos()), err))
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("generate operations failed:\n%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+type generator struct {
+	scanner *Scanner
+	openapi *oas.OpenAPI
+	// names of components schemas by qualified type name
+	names map[string]string
+	// docs of type and field declarations by position of names
+	docs map[token.Pos]string
+}
+
+func (g *generator) collectDocs(pkg *packages.Package) {
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.GenDecl:
+				for _, spec := range x.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						doc := ts.Doc
+						if doc == nil && len(x.Specs) == 1 {
+							doc = x.Doc
+						}
+						g.docs[ts.Name.Pos()] = docText(doc)
+					}
+				}
+			case *ast.Field:
+				doc := x.Doc
+				if doc == nil {
+					doc = x.Comment
+				}
+				if len(x.Names) == 0 {
+					g.docs[x.Type.Pos()] = docText(doc)
+				}
+				for _, name := range x.Names {
+					g.docs[name.Pos()] = docText(doc)
+				}
+			}
+			return true
+		})
+	}
+}
+
+func docText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+type directive struct {
+	name string
+	args []string
+}
+
+// directivesOf returns //oas: directives of the doc
+func directivesOf(doc *ast.CommentGroup) []directive {
+	directives := make([]directive, 0)
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, "//oas:") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(c.Text, "//oas:"))
+		if len(fields) > 0 {
+			directives = append(directives, directive{name: fields[0], args: fields[1:]})
+		}
+	}
+	return directives
+}
+
+func (g *generator) operation(pkg *packages.Package, fn *ast.FuncDecl) error {
+	directives := directivesOf(fn.Doc)
+	if len(directives) == 0 {
+		return nil
+	}
+
+	var method oas.HttpMethod
+	path := ""
+	op := oas.NewOperation(lowerFirst(fn.Name.Name))
+	status := http.StatusOK
+	responses := map[int]string{}
+
+	for _, d := range directives {
+		switch d.name {
+		case "route":
+			if len(d.args) != 2 {
+				return fmt.Errorf("//oas:route should be like GET /pets")
+			}
+			method, path = oas.HttpMethod(strings.ToLower(d.args[0])), d.args[1]
+			if !httpMethods[method] {
+				return fmt.Errorf("unknown method %s", d.args[0])
+			}
+		case "id":
+			if len(d.args) != 1 {
+				return fmt.Errorf("//oas:id should be with operationId")
+			}
+			op.OperationId = d.args[0]
+		case "tags":
+			op.Tags = append(op.Tags, d.args...)
+		case "deprecated":
+			op.Deprecated = true
+		case "status":
+			code, err := parseStatus(d.args)
+			if err != nil {
+				return err
+			}
+			status = code
+		case "response":
+			code, err := parseStatus(d.args)
+			if err != nil || len(d.args) != 2 {
+				return fmt.Errorf("//oas:response should be like 404 Error")
+			}
+			responses[code] = d.args[1]
+		default:
+			return fmt.Errorf("unknown directive //oas:%s", d.name)
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("//oas:route missing")
+	}
+
+	op.Summary, op.Description = summaryAndDescription(fn.Doc.Text())
+
+	sig := pkg.TypesInfo.Defs[fn.Name].(*types.Func).Type().(*types.Signature)
+
+	if err := g.request(op, sig); err != nil {
+		return err
+	}
+
+	for i := 0; i < sig.Results().Len(); i++ {
+		t := sig.Results().At(i).Type()
+		if isError(t) {
+			continue
+		}
+		g.addResponse(op, status, g.schemaOf(t))
+		break
+	}
+	if len(op.Responses.Responses) == 0 {
+		op.AddResponse(status, oas.NewResponse(http.StatusText(status)))
+	}
+
+	codes := make([]int, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if responses[code] == "-" {
+			op.AddResponse(code, oas.NewResponse(http.StatusText(code)))
+			continue
+		}
+		obj, ok := pkg.Types.Scope().Lookup(responses[code]).(*types.TypeName)
+		if !ok {
+			return fmt.Errorf("type %s of response %d not found", responses[code], code)
+		}
+		g.addResponse(op, code, g.schemaOf(obj.Type()))
+	}
+
+	g.openapi.AddOperation(method, path, op)
+	return nil
+}
+
+func (g *generator) addResponse(op *oas.Operation, code int, s *oas.Schema) {
+	op.AddResponse(code, oas.NewResponse(http.StatusText(code)).WithSchema("application/json", s))
+}
+
+// request collects parameters and request body from fields of the first struct parameter
+func (g *generator) request(op *oas.Operation, sig *types.Signature) error {
+	for i := 0; i < sig.Params().Len(); i++ {
+		t := sig.Params().At(i).Type()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+
+		for j := 0; j < st.NumFields(); j++ {
+			f := st.Field(j)
+			tag := reflect.StructTag(st.Tag(j))
+			in, ok := tag.Lookup("in")
+			if !ok {
+				continue
+			}
+
+			if in == "body" {
+				rb := oas.NewRequestBody(g.docs[f.Pos()], true)
+				op.SetRequestBody(rb.WithSchema("application/json", g.schemaOf(f.Type())))
+				continue
+			}
+
+			name := tag.Get("name")
+			if name == "" {
+				name, _, _ = jsonName(f, tag)
+			}
+			if name == "" {
+				name = f.Name()
+			}
+			required := tag.Get("required") == "true"
+			s := g.schemaOf(f.Type())
+
+			var p *oas.Parameter
+			switch oas.Position(in) {
+			case oas.PositionQuery:
+				p = oas.QueryParameter(name, s, required)
+			case oas.PositionPath:
+				p = oas.PathParameter(name, s)
+			case oas.PositionHeader:
+				p = oas.HeaderParameter(name, s, required)
+			case oas.PositionCookie:
+				p = oas.CookieParameter(name, s, required)
+			default:
+				return fmt.Errorf("field %s in unknown position %q", f.Name(), in)
+			}
+			if desc := g.docs[f.Pos()]; desc != "" {
+				p = p.WithDesc(desc)
+			}
+			op.AddParameter(p)
+		}
+		return nil
+	}
+	return nil
+}
+
+var httpMethods = map[oas.HttpMethod]bool{
+	oas.GET:     true,
+	oas.PUT:     true,
+	oas.POST:    true,
+	oas.DELETE:  true,
+	oas.OPTIONS: true,
+	oas.HEAD:    true,
+	oas.PATCH:   true,
+	oas.TRACE:   true,
+}
+
+func parseStatus(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, fmt.Errorf("status code missing")
+	}
+	code, err := strconv.Atoi(args[0])
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid status code %s", args[0])
+	}
+	return code, nil
+}
+
+// summaryAndDescription splits doc into the first paragraph and the rest
+func summaryAndDescription(doc string) (string, string) {
+	doc = strings.TrimSpace(doc)
+	summary, description, _ := strings.Cut(doc, "\n\n")
+	return strings.Join(strings.Fields(summary), " "), strings.TrimSpace(description)
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
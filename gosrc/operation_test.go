@@ -0,0 +1,69 @@
+package gosrc
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+const handlersPkg = "github.com/go-courier/oas/gosrc/testdata/handlers"
+
+func TestScannerGenerate(t *testing.T) {
+	s, err := NewScanner(".", "./testdata/handlers")
+	assert.NoError(t, err)
+
+	openapi := oas.NewOpenAPI()
+	assert.NoError(t, s.Generate(openapi, handlersPkg))
+
+	list := openapi.Paths.Paths["/pets"].Operations.Operations[oas.GET]
+	assert.Equal(t, "listPets", list.OperationId)
+	assert.Equal(t, "ListPets lists pets", list.Summary)
+	assert.Equal(t, "Pets are sorted by name.", list.Description)
+	assert.Equal(t, []string{"pets"}, list.Tags)
+
+	data, err := json.Marshal(list.Parameters)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"name":"limit","in":"query","description":"max count of pets","schema":{"type":"integer","format":"int64"}},
+		{"name":"status","in":"query","required":true,"schema":{"$ref":"#/components/schemas/Status"}},
+		{"name":"Authorization","in":"header","schema":{"type":"string"}}
+	]`, string(data))
+
+	data, err = json.Marshal(list.Responses)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"200":{"description":"OK","content":{"application/json":{"schema":{"type":"array","items":{"$ref":"#/components/schemas/Pet"}}}}}}`, string(data))
+
+	create := openapi.Paths.Paths["/stores/{storeId}/pets"].Operations.Operations[oas.POST]
+	assert.Equal(t, "addPet", create.OperationId)
+	assert.Equal(t, "CreatePet creates a pet", create.Summary)
+	assert.Equal(t, []string{"pets", "stores"}, create.Tags)
+	assert.True(t, create.Deprecated)
+	assert.Equal(t, "pet to create", create.RequestBody.Description)
+	assert.Equal(t, openapi.RefSchema("Pet"), create.RequestBody.Content["application/json"].Schema)
+	assert.Equal(t, "storeId", create.Parameters[0].Name)
+	assert.True(t, create.Parameters[0].Required)
+	assert.Equal(t, []int{201, 400, 409}, slices.Sorted(maps.Keys(create.Responses.Responses)))
+
+	data, err = json.Marshal(openapi.Schemas)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Pet":{"type":"object","description":"Pet in the store","properties":{
+			"id":{"type":"string","description":"ID of the pet"},
+			"name":{"type":"string"},
+			"status":{"$ref":"#/components/schemas/Status"},
+			"level":{"$ref":"#/components/schemas/Level"},
+			"tags":{"type":"array","items":{"type":"string"}},
+			"parent":{"$ref":"#/components/schemas/Pet"},
+			"createdAt":{"type":"string","format":"date-time"}
+		},"required":["id","name","status","createdAt"]},
+		"Status":{"type":"string","description":"Status of the pet","enum":["available","pending","sold"],"x-enum-labels":["StatusAvailable is available for sale","pending in an order","sold already"],"x-go-type":"github.com/go-courier/oas/gosrc/testdata/enums.Status"},
+		"Level":{"type":"integer","enum":[1,2,4],"x-enum-labels":["LevelLow","LevelMiddle","the highest level"],"x-go-type":"github.com/go-courier/oas/gosrc/testdata/enums.Level"},
+		"Error":{"type":"object","properties":{"message":{"type":"string"}},"required":["message"]}
+	}`, string(data))
+
+	assert.Error(t, s.Generate(openapi, "example.com/unknown"))
+}
@@ -0,0 +1,69 @@
+package gosrc
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps | packages.NeedModule
+
+// Scanner scans go packages loaded with syntax and types
+type Scanner struct {
+	packages map[string]*packages.Package
+}
+
+// NewScanner loads packages of patterns like "./...", relative to dir
+func NewScanner(dir string, patterns ...string) (*Scanner, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: dir}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Scanner{packages: map[string]*packages.Package{}}
+	errs := make([]string, 0)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		s.packages[pkg.PkgPath] = pkg
+		for _, e := range pkg.Errors {
+			errs = append(errs, e.Error())
+		}
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("load packages failed:\n%s", strings.Join(errs, "\n"))
+	}
+	return s, nil
+}
+
+// inMainModule returns true when the package in the main module
+func (s *Scanner) inMainModule(pkgPath string) bool {
+	pkg := s.packages[pkgPath]
+	return pkg != nil && pkg.Module != nil && pkg.Module.Main
+}
+
+// Package returns the loaded package of the import path
+func (s *Scanner) Package(pkgPath string) *packages.Package {
+	return s.packages[pkgPath]
+}
+
+// lookupType looks up named type by name like "github.com/x/y.Type"
+func (s *Scanner) lookupType(typeName string) (*packages.Package, *types.Named, error) {
+	i := strings.LastIndex(typeName, ".")
+	if i < 0 {
+		return nil, nil, fmt.Errorf("type name %q should be qualified by package path", typeName)
+	}
+	pkg := s.packages[typeName[:i]]
+	if pkg == nil {
+		return nil, nil, fmt.Errorf("package %q of type %q not loaded", typeName[:i], typeName)
+	}
+	obj, ok := pkg.Types.Scope().Lookup(typeName[i+1:]).(*types.TypeName)
+	if !ok {
+		return nil, nil, fmt.Errorf("type %q not found", typeName)
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, nil, fmt.Errorf("%q is not a named type", typeName)
+	}
+	return pkg, named, nil
+}
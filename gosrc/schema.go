@@ -0,0 +1,188 @@
+package gosrc
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+// schemaOf returns schema of the go type, named struct and enum types added into components and referred
+func (g *generator) schemaOf(t types.Type) *oas.Schema {
+	switch x := t.(type) {
+	case *types.Alias:
+		return g.schemaOf(types.Unalias(x))
+	case *types.Pointer:
+		return g.schemaOf(x.Elem())
+	case *types.Named:
+		return g.namedSchema(x)
+	case *types.Basic:
+		return basicSchema(x)
+	case *types.Slice:
+		if b, ok := x.Elem().(*types.Basic); ok && b.Kind() == types.Byte {
+			return oas.Byte()
+		}
+		return oas.ItemsOf(g.schemaOf(x.Elem()))
+	case *types.Array:
+		return oas.ItemsOf(g.schemaOf(x.Elem()))
+	case *types.Map:
+		return oas.MapOf(g.schemaOf(x.Elem()))
+	case *types.Struct:
+		return g.structSchema(x)
+	}
+	return &oas.Schema{}
+}
+
+func (g *generator) namedSchema(named *types.Named) *oas.Schema {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		// predeclared error
+		return oas.String()
+	}
+
+	typeName := obj.Pkg().Path() + "." + obj.Name()
+	if typeName == "time.Time" {
+		return oas.DateTime()
+	}
+	if name, ok := g.names[typeName]; ok {
+		return g.openapi.RefSchema(name)
+	}
+
+	switch named.Underlying().(type) {
+	case *types.Struct:
+	case *types.Basic:
+		if !g.scanner.inMainModule(obj.Pkg().Path()) {
+			return g.schemaOf(named.Underlying())
+		}
+		s, err := g.scanner.EnumSchema(typeName)
+		if err != nil {
+			// not enum
+			return g.schemaOf(named.Underlying())
+		}
+		s.Description = g.docs[obj.Pos()]
+		return g.openapi.RefSchema(g.addSchema(typeName, obj, s))
+	default:
+		return g.schemaOf(named.Underlying())
+	}
+
+	// added before properties collected, for circular refs
+	s := oas.ObjectOf(oas.Props{})
+	name := g.addSchema(typeName, obj, s)
+	*s = *g.structSchema(named.Underlying().(*types.Struct))
+	s.Description = g.docs[obj.Pos()]
+	return g.openapi.RefSchema(name)
+}
+
+func (g *generator) addSchema(typeName string, obj *types.TypeName, s *oas.Schema) string {
+	name := obj.Name()
+	if g.openapi.Schemas[name] != nil {
+		name = upperFirst(obj.Pkg().Name()) + name
+	}
+	g.names[typeName] = name
+	g.openapi.AddSchema(name, s)
+	return name
+}
+
+func (g *generator) structSchema(st *types.Struct) *oas.Schema {
+	s := oas.ObjectOf(oas.Props{})
+	g.collectProps(s, st)
+	return s
+}
+
+func (g *generator) collectProps(s *oas.Schema, st *types.Struct) {
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		name, omitempty, skip := jsonName(f, tag)
+		if skip {
+			continue
+		}
+
+		if f.Embedded() && name == "" {
+			t := f.Type()
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem()
+			}
+			if embedded, ok := t.Underlying().(*types.Struct); ok {
+				g.collectProps(s, embedded)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name()
+		}
+		prop := g.schemaOf(f.Type())
+		if desc := g.docs[f.Pos()]; desc != "" {
+			prop = withDesc(prop, desc)
+		}
+		_, isPtr := f.Type().(*types.Pointer)
+		s.SetProperty(name, prop, !omitempty && !isPtr)
+	}
+}
+
+// withDesc adds description, refs wrapped by allOf to keep the description
+func withDesc(s *oas.Schema, desc string) *oas.Schema {
+	if s.Refer != nil {
+		return oas.AllOf(s).WithDesc(desc)
+	}
+	return s.WithDesc(desc)
+}
+
+func jsonName(f *types.Var, tag reflect.StructTag) (name string, omitempty bool, skip bool) {
+	if !f.Exported() && !f.Embedded() {
+		return "", false, true
+	}
+	v, ok := tag.Lookup("json")
+	if !ok {
+		return "", false, !f.Exported()
+	}
+	parts := strings.Split(v, ",")
+	if parts[0] == "-" && len(parts) == 1 {
+		return "", false, true
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+	return parts[0], omitempty, false
+}
+
+func basicSchema(b *types.Basic) *oas.Schema {
+	switch b.Kind() {
+	case types.Bool, types.UntypedBool:
+		return oas.Boolean()
+	case types.String, types.UntypedString:
+		return oas.String()
+	case types.Int8:
+		return oas.Int8()
+	case types.Int16:
+		return oas.Int16()
+	case types.Int32:
+		return oas.Integer()
+	case types.Int, types.Int64, types.UntypedInt:
+		return oas.Long()
+	case types.Uint8:
+		return oas.Uint8()
+	case types.Uint16:
+		return oas.Uint16()
+	case types.Uint32:
+		return oas.Uint32()
+	case types.Uint, types.Uint64, types.Uintptr:
+		return oas.Uint64()
+	case types.Float32:
+		return oas.Float()
+	case types.Float64, types.UntypedFloat:
+		return oas.Double()
+	}
+	return &oas.Schema{}
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
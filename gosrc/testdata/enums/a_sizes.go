@@ -0,0 +1,4 @@
+package enums
+
+// SizeSmall declared before SizeLarge, since the file name sorted first
+const SizeSmall Size = "small"
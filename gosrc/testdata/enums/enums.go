@@ -0,0 +1,34 @@
+package enums
+
+// Status of the pet
+type Status string
+
+const (
+	// StatusAvailable is available for sale
+	StatusAvailable Status = "available"
+	StatusPending   Status = "pending" // pending in an order
+	// sold already
+	StatusSold Status = "sold"
+
+	// StatusDefault is alias of StatusAvailable
+	StatusDefault = StatusAvailable
+)
+
+// StatusUnknown is not of type Status
+const StatusUnknown = "unknown"
+
+type Level int
+
+const (
+	LevelLow Level = iota + 1
+	LevelMiddle
+	_
+	// the highest level
+	LevelHigh
+)
+
+type Empty string
+
+type Size string
+
+const SizeLarge Size = "large"
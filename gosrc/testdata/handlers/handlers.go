@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-courier/oas/gosrc/testdata/enums"
+)
+
+// Pet in the store
+type Pet struct {
+	// ID of the pet
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Status enums.Status `json:"status"`
+	Level  enums.Level  `json:"level,omitempty"`
+	Tags   []string     `json:"tags,omitempty"`
+	Parent *Pet         `json:"parent,omitempty"`
+	Audit
+	internal string
+}
+
+type Audit struct {
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+type ListPetsRequest struct {
+	// max count of pets
+	Limit  int          `in:"query" name:"limit"`
+	Status enums.Status `in:"query" json:"status" required:"true"`
+	Token  string       `in:"header" name:"Authorization"`
+}
+
+// ListPets lists pets
+//
+// Pets are sorted by name.
+//
+//oas:route GET /pets
+//oas:tags pets
+func ListPets(ctx context.Context, req *ListPetsRequest) ([]*Pet, error) {
+	return nil, nil
+}
+
+type CreatePetRequest struct {
+	StoreID string `in:"path" name:"storeId"`
+	// pet to create
+	Pet Pet `in:"body"`
+}
+
+// CreatePet creates a pet
+//
+//oas:route POST /stores/{storeId}/pets
+//oas:id addPet
+//oas:tags pets stores
+//oas:status 201
+//oas:response 409 Error
+//oas:response 400 -
+//oas:deprecated
+func CreatePet(ctx context.Context, req CreatePetRequest) (*Pet, error) {
+	return nil, nil
+}
+
+// Ping is not annotated
+func Ping() error {
+	return nil
+}
@@ -0,0 +1,148 @@
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HAR is the HTTP Archive recorded by browsers or proxies, only fields used for inference declared
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Entries []*Entry `json:"entries"`
+}
+
+type Entry struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Headers  []NameValue `json:"headers"`
+	Cookies  []NameValue `json:"cookies"`
+	PostData *PostData   `json:"postData,omitempty"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type Response struct {
+	Status  int         `json:"status"`
+	Headers []NameValue `json:"headers"`
+	Content Content     `json:"content"`
+}
+
+type Content struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	// Encoding is "base64" when text is encoded
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Exchange is a recorded pair of request and response
+type Exchange struct {
+	Method         string
+	URL            *url.URL
+	RequestHeader  http.Header
+	RequestBody    []byte
+	StatusCode     int
+	ResponseHeader http.Header
+	ResponseBody   []byte
+}
+
+// Decode reads HAR and converts entries into exchanges
+func Decode(r io.Reader) ([]*Exchange, error) {
+	h := &HAR{}
+	if err := json.NewDecoder(r).Decode(h); err != nil {
+		return nil, fmt.Errorf("decode har failed: %w", err)
+	}
+	return h.Exchanges()
+}
+
+// Exchanges converts entries into exchanges
+func (h *HAR) Exchanges() ([]*Exchange, error) {
+	exchanges := make([]*Exchange, 0, len(h.Log.Entries))
+	for i, e := range h.Log.Entries {
+		ex, err := e.Exchange()
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		exchanges = append(exchanges, ex)
+	}
+	return exchanges, nil
+}
+
+// Exchange converts the entry, mime types of bodies and cookies added into headers when missing
+func (e *Entry) Exchange() (*Exchange, error) {
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	ex := &Exchange{
+		Method:         strings.ToUpper(e.Request.Method),
+		URL:            u,
+		RequestHeader:  headerOf(e.Request.Headers),
+		StatusCode:     e.Response.Status,
+		ResponseHeader: headerOf(e.Response.Headers),
+	}
+
+	if ex.RequestHeader.Get("Cookie") == "" && len(e.Request.Cookies) > 0 {
+		cookies := make([]string, len(e.Request.Cookies))
+		for i, c := range e.Request.Cookies {
+			cookies[i] = c.Name + "=" + c.Value
+		}
+		ex.RequestHeader.Set("Cookie", strings.Join(cookies, "; "))
+	}
+
+	if pd := e.Request.PostData; pd != nil {
+		ex.RequestBody = []byte(pd.Text)
+		if ex.RequestHeader.Get("Content-Type") == "" && pd.MimeType != "" {
+			ex.RequestHeader.Set("Content-Type", pd.MimeType)
+		}
+	}
+
+	content := e.Response.Content
+	if content.Encoding == "base64" {
+		data, err := base64.StdEncoding.DecodeString(content.Text)
+		if err != nil {
+			return nil, fmt.Errorf("decode response content failed: %w", err)
+		}
+		ex.ResponseBody = data
+	} else {
+		ex.ResponseBody = []byte(content.Text)
+	}
+	if ex.ResponseHeader.Get("Content-Type") == "" && content.MimeType != "" {
+		ex.ResponseHeader.Set("Content-Type", content.MimeType)
+	}
+
+	return ex, nil
+}
+
+func headerOf(values []NameValue) http.Header {
+	h := http.Header{}
+	for _, nv := range values {
+		// pseudo headers of http2 like :authority
+		if strings.HasPrefix(nv.Name, ":") {
+			continue
+		}
+		h.Add(nv.Name, nv.Value)
+	}
+	return h
+}
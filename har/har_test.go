@@ -0,0 +1,48 @@
+package har
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	exchanges, err := Decode(strings.NewReader(`{
+  "log": {
+    "entries": [
+      {
+        "request": {
+          "method": "post",
+          "url": "https://api.example.com/pets?dryRun=true",
+          "headers": [{"name": ":authority", "value": "api.example.com"}, {"name": "X-Request-Id", "value": "1"}],
+          "cookies": [{"name": "session", "value": "abc"}],
+          "postData": {"mimeType": "application/json", "text": "{\"name\":\"kitty\"}"}
+        },
+        "response": {
+          "status": 201,
+          "headers": [],
+          "content": {"mimeType": "application/json", "text": "eyJpZCI6MX0=", "encoding": "base64"}
+        }
+      }
+    ]
+  }
+}`))
+	assert.NoError(t, err)
+	assert.Len(t, exchanges, 1)
+
+	ex := exchanges[0]
+	assert.Equal(t, "POST", ex.Method)
+	assert.Equal(t, "/pets", ex.URL.Path)
+	assert.Equal(t, "1", ex.RequestHeader.Get("X-Request-Id"))
+	assert.Empty(t, ex.RequestHeader.Get(":authority"))
+	assert.Equal(t, "session=abc", ex.RequestHeader.Get("Cookie"))
+	assert.Equal(t, "application/json", ex.RequestHeader.Get("Content-Type"))
+	assert.Equal(t, `{"name":"kitty"}`, string(ex.RequestBody))
+	assert.Equal(t, 201, ex.StatusCode)
+	assert.Equal(t, `{"id":1}`, string(ex.ResponseBody))
+	assert.Equal(t, "application/json", ex.ResponseHeader.Get("Content-Type"))
+
+	_, err = Decode(strings.NewReader(`{"log":{"entries":[{"request":{"url":"://"}}]}}`))
+	assert.Error(t, err)
+}
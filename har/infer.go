@@ -0,0 +1,333 @@
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+const (
+	// ExtensionSamples is the count of recorded samples the object inferred from
+	ExtensionSamples = "x-inferred-samples"
+	// ExtensionConfidence is the ratio of samples supporting the inference, between 0 and 1,
+	// presence ratio for parameters and properties, and how likely to be a parameter for path parameters.
+	ExtensionConfidence = "x-inferred-confidence"
+)
+
+func init() {
+	oas.RegisterExtension[int](ExtensionSamples)
+	oas.RegisterExtension[float64](ExtensionConfidence, "Parameter", "Schema")
+}
+
+// Inferrer infers openapi from recorded exchanges
+type Inferrer struct {
+	// ClusterThreshold is the min count of distinct values to cluster a literal path segment into a parameter, 0 to disable
+	ClusterThreshold int
+	// RequiredRatio is the min presence ratio of required parameters and properties
+	RequiredRatio float64
+	// MinEnumSamples is the min count of samples to infer enum of strings
+	MinEnumSamples int
+	// MaxEnumValues is the max count of distinct values of enum
+	MaxEnumValues int
+	// IgnoreHeaders are request headers not collected as parameters.
+	// Values of cookies, and of headers or query parameters named like credentials, are never inferred as enum.
+	IgnoreHeaders []string
+}
+
+func NewInferrer() *Inferrer {
+	return &Inferrer{
+		ClusterThreshold: 10,
+		RequiredRatio:    1,
+		MinEnumSamples:   5,
+		MaxEnumValues:    10,
+		IgnoreHeaders: []string{
+			"Accept", "Accept-Encoding", "Accept-Language", "Api-Key", "Authorization", "Cache-Control",
+			"Connection", "Content-Length", "Content-Type", "Cookie", "Host", "Origin", "Pragma",
+			"Proxy-Authorization", "Referer", "Te", "Upgrade-Insecure-Requests", "User-Agent",
+			"X-Access-Token", "X-Amz-Security-Token", "X-Api-Key", "X-Auth-Token", "X-Csrf-Token", "X-Xsrf-Token",
+		},
+	}
+}
+
+// Infer infers openapi from exchanges by the default inferrer
+func Infer(exchanges ...*Exchange) *oas.OpenAPI {
+	return NewInferrer().Infer(exchanges...)
+}
+
+// Infer infers openapi from exchanges.
+// Paths are templated by ids and clustered segments, operations grouped by method and path template,
+// parameters collected from path, query, headers and cookies, and schemas of json bodies merged from all samples.
+// Exchanges without status, like aborted requests, are ignored.
+func (inf *Inferrer) Infer(exchanges ...*Exchange) *oas.OpenAPI {
+	openapi := oas.NewOpenAPI()
+
+	paths := make([]string, 0)
+	servers := map[string]bool{}
+	for _, ex := range exchanges {
+		if ex.StatusCode == 0 {
+			continue
+		}
+		if !slices.Contains(paths, ex.URL.Path) {
+			paths = append(paths, ex.URL.Path)
+		}
+		if ex.URL.Host != "" {
+			servers[ex.URL.Scheme+"://"+ex.URL.Host] = true
+		}
+	}
+
+	for _, s := range slices.Sorted(maps.Keys(servers)) {
+		openapi.AddServer(oas.NewServer(s))
+	}
+
+	templates := templatePaths(paths, inf.ClusterThreshold)
+	groups := map[string]*group{}
+	for _, ex := range exchanges {
+		if ex.StatusCode == 0 {
+			continue
+		}
+		t := templates[ex.URL.Path]
+		key := ex.Method + " " + t.path
+		if groups[key] == nil {
+			groups[key] = &group{method: ex.Method, template: t}
+		}
+		groups[key].exchanges = append(groups[key].exchanges, ex)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		g := groups[key]
+		openapi.AddOperation(oas.HttpMethod(strings.ToLower(g.method)), g.template.path, inf.operation(g))
+	}
+
+	return openapi
+}
+
+// group is exchanges of the same operation
+type group struct {
+	method    string
+	template  *template
+	exchanges []*Exchange
+}
+
+// occurrences of a parameter
+type occurrences struct {
+	present int
+	multi   bool
+	obs     *observation
+}
+
+func (inf *Inferrer) operation(g *group) *oas.Operation {
+	op := oas.NewOperation(operationId(g.method, g.template.path))
+	n := len(g.exchanges)
+	op.AddExtension(ExtensionSamples, n)
+
+	for i, name := range g.template.names {
+		obs := newObservation()
+		distinct := map[string]bool{}
+		ids := 0
+		for _, ex := range g.exchanges {
+			v := g.template.values(ex.URL.Path)[i]
+			obs.observeText(v, inf)
+			distinct[v] = true
+			if isIdentifier(v) {
+				ids++
+			}
+		}
+		p := oas.PathParameter(name, obs.schema(inf))
+		annotate(&p.SpecExtensions, n, max(float64(ids)/float64(n), 1-1/float64(len(distinct))))
+		op.AddParameter(p)
+	}
+
+	query := map[string]*occurrences{}
+	headers := map[string]*occurrences{}
+	cookies := map[string]*occurrences{}
+
+	for _, ex := range g.exchanges {
+		for name, values := range ex.URL.Query() {
+			inf.occur(query, name, values, false)
+		}
+		for name, values := range ex.RequestHeader {
+			if slices.Contains(inf.IgnoreHeaders, http.CanonicalHeaderKey(name)) {
+				continue
+			}
+			inf.occur(headers, http.CanonicalHeaderKey(name), values, false)
+		}
+		req := &http.Request{Header: ex.RequestHeader}
+		seen := map[string][]string{}
+		for _, c := range req.Cookies() {
+			seen[c.Name] = append(seen[c.Name], c.Value)
+		}
+		for name, values := range seen {
+			inf.occur(cookies, name, values, true)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(query)) {
+		op.AddParameter(inf.parameter(oas.PositionQuery, name, query[name], n))
+	}
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		op.AddParameter(inf.parameter(oas.PositionHeader, name, headers[name], n))
+	}
+	for _, name := range slices.Sorted(maps.Keys(cookies)) {
+		op.AddParameter(inf.parameter(oas.PositionCookie, name, cookies[name], n))
+	}
+
+	requests := newBodies()
+	for _, ex := range g.exchanges {
+		requests.observe(ex.RequestHeader, ex.RequestBody, inf)
+	}
+	if requests.count > 0 {
+		rb := oas.NewRequestBody("", float64(requests.count)/float64(n) >= inf.RequiredRatio)
+		rb.AddExtension(ExtensionSamples, requests.count)
+		for _, ct := range slices.Sorted(maps.Keys(requests.contents)) {
+			rb = rb.WithSchema(ct, requests.schema(ct, inf))
+		}
+		op.SetRequestBody(rb)
+	}
+
+	responses := map[int][]*Exchange{}
+	for _, ex := range g.exchanges {
+		responses[ex.StatusCode] = append(responses[ex.StatusCode], ex)
+	}
+	for code, list := range responses {
+		desc := http.StatusText(code)
+		if desc == "" {
+			desc = fmt.Sprintf("status %d", code)
+		}
+		r := oas.NewResponse(desc)
+		r.AddExtension(ExtensionSamples, len(list))
+
+		bodies := newBodies()
+		for _, ex := range list {
+			bodies.observe(ex.ResponseHeader, ex.ResponseBody, inf)
+		}
+		for _, ct := range slices.Sorted(maps.Keys(bodies.contents)) {
+			r = r.WithSchema(ct, bodies.schema(ct, inf))
+		}
+		op.AddResponse(code, r)
+	}
+
+	return op
+}
+
+// occur observes values of the name, secret ones not inferred as enum
+func (inf *Inferrer) occur(m map[string]*occurrences, name string, values []string, secret bool) {
+	o := m[name]
+	if o == nil {
+		o = &occurrences{obs: newNamedObservation(name, secret)}
+		m[name] = o
+	}
+	o.present++
+	o.multi = o.multi || len(values) > 1
+	for _, v := range values {
+		o.obs.observeText(v, inf)
+	}
+}
+
+func (inf *Inferrer) parameter(in oas.Position, name string, o *occurrences, samples int) *oas.Parameter {
+	s := o.obs.schema(inf)
+	if o.multi {
+		s = oas.ItemsOf(s)
+	}
+	ratio := float64(o.present) / float64(samples)
+	required := ratio >= inf.RequiredRatio
+
+	var p *oas.Parameter
+	switch in {
+	case oas.PositionHeader:
+		p = oas.HeaderParameter(name, s, required)
+	case oas.PositionCookie:
+		p = oas.CookieParameter(name, s, required)
+	default:
+		p = oas.QueryParameter(name, s, required)
+	}
+	annotate(&p.SpecExtensions, o.present, ratio)
+	return p
+}
+
+// bodies are observations of bodies by content type
+type bodies struct {
+	count    int
+	contents map[string]int
+	obs      map[string]*observation
+}
+
+func newBodies() *bodies {
+	return &bodies{contents: map[string]int{}, obs: map[string]*observation{}}
+}
+
+func (b *bodies) observe(header http.Header, body []byte, inf *Inferrer) {
+	if len(body) == 0 {
+		return
+	}
+	b.count++
+
+	ct := "application/octet-stream"
+	if mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type")); err == nil {
+		ct = mediaType
+	}
+	b.contents[ct]++
+
+	switch {
+	case isJSON(ct):
+		d := json.NewDecoder(bytes.NewReader(body))
+		d.UseNumber()
+		var v interface{}
+		if err := d.Decode(&v); err != nil {
+			return
+		}
+		b.observation(ct).observeJSON(v, inf)
+	case ct == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return
+		}
+		obs := b.observation(ct)
+		obs.count++
+		obs.objects++
+		obs.types[oas.TypeObject]++
+		if obs.props == nil {
+			obs.props = map[string]*observation{}
+		}
+		for name, list := range values {
+			if obs.props[name] == nil {
+				obs.props[name] = newNamedObservation(name, false)
+			}
+			obs.props[name].observeText(list[0], inf)
+		}
+	}
+}
+
+func (b *bodies) observation(ct string) *observation {
+	if b.obs[ct] == nil {
+		b.obs[ct] = newObservation()
+	}
+	return b.obs[ct]
+}
+
+func (b *bodies) schema(ct string, inf *Inferrer) *oas.Schema {
+	if obs := b.obs[ct]; obs != nil {
+		return obs.schema(inf)
+	}
+	if strings.HasPrefix(ct, "text/") {
+		return oas.String()
+	}
+	return oas.Binary()
+}
+
+func isJSON(ct string) bool {
+	return ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
+
+// annotate adds samples and confidence into extensions
+func annotate(ext *oas.SpecExtensions, samples int, confidence float64) {
+	ext.AddExtension(ExtensionSamples, samples)
+	ext.AddExtension(ExtensionConfidence, confidence)
+}
@@ -0,0 +1,183 @@
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+func exchange(method string, rawURL string, status int, body string) *Exchange {
+	u, _ := url.Parse(rawURL)
+	ex := &Exchange{
+		Method:         method,
+		URL:            u,
+		RequestHeader:  http.Header{},
+		StatusCode:     status,
+		ResponseHeader: http.Header{},
+	}
+	if body != "" {
+		ex.ResponseHeader.Set("Content-Type", "application/json; charset=utf-8")
+		ex.ResponseBody = []byte(body)
+	}
+	return ex
+}
+
+func TestInfer(t *testing.T) {
+	statuses := []string{"available", "pending", "sold"}
+	exchanges := make([]*Exchange, 0)
+
+	for i := 1; i <= 6; i++ {
+		tag := ""
+		if i%2 == 0 {
+			tag = `,"tag":"cat"`
+		}
+		ex := exchange("GET", fmt.Sprintf("https://api.example.com/pets/%d?fields=name&fields=tag", i), http.StatusOK, fmt.Sprintf(
+			`{"id":%d,"name":"pet%d","status":%q,"createdAt":"2024-01-0%dT00:00:00Z","owner":"0b6e3f2c-9a1e-4d7c-8f55-3c2a1b0e9d4f","weight":%s,"photos":[{"url":"a"}],"parent":null%s}`,
+			i, i, statuses[i%3], i, "1.5", tag,
+		))
+		ex.RequestHeader.Set("X-Request-Id", fmt.Sprint(i))
+		ex.RequestHeader.Set("User-Agent", "curl")
+		ex.RequestHeader.Set("Cookie", "session=abc")
+		exchanges = append(exchanges, ex)
+	}
+	exchanges = append(exchanges,
+		exchange("GET", "https://api.example.com/pets/404", http.StatusNotFound, `{"message":"not found"}`),
+		exchange("GET", "https://api.example.com/pets/7", 0, ""),
+	)
+
+	create := exchange("POST", "https://api.example.com/pets", http.StatusCreated, `{"id":8,"weight":2}`)
+	create.RequestHeader.Set("Content-Type", "application/json")
+	create.RequestBody = []byte(`{"name":"kitty"}`)
+	exchanges = append(exchanges, create)
+
+	openapi := Infer(exchanges...)
+
+	assert.Equal(t, "https://api.example.com", openapi.Servers[0].URL)
+
+	op := openapi.Paths.Paths["/pets/{petId}"].Operations.Operations[oas.GET]
+	assert.Equal(t, "getPetsByPetId", op.OperationId)
+	samples, _ := oas.Extension[int](op.SpecExtensions, ExtensionSamples)
+	assert.Equal(t, 7, samples)
+
+	t.Run("parameters", func(t *testing.T) {
+		assert.Len(t, op.Parameters, 4)
+
+		path := op.Parameters[0]
+		assert.Equal(t, "petId", path.Name)
+		assert.Equal(t, oas.TypeInteger, path.Schema.Type)
+		confidence, _ := oas.Extension[float64](path.SpecExtensions, ExtensionConfidence)
+		assert.Equal(t, 1.0, confidence)
+
+		query := op.Parameters[1]
+		assert.Equal(t, "fields", query.Name)
+		assert.Equal(t, oas.TypeArray, query.Schema.Type)
+		assert.False(t, query.Required)
+
+		header := op.Parameters[2]
+		assert.Equal(t, oas.PositionHeader, header.In)
+		assert.Equal(t, "X-Request-Id", header.Name)
+
+		cookie := op.Parameters[3]
+		assert.Equal(t, oas.PositionCookie, cookie.In)
+		assert.Equal(t, "session", cookie.Name)
+		confidence, _ = oas.Extension[float64](cookie.SpecExtensions, ExtensionConfidence)
+		assert.InDelta(t, 6.0/7, confidence, 0.001)
+	})
+
+	t.Run("response schemas", func(t *testing.T) {
+		s := op.Responses.Responses[200].Content["application/json"].Schema
+		assert.Equal(t, oas.TypeObject, s.Type)
+		assert.ElementsMatch(t, []string{"id", "name", "status", "createdAt", "owner", "weight", "photos", "parent"}, s.Required)
+
+		props := s.Properties
+		assert.Equal(t, oas.TypeInteger, props["id"].Type)
+		assert.Equal(t, oas.TypeString, props["name"].Type)
+		assert.Empty(t, props["name"].Enum)
+		assert.Equal(t, []interface{}{"available", "pending", "sold"}, props["status"].Enum)
+		assert.Equal(t, "date-time", props["createdAt"].Format)
+		assert.Equal(t, "uuid", props["owner"].Format)
+		assert.Equal(t, oas.TypeNumber, props["weight"].Type)
+		assert.Equal(t, oas.TypeString, props["photos"].Items.Properties["url"].Type)
+		assert.True(t, props["parent"].Nullable)
+
+		confidence, _ := oas.Extension[float64](props["tag"].SpecExtensions, ExtensionConfidence)
+		assert.Equal(t, 0.5, confidence)
+
+		notFound := op.Responses.Responses[404].Content["application/json"].Schema
+		assert.Equal(t, []string{"message"}, notFound.Required)
+	})
+
+	t.Run("request body", func(t *testing.T) {
+		op := openapi.Paths.Paths["/pets"].Operations.Operations[oas.POST]
+		assert.Equal(t, "postPets", op.OperationId)
+		assert.True(t, op.RequestBody.Required)
+		s := op.RequestBody.Content["application/json"].Schema
+		assert.Equal(t, []string{"name"}, s.Required)
+
+		weight := op.Responses.Responses[201].Content["application/json"].Schema.Properties["weight"]
+		assert.Equal(t, oas.TypeInteger, weight.Type)
+	})
+}
+
+func TestInferMixedTypes(t *testing.T) {
+	openapi := Infer(
+		exchange("GET", "/values", http.StatusOK, `{"v":1}`),
+		exchange("GET", "/values", http.StatusOK, `{"v":1.5}`),
+		exchange("GET", "/values", http.StatusOK, `{"v":"x"}`),
+	)
+	v := openapi.Paths.Paths["/values"].Operations.Operations[oas.GET].Responses.Responses[200].Content["application/json"].Schema.Properties["v"]
+	assert.Len(t, v.AnyOf, 2)
+	assert.Equal(t, oas.TypeNumber, v.AnyOf[0].Type)
+	assert.Equal(t, oas.TypeString, v.AnyOf[1].Type)
+}
+
+func TestInferCredentials(t *testing.T) {
+	credentials := []string{"ck-session", "dark", "ak-header", "bearer-token", "sess-header", "qk-query", "tk-body", "pw-form"}
+
+	exchanges := make([]*Exchange, 0)
+	for i := 0; i < 6; i++ {
+		ex := exchange("POST", "https://api.example.com/login?api_key=qk-query&page=1", http.StatusOK, `{"access_token":"tk-body","kind":"user"}`)
+		ex.RequestHeader.Set("Cookie", "session=ck-session; theme=dark")
+		ex.RequestHeader.Set("X-Api-Key", "ak-header")
+		ex.RequestHeader.Set("Authorization", "Bearer bearer-token")
+		ex.RequestHeader.Set("X-Session-Id", "sess-header")
+		ex.RequestHeader.Set("Content-Type", "application/x-www-form-urlencoded")
+		ex.RequestBody = []byte("user=a&password=pw-form")
+		exchanges = append(exchanges, ex)
+	}
+
+	openapi := Infer(exchanges...)
+	data, err := json.Marshal(openapi)
+	assert.NoError(t, err)
+
+	for _, credential := range credentials {
+		assert.NotContains(t, string(data), credential)
+	}
+
+	op := openapi.Paths.Paths["/login"].Operations.Operations[oas.POST]
+	names := make([]string, 0)
+	for _, p := range op.Parameters {
+		names = append(names, string(p.In)+" "+p.Name)
+	}
+	assert.Equal(t, []string{"query api_key", "query page", "header X-Session-Id", "cookie session", "cookie theme"}, names)
+
+	// enum still inferred for names not of credentials
+	kind := op.Responses.Responses[200].Content["application/json"].Schema.Properties["kind"]
+	assert.Equal(t, []interface{}{"user"}, kind.Enum)
+}
+
+func TestIsCredential(t *testing.T) {
+	for _, name := range []string{"api_key", "X-Api-Key", "apikey", "accessToken", "X-Session-Id", "sessionid", "password", "AUTH_TOKEN", "clientSecret", "Authorization"} {
+		assert.True(t, isCredential(name), name)
+	}
+	for _, name := range []string{"monkey", "keyword", "author", "tokenizer", "keys", "sessions", "authority", "donkeyName"} {
+		assert.False(t, isCredential(name), name)
+	}
+
+	assert.Equal(t, []string{"xml", "http", "request", "id"}, nameWords("XMLHttpRequest_id"))
+}
@@ -0,0 +1,200 @@
+package har
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// template is the path template of recorded paths, with positions of parameters in segments
+type template struct {
+	path      string
+	positions []int
+	names     []string
+}
+
+// values returns values of parameters in the recorded path
+func (t *template) values(path string) []string {
+	segs := splitPath(path)
+	values := make([]string, len(t.positions))
+	for i, pos := range t.positions {
+		values[i] = segs[pos]
+	}
+	return values
+}
+
+// templatePaths infers templates of recorded paths.
+// Segments like ids or uuids are parameters,
+// and literal segments are clustered into a parameter when not the first segment,
+// and at least threshold distinct values found among paths only differs in the segment.
+func templatePaths(paths []string, threshold int) map[string]*template {
+	segments := map[string][]string{}
+	variables := map[string][]bool{}
+
+	for _, p := range paths {
+		segs := splitPath(p)
+		vars := make([]bool, len(segs))
+		for i, seg := range segs {
+			vars[i] = isIdentifier(seg)
+		}
+		segments[p], variables[p] = segs, vars
+	}
+
+	if threshold > 0 {
+		for pos := 1; ; pos++ {
+			found := false
+			groups := map[string]map[string]bool{}
+			keys := map[string]string{}
+
+			for _, p := range paths {
+				segs, vars := segments[p], variables[p]
+				if len(segs) <= pos {
+					continue
+				}
+				found = true
+				if vars[pos] || vars[pos-1] {
+					continue
+				}
+				key := clusterKey(segs, vars, pos)
+				if groups[key] == nil {
+					groups[key] = map[string]bool{}
+				}
+				groups[key][segs[pos]] = true
+				keys[p] = key
+			}
+			if !found {
+				break
+			}
+			for p, key := range keys {
+				if len(groups[key]) >= threshold {
+					variables[p][pos] = true
+				}
+			}
+		}
+	}
+
+	templates := map[string]*template{}
+	for _, p := range paths {
+		templates[p] = newTemplate(segments[p], variables[p])
+	}
+	return templates
+}
+
+func clusterKey(segs []string, vars []bool, pos int) string {
+	b := strings.Builder{}
+	b.WriteString(strconv.Itoa(len(segs)))
+	for i, seg := range segs {
+		b.WriteString("/")
+		switch {
+		case i == pos:
+			b.WriteString("*")
+		case vars[i]:
+			b.WriteString("{}")
+		default:
+			b.WriteString(seg)
+		}
+	}
+	return b.String()
+}
+
+func newTemplate(segs []string, vars []bool) *template {
+	t := &template{}
+	used := map[string]bool{}
+	b := strings.Builder{}
+
+	for i, seg := range segs {
+		b.WriteString("/")
+		if !vars[i] {
+			b.WriteString(seg)
+			continue
+		}
+		name := "id"
+		if i > 0 && !vars[i-1] {
+			if base := lowerCamel(singular(segs[i-1])); base != "" {
+				name = base + "Id"
+			}
+		}
+		for n := 2; used[name]; n++ {
+			name = strings.TrimRight(name, "0123456789") + strconv.Itoa(n)
+		}
+		used[name] = true
+		t.positions = append(t.positions, i)
+		t.names = append(t.names, name)
+		b.WriteString("{" + name + "}")
+	}
+
+	t.path = b.String()
+	if t.path == "" {
+		t.path = "/"
+	}
+	return t
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+var (
+	reUUID    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	reNumeric = regexp.MustCompile(`^[0-9]+$`)
+	reHex     = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+	reToken   = regexp.MustCompile(`^[0-9A-Za-z_-]{16,}$`)
+)
+
+// isIdentifier returns true when the segment looks like an id,
+// numbers, uuids, long hex strings or long random tokens mixed by letters and digits
+func isIdentifier(seg string) bool {
+	if reNumeric.MatchString(seg) || reUUID.MatchString(seg) || reHex.MatchString(seg) {
+		return true
+	}
+	return reToken.MatchString(seg) && strings.ContainsAny(seg, "0123456789") && strings.IndexFunc(seg, unicode.IsLetter) >= 0
+}
+
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return strings.TrimSuffix(s, "s")
+	}
+	return s
+}
+
+// lowerCamel converts words split by - _ . or spaces to lowerCamelCase
+func lowerCamel(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	b := strings.Builder{}
+	for i, w := range words {
+		if i == 0 {
+			b.WriteString(strings.ToLower(w[:1]) + w[1:])
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// operationId generates operationId like getPetsByPetId from method and template
+func operationId(method string, path string) string {
+	b := strings.Builder{}
+	b.WriteString(strings.ToLower(method))
+	for _, seg := range splitPath(path) {
+		if strings.HasPrefix(seg, "{") {
+			b.WriteString("By")
+			seg = strings.Trim(seg, "{}")
+		}
+		if w := lowerCamel(seg); w != "" {
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	return b.String()
+}
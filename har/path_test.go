@@ -0,0 +1,64 @@
+package har
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplatePaths(t *testing.T) {
+	paths := []string{
+		"/",
+		"/pets",
+		"/pets/1",
+		"/pets/2/photos/3",
+		"/pets/mine",
+		"/orders/0b6e3f2c-9a1e-4d7c-8f55-3c2a1b0e9d4f",
+		"/files/5f2b6c1e9a7d4b3c8e0f1a2b",
+		"/categories/42/items",
+	}
+	for i := 0; i < 3; i++ {
+		paths = append(paths, fmt.Sprintf("/users/user%c/repos", 'a'+i))
+	}
+
+	templates := templatePaths(paths, 3)
+
+	cases := map[string]string{
+		"/":                "/",
+		"/pets":            "/pets",
+		"/pets/1":          "/pets/{petId}",
+		"/pets/2/photos/3": "/pets/{petId}/photos/{photoId}",
+		"/pets/mine":       "/pets/mine",
+		"/orders/0b6e3f2c-9a1e-4d7c-8f55-3c2a1b0e9d4f": "/orders/{orderId}",
+		"/files/5f2b6c1e9a7d4b3c8e0f1a2b":              "/files/{fileId}",
+		"/categories/42/items":                         "/categories/{categoryId}/items",
+		"/users/usera/repos":                           "/users/{userId}/repos",
+	}
+	for p, tpl := range cases {
+		assert.Equal(t, tpl, templates[p].path, p)
+	}
+
+	assert.Equal(t, []string{"2", "3"}, templates["/pets/2/photos/3"].values("/pets/2/photos/3"))
+
+	t.Run("clustering disabled", func(t *testing.T) {
+		templates := templatePaths(paths, 0)
+		assert.Equal(t, "/users/usera/repos", templates["/users/usera/repos"].path)
+	})
+
+	t.Run("first segment never clustered", func(t *testing.T) {
+		templates := templatePaths([]string{"/pets", "/users", "/orders"}, 3)
+		assert.Equal(t, "/pets", templates["/pets"].path)
+	})
+
+	t.Run("duplicated names", func(t *testing.T) {
+		templates := templatePaths([]string{"/1/2"}, 0)
+		assert.Equal(t, "/{id}/{id2}", templates["/1/2"].path)
+	})
+}
+
+func TestOperationId(t *testing.T) {
+	assert.Equal(t, "getPetsByPetIdPhotos", operationId("GET", "/pets/{petId}/photos"))
+	assert.Equal(t, "postPetTypes", operationId("POST", "/pet-types"))
+	assert.Equal(t, "get", operationId("GET", "/"))
+}
@@ -0,0 +1,258 @@
+package har
+
+import (
+	"encoding/json"
+	"maps"
+	"net/mail"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-courier/oas"
+)
+
+// observation merges observed values of the same location, to infer the schema
+type observation struct {
+	// count of observed values, nulls included
+	count int
+	nulls int
+	types map[oas.Type]int
+
+	// observations of properties, and count of objects observed
+	objects int
+	props   map[string]*observation
+
+	items *observation
+
+	// distinct strings for enum candidates, nil when too many
+	strings map[string]int
+	// count of strings in formats
+	formats map[string]int
+}
+
+func newObservation() *observation {
+	return &observation{
+		types:   map[oas.Type]int{},
+		strings: map[string]int{},
+		formats: map[string]int{},
+	}
+}
+
+// newNamedObservation returns observation of values of the name,
+// values not collected for enum when secret, or the name looks like of credentials
+func newNamedObservation(name string, secret bool) *observation {
+	o := newObservation()
+	if secret || isCredential(name) {
+		o.strings = nil
+	}
+	return o
+}
+
+var credentialWords = []string{"apikey", "auth", "authorization", "key", "passwd", "password", "secret", "session", "sessionid", "token"}
+
+// isCredential returns true when the name looks like of credentials, like api_key, X-Session-Id or accessToken,
+// words are matched as whole, so that names like monkey or author are not
+func isCredential(name string) bool {
+	for _, word := range nameWords(name) {
+		if slices.Contains(credentialWords, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// nameWords splits the name into lower case words by non letters or digits and camel case boundaries,
+// like XMLHttpRequest to xml, http and request
+func nameWords(name string) []string {
+	words := make([]string, 0)
+	runes := []rune(name)
+
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, strings.ToLower(string(runes[start:end])))
+		}
+		start = end
+	}
+
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			if !unicode.IsUpper(prev) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				flush(i)
+			}
+		}
+	}
+	flush(len(runes))
+
+	return words
+}
+
+// observeJSON observes the json value decoded with UseNumber
+func (o *observation) observeJSON(v interface{}, opts *Inferrer) {
+	o.count++
+
+	switch x := v.(type) {
+	case nil:
+		o.nulls++
+	case bool:
+		o.types[oas.TypeBoolean]++
+	case json.Number:
+		if _, err := x.Int64(); err == nil {
+			o.types[oas.TypeInteger]++
+		} else {
+			o.types[oas.TypeNumber]++
+		}
+	case string:
+		o.types[oas.TypeString]++
+		o.observeString(x, opts)
+	case []interface{}:
+		o.types[oas.TypeArray]++
+		if o.items == nil {
+			o.items = newObservation()
+		}
+		for _, item := range x {
+			o.items.observeJSON(item, opts)
+		}
+	case map[string]interface{}:
+		o.types[oas.TypeObject]++
+		o.objects++
+		if o.props == nil {
+			o.props = map[string]*observation{}
+		}
+		for k, value := range x {
+			if o.props[k] == nil {
+				o.props[k] = newNamedObservation(k, false)
+			}
+			o.props[k].observeJSON(value, opts)
+		}
+	}
+}
+
+// observeText observes the text value of parameters, types inferred from the text
+func (o *observation) observeText(s string, opts *Inferrer) {
+	o.count++
+
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		o.types[oas.TypeInteger]++
+		return
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		o.types[oas.TypeNumber]++
+		return
+	}
+	if s == "true" || s == "false" {
+		o.types[oas.TypeBoolean]++
+		return
+	}
+	o.types[oas.TypeString]++
+	o.observeString(s, opts)
+}
+
+func (o *observation) observeString(s string, opts *Inferrer) {
+	if format := formatOf(s); format != "" {
+		o.formats[format]++
+	}
+	if o.strings != nil {
+		o.strings[s]++
+		if len(o.strings) > opts.MaxEnumValues {
+			o.strings = nil
+		}
+	}
+}
+
+func formatOf(s string) string {
+	if reUUID.MatchString(s) {
+		return "uuid"
+	}
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		return "date-time"
+	}
+	if _, err := time.Parse(time.DateOnly, s); err == nil {
+		return "date"
+	}
+	if a, err := mail.ParseAddress(s); err == nil && a.Name == "" && a.Address == s {
+		return "email"
+	}
+	return ""
+}
+
+// schema returns the inferred schema, mixed types are in anyOf
+func (o *observation) schema(opts *Inferrer) *oas.Schema {
+	if o.types[oas.TypeInteger] > 0 && o.types[oas.TypeNumber] > 0 {
+		o.types[oas.TypeNumber] += o.types[oas.TypeInteger]
+		delete(o.types, oas.TypeInteger)
+	}
+
+	types := make([]oas.Type, 0, len(o.types))
+	for t := range o.types {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	var s *oas.Schema
+	switch len(types) {
+	case 0:
+		s = &oas.Schema{}
+	case 1:
+		s = o.schemaOfType(types[0], opts)
+	default:
+		schemas := make([]*oas.Schema, len(types))
+		for i, t := range types {
+			schemas[i] = o.schemaOfType(t, opts)
+		}
+		s = oas.AnyOf(schemas...)
+	}
+	if o.nulls > 0 {
+		s.Nullable = true
+	}
+	return s
+}
+
+func (o *observation) schemaOfType(t oas.Type, opts *Inferrer) *oas.Schema {
+	switch t {
+	case oas.TypeObject:
+		s := oas.ObjectOf(oas.Props{})
+		for _, name := range slices.Sorted(maps.Keys(o.props)) {
+			prop := o.props[name]
+			ps := prop.schema(opts)
+			ratio := float64(prop.count) / float64(o.objects)
+			annotate(&ps.SpecExtensions, prop.count, ratio)
+			s.SetProperty(name, ps, ratio >= opts.RequiredRatio)
+		}
+		return s
+	case oas.TypeArray:
+		if o.items == nil {
+			return oas.ItemsOf(&oas.Schema{})
+		}
+		return oas.ItemsOf(o.items.schema(opts))
+	case oas.TypeString:
+		n := o.types[oas.TypeString]
+		for format, count := range o.formats {
+			if count == n {
+				return oas.NewSchema(oas.TypeString, format)
+			}
+		}
+		if o.strings != nil && n >= opts.MinEnumSamples && len(o.strings)*2 <= n {
+			values := make([]string, 0, len(o.strings))
+			for v := range o.strings {
+				values = append(values, v)
+			}
+			sort.Strings(values)
+			enum := make([]interface{}, len(values))
+			for i := range values {
+				enum[i] = values[i]
+			}
+			return oas.String().WithEnum(enum...)
+		}
+		return oas.String()
+	}
+	return oas.NewSchema(t, "")
+}
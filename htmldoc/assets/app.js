@@ -0,0 +1,589 @@
+(function () {
+  "use strict";
+
+  var METHODS = ["get", "put", "post", "delete", "options", "head", "patch", "trace"];
+
+  var spec = null;
+
+  function el(tag, attrs) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      if (k === "class") {
+        node.className = attrs[k];
+      } else if (k.indexOf("on") === 0) {
+        node.addEventListener(k.slice(2), attrs[k]);
+      } else if (attrs[k] !== undefined && attrs[k] !== null && attrs[k] !== false) {
+        node.setAttribute(k, attrs[k]);
+      }
+    });
+    for (var i = 2; i < arguments.length; i++) {
+      append(node, arguments[i]);
+    }
+    return node;
+  }
+
+  function append(node, child) {
+    if (child === undefined || child === null || child === false) {
+      return;
+    }
+    if (Array.isArray(child)) {
+      child.forEach(function (c) {
+        append(node, c);
+      });
+      return;
+    }
+    node.appendChild(typeof child === "object" ? child : document.createTextNode(String(child)));
+  }
+
+  function text(s) {
+    return s ? el("p", {}, s) : null;
+  }
+
+  // json pointer
+
+  function resolvePointer(ref) {
+    if (ref.indexOf("#/") !== 0) {
+      return undefined;
+    }
+    return ref.slice(2).split("/").reduce(function (v, token) {
+      if (v === undefined || v === null) {
+        return undefined;
+      }
+      return v[token.replace(/~1/g, "/").replace(/~0/g, "~")];
+    }, spec);
+  }
+
+  function resolve(obj) {
+    for (var i = 0; obj && obj.$ref && i < 32; i++) {
+      obj = resolvePointer(obj.$ref);
+    }
+    return obj;
+  }
+
+  function refName(ref) {
+    return ref.split("/").pop();
+  }
+
+  // schema explorer
+
+  function typeSummary(s) {
+    if (!s) {
+      return "any";
+    }
+    if (s.$ref) {
+      return refName(s.$ref);
+    }
+    var t;
+    if (s.allOf) {
+      t = "allOf(" + s.allOf.map(typeSummary).join(", ") + ")";
+    } else if (s.oneOf) {
+      t = "oneOf(" + s.oneOf.map(typeSummary).join(", ") + ")";
+    } else if (s.anyOf) {
+      t = "anyOf(" + s.anyOf.map(typeSummary).join(", ") + ")";
+    } else if (s.type === "array") {
+      t = "[]" + typeSummary(s.items);
+    } else if (s.type === "object" && !s.properties && s.additionalProperties && typeof s.additionalProperties === "object") {
+      t = "map[string]" + typeSummary(s.additionalProperties);
+    } else if (!s.type) {
+      t = "any";
+    } else {
+      t = s.type + (s.format ? "(" + s.format + ")" : "");
+    }
+    return s.nullable ? t + " (nullable)" : t;
+  }
+
+  function constraints(s) {
+    var list = [];
+    if (!s) {
+      return list;
+    }
+    if (s.enum) {
+      list.push("enum: " + s.enum.map(function (v) {
+        return JSON.stringify(v);
+      }).join(", "));
+    }
+    if (s.default !== undefined) {
+      list.push("default: " + JSON.stringify(s.default));
+    }
+    ["minimum", "maximum", "multipleOf", "minLength", "maxLength", "pattern", "minItems", "maxItems", "minProperties", "maxProperties"].forEach(function (k) {
+      if (s[k] !== undefined) {
+        list.push(k + ": " + s[k]);
+      }
+    });
+    ["exclusiveMinimum", "exclusiveMaximum", "uniqueItems", "readOnly", "writeOnly", "deprecated"].forEach(function (k) {
+      if (s[k]) {
+        list.push(k);
+      }
+    });
+    return list;
+  }
+
+  function hasChildren(s) {
+    return s && (s.$ref || s.properties || s.items || s.allOf || s.oneOf || s.anyOf ||
+      (s.additionalProperties && typeof s.additionalProperties === "object"));
+  }
+
+  function schemaLine(name, s, required) {
+    var resolved = resolve(s) || {};
+    var c = constraints(resolved);
+    return [
+      name ? el("code", {}, name) : null, " ",
+      el("span", {"class": "type"}, typeSummary(s)),
+      required ? el("span", {"class": "required"}, " required") : null,
+      resolved.description ? el("span", {"class": "desc"}, " " + resolved.description) : null,
+      c.length ? el("span", {"class": "desc"}, " (" + c.join(", ") + ")") : null
+    ];
+  }
+
+  // schemaNode renders schema as details, children rendered when expanded, so circular refs work
+  function schemaNode(name, s, required, open) {
+    if (!hasChildren(s)) {
+      return el("div", {}, schemaLine(name, s, required));
+    }
+    var details = el("details", {}, el("summary", {}, schemaLine(name, s, required)));
+    var rendered = false;
+    details.addEventListener("toggle", function () {
+      if (details.open && !rendered) {
+        rendered = true;
+        append(details, schemaChildren(s));
+      }
+    });
+    details.open = !!open;
+    return details;
+  }
+
+  function schemaChildren(s) {
+    var children = [];
+    s = resolve(s);
+    if (!s) {
+      return el("div", {"class": "error"}, "unresolved ref");
+    }
+    (s.allOf || []).forEach(function (sub) {
+      var resolved = resolve(sub);
+      if (resolved !== sub) {
+        children.push(schemaNode("allOf", sub, false));
+      } else {
+        children.push(schemaChildren(sub));
+      }
+    });
+    ["oneOf", "anyOf"].forEach(function (k) {
+      (s[k] || []).forEach(function (sub, i) {
+        children.push(schemaNode(k + "[" + i + "]", sub, false));
+      });
+    });
+    if (s.items) {
+      children.push(schemaNode("items", s.items, false));
+    }
+    var required = s.required || [];
+    Object.keys(s.properties || {}).sort().forEach(function (k) {
+      children.push(schemaNode(k, s.properties[k], required.indexOf(k) >= 0));
+    });
+    if (s.additionalProperties && typeof s.additionalProperties === "object") {
+      children.push(schemaNode("*", s.additionalProperties, false));
+    }
+    return el("div", {}, children);
+  }
+
+  function schemaExplorer(s) {
+    return el("div", {"class": "schema"}, schemaNode("", s, false, true));
+  }
+
+  // operations
+
+  function operationId(method, path) {
+    return "op-" + method + "-" + path.replace(/[^A-Za-z0-9_-]/g, "_");
+  }
+
+  function collectOperations() {
+    var tags = [];
+    var byTag = {};
+
+    function tagOf(name) {
+      if (!byTag[name]) {
+        var declared = (spec.tags || []).filter(function (t) {
+          return t.name === name;
+        })[0] || {name: name};
+        byTag[name] = {tag: declared, operations: []};
+        tags.push(byTag[name]);
+      }
+      return byTag[name];
+    }
+
+    (spec.tags || []).forEach(function (t) {
+      tagOf(t.name);
+    });
+
+    var declaredCount = tags.length;
+
+    Object.keys(spec.paths || {}).sort().forEach(function (path) {
+      var item = spec.paths[path] || {};
+      METHODS.forEach(function (method) {
+        var op = item[method];
+        if (!op) {
+          return;
+        }
+        var entry = {method: method, path: path, op: op, item: item};
+        (op.tags && op.tags.length ? op.tags : ["default"]).forEach(function (name) {
+          tagOf(name).operations.push(entry);
+        });
+      });
+    });
+
+    var undeclared = tags.slice(declaredCount).sort(function (a, b) {
+      return a.tag.name < b.tag.name ? -1 : 1;
+    });
+
+    return tags.slice(0, declaredCount).concat(undeclared).filter(function (t) {
+      return t.operations.length > 0;
+    });
+  }
+
+  function parametersOf(entry) {
+    var seen = {};
+    var params = [];
+    (entry.op.parameters || []).concat(entry.item.parameters || []).forEach(function (p) {
+      p = resolve(p);
+      if (!p || seen[p.in + "." + p.name]) {
+        return;
+      }
+      seen[p.in + "." + p.name] = true;
+      params.push(p);
+    });
+    return params;
+  }
+
+  function parameterSchema(p) {
+    if (p.schema) {
+      return p.schema;
+    }
+    var content = p.content || {};
+    var first = Object.keys(content)[0];
+    return first ? content[first].schema : undefined;
+  }
+
+  function parametersTable(params) {
+    return el("table", {},
+      el("thead", {}, el("tr", {}, ["Name", "In", "Required", "Type", "Description"].map(function (h) {
+        return el("th", {}, h);
+      }))),
+      el("tbody", {}, params.map(function (p) {
+        var s = parameterSchema(p);
+        var c = constraints(resolve(s));
+        return el("tr", {},
+          el("td", {}, el("code", {}, p.name), p.deprecated ? el("span", {"class": "badge"}, "deprecated") : null),
+          el("td", {}, p.in),
+          el("td", {}, p.required ? "yes" : "no"),
+          el("td", {}, s ? schemaExplorer(s) : el("code", {}, "any")),
+          el("td", {}, p.description || "", c.length ? " (" + c.join(", ") + ")" : ""));
+      })));
+  }
+
+  function contentSection(content) {
+    return Object.keys(content || {}).sort().map(function (ct) {
+      var mt = content[ct] || {};
+      var examples = [];
+      if (mt.example !== undefined) {
+        examples.push(el("div", {}, el("div", {}, "Example"), el("pre", {}, JSON.stringify(mt.example, null, 2))));
+      }
+      Object.keys(mt.examples || {}).sort().forEach(function (name) {
+        var e = resolve(mt.examples[name]) || {};
+        examples.push(el("div", {},
+          el("div", {}, "Example ", el("code", {}, name), e.summary ? ": " + e.summary : ""),
+          e.externalValue ? el("a", {href: e.externalValue}, e.externalValue) : el("pre", {}, JSON.stringify(e.value, null, 2))));
+      });
+      return el("div", {"class": "content"},
+        el("div", {}, el("code", {}, ct)),
+        mt.schema ? schemaExplorer(mt.schema) : null,
+        examples);
+    });
+  }
+
+  function securitySection(op) {
+    var reqs = op.security !== undefined ? op.security : spec.security;
+    if (reqs === undefined) {
+      return null;
+    }
+    var optional = reqs.length === 0 || reqs.some(function (r) {
+      return Object.keys(r).length === 0;
+    });
+    var list = reqs.filter(function (r) {
+      return Object.keys(r).length > 0;
+    }).map(function (r) {
+      return el("li", {}, Object.keys(r).sort().map(function (name, i) {
+        return [i > 0 ? " and " : "", el("code", {}, name), r[name].length ? " (" + r[name].join(", ") + ")" : ""];
+      }));
+    });
+    return el("section", {},
+      el("h4", {}, "Security"),
+      list.length ? el("p", {}, optional ? "Optional, any of:" : "Any of:") : el("p", {}, "No authentication required."),
+      list.length ? el("ul", {}, list) : null);
+  }
+
+  function responsesSection(responses) {
+    var keys = Object.keys(responses || {}).filter(function (k) {
+      return k.indexOf("x-") !== 0;
+    }).sort(function (a, b) {
+      if (a === "default") {
+        return 1;
+      }
+      if (b === "default") {
+        return -1;
+      }
+      return a < b ? -1 : 1;
+    });
+    return el("section", {},
+      el("h4", {}, "Responses"),
+      keys.map(function (status) {
+        var r = resolve(responses[status]) || {};
+        var headers = Object.keys(r.headers || {}).sort().map(function (name) {
+          var h = resolve(r.headers[name]) || {};
+          return Object.assign({name: name, in: "header"}, h);
+        });
+        return el("div", {"class": "response"},
+          el("h5", {}, status, " ", r.description || ""),
+          headers.length ? parametersTable(headers) : null,
+          contentSection(r.content));
+      }));
+  }
+
+  // try it
+
+  function servers(entry) {
+    var list = entry.op.servers || entry.item.servers || spec.servers || [{url: "/"}];
+    return list.map(function (s) {
+      return s.url.replace(/\{([^}]+)\}/g, function (m, name) {
+        var v = (s.variables || {})[name];
+        return v ? v.default : m;
+      });
+    });
+  }
+
+  function exampleBody(op) {
+    var rb = resolve(op.requestBody);
+    if (!rb || !rb.content) {
+      return null;
+    }
+    var ct = rb.content["application/json"] ? "application/json" : Object.keys(rb.content).sort()[0];
+    var mt = rb.content[ct] || {};
+    if (mt.example !== undefined) {
+      return JSON.stringify(mt.example, null, 2);
+    }
+    var names = Object.keys(mt.examples || {}).sort();
+    if (names.length) {
+      var e = resolve(mt.examples[names[0]]) || {};
+      return JSON.stringify(e.value, null, 2);
+    }
+    return "";
+  }
+
+  // typedValue converts input by schema, values are serialized by the server with parameter serialization rules
+  function typedValue(raw, s) {
+    s = resolve(s) || {};
+    switch (s.type) {
+      case "integer":
+      case "number":
+        var n = Number(raw);
+        return isNaN(n) ? raw : n;
+      case "boolean":
+        return raw === "true";
+      case "array":
+        return raw.split(",").map(function (v) {
+          return typedValue(v.trim(), s.items);
+        });
+      case "object":
+        return JSON.parse(raw);
+    }
+    return raw;
+  }
+
+  function tryItSection(entry) {
+    var params = parametersOf(entry);
+    var body = exampleBody(entry.op);
+    var output = el("div", {"class": "output"});
+
+    var serverSelect = el("select", {name: "server"}, servers(entry).map(function (u) {
+      return el("option", {value: u}, u);
+    }));
+
+    var inputs = params.map(function (p) {
+      var s = resolve(parameterSchema(p)) || {};
+      var hint = s.type === "array" ? "comma separated" : s.type === "object" ? "json" : "";
+      return {
+        param: p,
+        input: el("input", {name: p.in + "." + p.name, placeholder: hint, required: p.required ? "required" : null})
+      };
+    });
+
+    var bodyInput = body === null ? null : el("textarea", {name: "body"}, body);
+
+    function send(e) {
+      e.preventDefault();
+      output.textContent = "";
+
+      var values = {};
+      try {
+        inputs.forEach(function (i) {
+          if (i.input.value !== "") {
+            values[i.param.in + "." + i.param.name] = typedValue(i.input.value, parameterSchema(i.param));
+          }
+        });
+      } catch (err) {
+        append(output, el("p", {"class": "error"}, String(err)));
+        return;
+      }
+
+      var payload = {
+        method: entry.method,
+        path: entry.path,
+        server: new URL(serverSelect.value, location.href).toString(),
+        parameters: values,
+        body: bodyInput ? bodyInput.value : ""
+      };
+
+      fetch("request", {method: "POST", headers: {"Content-Type": "application/json"}, body: JSON.stringify(payload)})
+        .then(function (resp) {
+          return resp.json().then(function (prepared) {
+            if (!resp.ok) {
+              throw new Error(prepared.error);
+            }
+            return prepared;
+          });
+        })
+        .then(function (prepared) {
+          append(output, el("pre", {}, prepared.method + " " + prepared.url));
+          var headers = Object.assign({}, prepared.headers);
+          // browsers manage cookies
+          delete headers.Cookie;
+          return fetch(prepared.url, {
+            method: prepared.method,
+            headers: headers,
+            body: payload.body && prepared.method !== "GET" && prepared.method !== "HEAD" ? payload.body : undefined
+          });
+        })
+        .then(function (resp) {
+          return resp.text().then(function (t) {
+            var lines = [resp.status + " " + resp.statusText];
+            resp.headers.forEach(function (v, k) {
+              lines.push(k + ": " + v);
+            });
+            append(output, el("pre", {}, lines.join("\n") + "\n\n" + t));
+          });
+        })
+        .catch(function (err) {
+          append(output, el("p", {"class": "error"}, String(err)));
+        });
+    }
+
+    return el("details", {"class": "try"},
+      el("summary", {}, "Try it"),
+      el("form", {onsubmit: send},
+        el("label", {}, "Server ", serverSelect),
+        inputs.map(function (i) {
+          return el("label", {}, el("code", {}, i.param.in + "." + i.param.name), i.param.required ? " *" : "", i.input);
+        }),
+        bodyInput ? el("label", {}, "Body", bodyInput) : null,
+        el("button", {type: "submit"}, "Send")),
+      output);
+  }
+
+  function operationSection(entry) {
+    var op = entry.op;
+    var params = parametersOf(entry);
+    var rb = resolve(op.requestBody);
+
+    return el("section", {"class": "operation" + (op.deprecated ? " deprecated" : ""), id: operationId(entry.method, entry.path)},
+      el("h3", {},
+        el("span", {"class": "method " + entry.method}, entry.method), " ",
+        el("span", {"class": "path"}, entry.path),
+        op.deprecated ? el("span", {"class": "badge"}, "deprecated") : null),
+      op.summary ? el("p", {}, el("strong", {}, op.summary), op.operationId ? [" ", el("code", {}, op.operationId)] : null) : null,
+      text(op.description),
+      op.externalDocs ? el("p", {}, el("a", {href: op.externalDocs.url}, op.externalDocs.description || op.externalDocs.url)) : null,
+      securitySection(op),
+      params.length ? el("section", {}, el("h4", {}, "Parameters"), parametersTable(params)) : null,
+      rb ? el("section", {},
+        el("h4", {}, "Request Body", rb.required ? el("span", {"class": "required"}, " required") : null),
+        text(rb.description),
+        contentSection(rb.content)) : null,
+      responsesSection(op.responses),
+      tryItSection(entry));
+  }
+
+  function render() {
+    var main = document.getElementById("main");
+    var nav = document.getElementById("nav");
+    main.textContent = "";
+
+    var info = spec.info || {};
+    append(main, [
+      el("h2", {}, info.title || "", info.version ? " " + info.version : ""),
+      text(info.description)
+    ]);
+
+    var tags = collectOperations();
+
+    tags.forEach(function (group) {
+      var byPath = {};
+      var paths = [];
+      group.operations.forEach(function (entry) {
+        if (!byPath[entry.path]) {
+          byPath[entry.path] = [];
+          paths.push(entry.path);
+        }
+        byPath[entry.path].push(entry);
+      });
+
+      append(nav, el("div", {"class": "tag"}, el("a", {href: "#tag-" + group.tag.name}, group.tag.name)));
+      append(nav, el("ul", {}, paths.map(function (path) {
+        return el("li", {"data-filter": path.toLowerCase()},
+          el("div", {"class": "path"}, path),
+          el("ul", {}, byPath[path].map(function (entry) {
+            return el("li", {}, el("a", {href: "#" + operationId(entry.method, entry.path)},
+              el("span", {"class": "method " + entry.method}, entry.method), " ", entry.op.summary || entry.op.operationId || ""));
+          })));
+      })));
+
+      append(main, el("section", {id: "tag-" + group.tag.name},
+        el("h2", {}, group.tag.name),
+        text(group.tag.description),
+        group.tag.externalDocs ? el("p", {}, el("a", {href: group.tag.externalDocs.url}, group.tag.externalDocs.description || group.tag.externalDocs.url)) : null,
+        group.operations.map(operationSection)));
+    });
+
+    var schemas = (spec.components || {}).schemas || {};
+    var names = Object.keys(schemas).sort();
+    if (names.length) {
+      append(nav, el("div", {"class": "tag"}, el("a", {href: "#schemas"}, "Schemas")));
+      append(main, el("section", {id: "schemas"},
+        el("h2", {}, "Schemas"),
+        names.map(function (name) {
+          return el("div", {"class": "schema", id: "schema-" + name}, schemaNode(name, {$ref: "#/components/schemas/" + name}, false));
+        })));
+    }
+
+    document.getElementById("filter").addEventListener("input", function (e) {
+      var q = e.target.value.toLowerCase();
+      Array.prototype.forEach.call(nav.querySelectorAll("[data-filter]"), function (li) {
+        li.style.display = li.getAttribute("data-filter").indexOf(q) >= 0 ? "" : "none";
+      });
+    });
+  }
+
+  fetch("openapi.json")
+    .then(function (resp) {
+      return resp.json();
+    })
+    .then(function (data) {
+      spec = data;
+      render();
+      if (location.hash) {
+        var target = document.getElementById(location.hash.slice(1));
+        if (target) {
+          target.scrollIntoView();
+        }
+      }
+    })
+    .catch(function (err) {
+      document.getElementById("main").textContent = "Failed to load openapi.json: " + err;
+    });
+})();
@@ -0,0 +1,115 @@
+package htmldoc
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+//go:embed assets
+var assets embed.FS
+
+var indexTemplate = template.Must(template.ParseFS(assets, "assets/index.html"))
+
+// Handler serves offline html documentation of the openapi, all assets embedded.
+//
+// Routes relative to where the handler mounted, like http.StripPrefix("/docs", handler):
+//
+//	/              html page
+//	/openapi.json  raw spec as json
+//	/openapi.yaml  raw spec as yaml
+//	/request       builds request of try-it form by parameter serialization rules
+//	/assets/       scripts and styles
+type Handler struct {
+	OpenAPI *oas.OpenAPI
+	// Title of the page, title of the openapi used when empty
+	Title string
+}
+
+func NewHandler(openapi *oas.OpenAPI) *Handler {
+	return &Handler{OpenAPI: openapi}
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	p := r.URL.Path
+
+	if p == "/request" {
+		if r.Method != http.MethodPost {
+			rw.Header().Set("Allow", http.MethodPost)
+			http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		h.serveRequest(rw, r)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		rw.Header().Set("Allow", "GET, HEAD")
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case p == "":
+		// mounted by StripPrefix without trailing slash, redirect to make relative urls of assets work
+		requestPath, _, _ := strings.Cut(r.RequestURI, "?")
+		http.Redirect(rw, r, path.Base(requestPath)+"/", http.StatusMovedPermanently)
+	case p == "/" || p == "/index.html":
+		h.serveIndex(rw)
+	case p == "/openapi.json":
+		h.serveSpec(rw, "application/json", func(data []byte) ([]byte, error) {
+			buf := bytes.NewBuffer(nil)
+			if err := json.Indent(buf, data, "", "  "); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		})
+	case p == "/openapi.yaml":
+		h.serveSpec(rw, "application/yaml", oas.JSONToYAML)
+	case strings.HasPrefix(p, "/assets/"):
+		sub, _ := fs.Sub(assets, "assets")
+		http.StripPrefix("/assets", http.FileServer(http.FS(sub))).ServeHTTP(rw, r)
+	default:
+		http.NotFound(rw, r)
+	}
+}
+
+func (h *Handler) title() string {
+	if h.Title != "" {
+		return h.Title
+	}
+	if h.OpenAPI.Title != "" {
+		return h.OpenAPI.Title
+	}
+	return "API Reference"
+}
+
+func (h *Handler) serveIndex(rw http.ResponseWriter) {
+	buf := bytes.NewBuffer(nil)
+	if err := indexTemplate.Execute(buf, map[string]string{"Title": h.title()}); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = rw.Write(buf.Bytes())
+}
+
+func (h *Handler) serveSpec(rw http.ResponseWriter, contentType string, convert func(data []byte) ([]byte, error)) {
+	data, err := json.Marshal(h.OpenAPI)
+	if err == nil {
+		data, err = convert(data)
+	}
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", contentType)
+	_, _ = rw.Write(data)
+}
@@ -0,0 +1,113 @@
+package htmldoc
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOpenAPI() *oas.OpenAPI {
+	openapi := oas.NewOpenAPI()
+	openapi.Title = "Pet <Store>"
+	openapi.Version = "1.0.0"
+	openapi.AddServer(oas.NewServer("https://api.example.com/v1"))
+
+	op := oas.NewOperation("listPets")
+	op.AddParameter(oas.QueryParameter("tags", oas.ItemsOf(oas.String()), false).WithStyle(oas.ParameterStylePipeDelimited, false))
+	op.AddParameter(oas.HeaderParameter("X-Trace", oas.String(), false))
+	op.AddResponse(http.StatusOK, oas.NewResponse("ok"))
+	openapi.AddOperation(oas.GET, "/users/{userId}/pets", op)
+	openapi.Paths.Paths["/users/{userId}/pets"].Parameters = []*oas.Parameter{oas.PathParameter("userId", oas.String())}
+
+	return openapi
+}
+
+func get(h http.Handler, method string, target string, body string) *http.Response {
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rw.Result()
+}
+
+func readAll(resp *http.Response) string {
+	data, _ := io.ReadAll(resp.Body)
+	return string(data)
+}
+
+func TestHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/docs/", http.StripPrefix("/docs", NewHandler(newOpenAPI())))
+	mux.Handle("/docs", http.StripPrefix("/docs", NewHandler(newOpenAPI())))
+
+	t.Run("index", func(t *testing.T) {
+		resp := get(mux, http.MethodGet, "/docs/", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+
+		html := readAll(resp)
+		assert.Contains(t, html, "<title>Pet &lt;Store&gt;</title>")
+		assert.Contains(t, html, `<script src="assets/app.js"></script>`)
+		assert.NotContains(t, html, "http://")
+		assert.NotContains(t, html, "https://")
+	})
+
+	t.Run("redirect to trailing slash", func(t *testing.T) {
+		resp := get(mux, http.MethodGet, "/docs?x=1", "")
+		assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+		assert.Equal(t, "/docs/", resp.Header.Get("Location"))
+	})
+
+	t.Run("assets", func(t *testing.T) {
+		for _, name := range []string{"app.js", "style.css"} {
+			resp := get(mux, http.MethodGet, "/docs/assets/"+name, "")
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.NotEmpty(t, readAll(resp))
+		}
+		assert.Equal(t, http.StatusNotFound, get(mux, http.MethodGet, "/docs/assets/unknown.js", "").StatusCode)
+		assert.Equal(t, http.StatusNotFound, get(mux, http.MethodGet, "/docs/unknown", "").StatusCode)
+	})
+
+	t.Run("spec", func(t *testing.T) {
+		resp := get(mux, http.MethodGet, "/docs/openapi.json", "")
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		openapi := &oas.OpenAPI{}
+		assert.NoError(t, json.Unmarshal([]byte(readAll(resp)), openapi))
+		assert.Equal(t, "Pet <Store>", openapi.Title)
+
+		resp = get(mux, http.MethodGet, "/docs/openapi.yaml", "")
+		assert.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+		openapi = &oas.OpenAPI{}
+		assert.NoError(t, oas.UnmarshalYAML([]byte(readAll(resp)), openapi))
+		assert.Equal(t, "Pet <Store>", openapi.Title)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, get(mux, http.MethodPost, "/docs/openapi.json", "").StatusCode)
+	})
+
+	t.Run("prepare request", func(t *testing.T) {
+		resp := get(mux, http.MethodPost, "/docs/request", `{
+	"method": "get",
+	"path": "/users/{userId}/pets",
+	"parameters": {"path.userId": "u 1", "query.tags": ["a", "b"], "header.X-Trace": "t1"}
+}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{
+	"method": "GET",
+	"url": "https://api.example.com/v1/users/u%201/pets?tags=a|b",
+	"headers": {"X-Trace": "t1"}
+}`, readAll(resp))
+
+		resp = get(mux, http.MethodPost, "/docs/request", `{"method":"get","path":"/users/{userId}/pets","server":"http://localhost:8080"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"error":"listPets: missing required parameter path.userId"}`, readAll(resp))
+
+		resp = get(mux, http.MethodPost, "/docs/request", `{"method":"post","path":"/users/{userId}/pets"}`)
+		assert.JSONEq(t, `{"error":"operation post /users/{userId}/pets not found"}`, readAll(resp))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, get(mux, http.MethodGet, "/docs/request", "").StatusCode)
+	})
+}
@@ -0,0 +1,94 @@
+package htmldoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"github.com/go-courier/oas/workflow"
+)
+
+// TryRequest is posted by the try-it form
+type TryRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Server is the base url selected, the first effective server used when empty
+	Server     string                 `json:"server,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	// Body is the raw request body, only used to decide content type, the browser sends it
+	Body string `json:"body,omitempty"`
+}
+
+// PreparedRequest is the request built for the browser to send
+type PreparedRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Prepare builds request by parameter serialization rules of the operation,
+// the request will not be sent by the server.
+func Prepare(openapi *oas.OpenAPI, tr *TryRequest) (*PreparedRequest, error) {
+	method := oas.HttpMethod(strings.ToLower(tr.Method))
+
+	pathItem := openapi.Paths.Paths[tr.Path]
+	if pathItem == nil || pathItem.Operations.Operations[method] == nil {
+		return nil, fmt.Errorf("operation %s %s not found", tr.Method, tr.Path)
+	}
+
+	t := &workflow.Target{Method: method, Path: tr.Path, PathItem: pathItem, Operation: pathItem.Operations.Operations[method]}
+
+	baseURL := tr.Server
+	if baseURL == "" {
+		u, err := workflow.BaseURL(openapi, t)
+		if err != nil {
+			return nil, err
+		}
+		baseURL = u
+	}
+
+	var body interface{}
+	if tr.Body != "" {
+		body = tr.Body
+	}
+
+	req, err := workflow.NewRequest(openapi, baseURL, t, tr.Parameters, body)
+	if err != nil {
+		return nil, err
+	}
+
+	prepared := &PreparedRequest{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: map[string]string{},
+	}
+	for k := range req.Header {
+		prepared.Headers[k] = req.Header.Get(k)
+	}
+
+	return prepared, nil
+}
+
+func (h *Handler) serveRequest(rw http.ResponseWriter, r *http.Request) {
+	tr := &TryRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, 1<<20)).Decode(tr); err != nil {
+		writeJSON(rw, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	prepared, err := Prepare(h.OpenAPI, tr)
+	if err != nil {
+		writeJSON(rw, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, prepared)
+}
+
+func writeJSON(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(v)
+}
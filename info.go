@@ -1,10 +1,41 @@
 package oas
 
+func NewInfo(title string, version string) *Info {
+	i := &Info{}
+	i.Title = title
+	i.Version = version
+	return i
+}
+
 type Info struct {
 	InfoObject
 	SpecExtensions
 }
 
+func (i Info) WithDesc(desc string) *Info {
+	c := i.Clone()
+	c.Description = desc
+	return c
+}
+
+func (i Info) WithTermsOfService(url string) *Info {
+	c := i.Clone()
+	c.TermsOfService = url
+	return c
+}
+
+func (i Info) WithContact(contact *Contact) *Info {
+	c := i.Clone()
+	c.Contact = contact.Clone()
+	return c
+}
+
+func (i Info) WithLicense(license *License) *Info {
+	c := i.Clone()
+	c.License = license.Clone()
+	return c
+}
+
 func (i Info) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.InfoObject, i.SpecExtensions)
 }
@@ -22,11 +53,29 @@ type InfoObject struct {
 	Version        string `json:"version"`
 }
 
+func NewContact(name string) *Contact {
+	c := &Contact{}
+	c.Name = name
+	return c
+}
+
 type Contact struct {
 	ContactObject
 	SpecExtensions
 }
 
+func (i Contact) WithURL(url string) *Contact {
+	c := i.Clone()
+	c.URL = url
+	return c
+}
+
+func (i Contact) WithEmail(email string) *Contact {
+	c := i.Clone()
+	c.Email = email
+	return c
+}
+
 func (i Contact) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.ContactObject, i.SpecExtensions)
 }
@@ -41,11 +90,23 @@ type ContactObject struct {
 	Email string `json:"email,omitempty"`
 }
 
+func NewLicense(name string) *License {
+	l := &License{}
+	l.Name = name
+	return l
+}
+
 type License struct {
 	LicenseObject
 	SpecExtensions
 }
 
+func (i License) WithURL(url string) *License {
+	c := i.Clone()
+	c.URL = url
+	return c
+}
+
 func (i License) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.LicenseObject, i.SpecExtensions)
 }
@@ -0,0 +1,171 @@
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type jsonKind int
+
+const (
+	jsonNull jsonKind = iota
+	jsonBool
+	jsonNumber
+	jsonString
+	jsonArray
+	jsonObject
+)
+
+func (k jsonKind) String() string {
+	switch k {
+	case jsonBool:
+		return "bool"
+	case jsonNumber:
+		return "number"
+	case jsonString:
+		return "string"
+	case jsonArray:
+		return "array"
+	case jsonObject:
+		return "object"
+	}
+	return "null"
+}
+
+// jsonNode is a parsed json value which keeps the offsets of values and member keys,
+// and the order of object members.
+type jsonNode struct {
+	kind    jsonKind
+	offset  int
+	raw     string
+	members []*jsonMember
+	items   []*jsonNode
+}
+
+type jsonMember struct {
+	key    string
+	offset int
+	value  *jsonNode
+}
+
+func (n *jsonNode) member(key string) *jsonMember {
+	for _, m := range n.members {
+		if m.key == key {
+			return m
+		}
+	}
+	return nil
+}
+
+func parseJSONNode(data []byte) (*jsonNode, error) {
+	if !json.Valid(data) {
+		// let encoding/json describe the syntax error
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("invalid json")
+	}
+	p := &jsonNodeParser{data: data}
+	return p.parseValue(), nil
+}
+
+// jsonNodeParser only runs on input already checked by json.Valid
+type jsonNodeParser struct {
+	data []byte
+	pos  int
+}
+
+func (p *jsonNodeParser) skipSpaces() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsonNodeParser) parseValue() *jsonNode {
+	p.skipSpaces()
+
+	n := &jsonNode{offset: p.pos}
+
+	switch c := p.data[p.pos]; {
+	case c == '{':
+		n.kind = jsonObject
+		p.pos++
+		p.skipSpaces()
+		for p.data[p.pos] != '}' {
+			p.skipSpaces()
+			m := &jsonMember{offset: p.pos}
+			m.key = p.parseString()
+			p.skipSpaces()
+			p.pos++ // :
+			m.value = p.parseValue()
+			n.members = append(n.members, m)
+			p.skipSpaces()
+			if p.data[p.pos] == ',' {
+				p.pos++
+			}
+		}
+		p.pos++
+	case c == '[':
+		n.kind = jsonArray
+		p.pos++
+		p.skipSpaces()
+		for p.data[p.pos] != ']' {
+			n.items = append(n.items, p.parseValue())
+			p.skipSpaces()
+			if p.data[p.pos] == ',' {
+				p.pos++
+			}
+		}
+		p.pos++
+	case c == '"':
+		n.kind = jsonString
+		n.raw = p.parseString()
+	case c == 't':
+		n.kind = jsonBool
+		n.raw = "true"
+		p.pos += 4
+	case c == 'f':
+		n.kind = jsonBool
+		n.raw = "false"
+		p.pos += 5
+	case c == 'n':
+		n.kind = jsonNull
+		n.raw = "null"
+		p.pos += 4
+	default:
+		n.kind = jsonNumber
+		start := p.pos
+		for p.pos < len(p.data) && isJSONNumberByte(p.data[p.pos]) {
+			p.pos++
+		}
+		n.raw = string(p.data[start:p.pos])
+	}
+
+	return n
+}
+
+func (p *jsonNodeParser) parseString() string {
+	start := p.pos
+	p.pos++
+	for p.data[p.pos] != '"' {
+		if p.data[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	p.pos++
+
+	s := ""
+	_ = json.Unmarshal(p.data[start:p.pos], &s)
+	return s
+}
+
+func isJSONNumberByte(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
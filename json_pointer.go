@@ -0,0 +1,51 @@
+package oas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseJSONPointer parses json pointer like `/paths/~1pets/get` into unescaped tokens
+func ParseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid json pointer %q, should start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(tokens[i], "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// ResolveJSONPointer resolves value of the pointer from the decoded json value
+func ResolveJSONPointer(v interface{}, pointer string) (interface{}, error) {
+	tokens, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, token := range tokens {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("json pointer %q: %q not found", pointer, "/"+strings.Join(tokens[:i+1], "/"))
+			}
+			v = value
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, fmt.Errorf("json pointer %q: invalid index %q", pointer, token)
+			}
+			v = node[idx]
+		default:
+			return nil, fmt.Errorf("json pointer %q: %q is not an object or array", pointer, "/"+strings.Join(tokens[:i], "/"))
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,39 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveJSONPointer(t *testing.T) {
+	v := map[string]interface{}{
+		"paths": map[string]interface{}{
+			"/pets": map[string]interface{}{
+				"tags": []interface{}{"a~b", "pets"},
+			},
+		},
+		"a~b": 1,
+	}
+
+	resolved, err := ResolveJSONPointer(v, "/paths/~1pets/tags/1")
+	assert.NoError(t, err)
+	assert.Equal(t, "pets", resolved)
+
+	resolved, err = ResolveJSONPointer(v, "/a~0b")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resolved)
+
+	resolved, err = ResolveJSONPointer(v, "")
+	assert.NoError(t, err)
+	assert.Equal(t, v, resolved)
+
+	_, err = ResolveJSONPointer(v, "/paths/~1pets/tags/2")
+	assert.Error(t, err)
+
+	_, err = ResolveJSONPointer(v, "/a~0b/c")
+	assert.Error(t, err)
+
+	_, err = ResolveJSONPointer(v, "paths")
+	assert.Error(t, err)
+}
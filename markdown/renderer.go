@@ -0,0 +1,83 @@
+package markdown
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/go-courier/oas"
+)
+
+var funcs = template.FuncMap{
+	"code":   code,
+	"cell":   cell,
+	"anchor": Anchor,
+}
+
+var defaultTemplate = template.Must(
+	template.Must(template.New("markdown").Funcs(funcs).Parse(`{{ template "document" . }}`)).Parse(defaultTemplates),
+)
+
+// Renderer renders *oas.OpenAPI as markdown by text/template
+type Renderer struct {
+	tpl *template.Template
+}
+
+// NewRenderer creates renderer with default templates.
+// Templates are executed with *Document, see defaultTemplates for names of templates.
+func NewRenderer() *Renderer {
+	return &Renderer{tpl: template.Must(defaultTemplate.Clone())}
+}
+
+// Funcs adds functions could be used in overrides, should be called before Override.
+func (r *Renderer) Funcs(funcMap template.FuncMap) *Renderer {
+	r.tpl.Funcs(funcMap)
+	return r
+}
+
+// Override replaces default templates by the text, like `{{ define "operation" }}...{{ end }}`
+func (r *Renderer) Override(text string) error {
+	_, err := r.tpl.Parse(text)
+	return err
+}
+
+func (r *Renderer) Render(w io.Writer, openapi *oas.OpenAPI) error {
+	buf := bytes.NewBuffer(nil)
+	if err := r.tpl.Execute(buf, NewDocument(openapi)); err != nil {
+		return err
+	}
+	_, err := w.Write(collapseBlankLines(buf.Bytes()))
+	return err
+}
+
+// Render renders openapi as markdown with default templates
+func Render(w io.Writer, openapi *oas.OpenAPI) error {
+	return NewRenderer().Render(w, openapi)
+}
+
+var reBlankLines = regexp.MustCompile(`\n{3,}`)
+
+func collapseBlankLines(data []byte) []byte {
+	return append(bytes.TrimSpace(reBlankLines.ReplaceAll(data, []byte("\n\n"))), '\n')
+}
+
+func code(s string) string {
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// cell escapes text for table cells, which should be in one line
+func cell(s string) string {
+	return strings.ReplaceAll(oneLine(s), "|", `\|`)
+}
+
+var reNonAnchor = regexp.MustCompile(`[^\p{L}\p{N}\- _]`)
+
+// Anchor returns anchor of the heading, as same as generated by GitHub and GitLab
+func Anchor(heading string) string {
+	return strings.ReplaceAll(reNonAnchor.ReplaceAllString(strings.ToLower(heading), ""), " ", "-")
+}
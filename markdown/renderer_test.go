@@ -0,0 +1,122 @@
+package markdown
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/go-courier/oas"
+	"github.com/go-courier/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOpenAPI() *oas.OpenAPI {
+	openapi := oas.NewOpenAPI()
+	openapi.Title = "Pet Store"
+	openapi.Version = "1.0.0"
+	openapi.AddServer(oas.NewServer("https://api.example.com"))
+
+	tag := oas.NewTag("pets")
+	tag.Description = "Everything about pets"
+	tag.ExternalDocs = oas.NewExternalDoc("https://example.com/pets", "Pet guide")
+	openapi.AddTag(tag)
+
+	openapi.AddSecurityScheme("apiKey", oas.NewAPIKeySecurityScheme("X-API-Key", oas.PositionHeader))
+	openapi.AddSecurityRequirement(&oas.SecurityRequirement{"apiKey": {}})
+
+	openapi.AddSchema("Pet", oas.ObjectOf(oas.Props{
+		"id":   oas.Long(),
+		"name": oas.String().WithDesc("name of | the pet"),
+		"kind": oas.String().WithValidation(&oas.SchemaValidation{Enum: []interface{}{"cat", "dog"}}),
+	}, "id", "name"))
+	openapi.Schemas["Pet"].Properties["parent"] = openapi.RefSchema("Pet")
+
+	{
+		op := oas.NewOperation("listPets")
+		op.Summary = "List pets"
+		op.Tags = []string{"pets"}
+		op.AddParameter(oas.QueryParameter("limit", oas.Integer().WithValidation(&oas.SchemaValidation{Maximum: ptr.Float64(100)}), false).WithDesc("max items"))
+
+		mt := oas.NewMediaTypeWithSchema(oas.ItemsOf(openapi.RefSchema("Pet")))
+		example := oas.NewExample()
+		example.Summary = "cats"
+		example.Value = oas.AnyValue([]interface{}{map[string]interface{}{"id": 1, "name": "kitty"}})
+		mt.AddExample("cats", example)
+
+		resp := oas.NewResponse("pets")
+		resp.AddContent("application/json", mt)
+		op.AddResponse(http.StatusOK, resp)
+		op.SetDefaultResponse(oas.NewResponse("error"))
+
+		openapi.AddOperation(oas.GET, "/pets", op)
+	}
+
+	{
+		op := oas.NewOperation("createPet")
+		op.Tags = []string{"pets"}
+		op.Deprecated = true
+		op.AddSecurityRequirement(&oas.SecurityRequirement{"oauth": {"write"}})
+		op.AddOptionalSecurity()
+
+		rb := oas.NewRequestBody("pet to create", true)
+		rb.AddContent("application/json", oas.NewMediaTypeWithSchema(openapi.RefSchema("Pet")))
+		op.SetRequestBody(rb)
+		op.AddResponse(http.StatusCreated, oas.NewResponse("created"))
+
+		openapi.AddOperation(oas.POST, "/pets", op)
+	}
+
+	{
+		op := oas.NewOperation("health")
+		op.DisableSecurity()
+		op.AddResponse(http.StatusNoContent, oas.NewResponse("ok"))
+		openapi.AddOperation(oas.GET, "/health", op)
+	}
+
+	return openapi
+}
+
+func TestRender(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, Render(buf, newOpenAPI()))
+
+	output := buf.String()
+
+	for _, fragment := range []string{
+		"# Pet Store (1.0.0)\n",
+		"## Operations\n\n- pets\n  - [GET /pets](#get-pets)\n  - [POST /pets](#post-pets) *(deprecated)*\n- default\n  - [GET /health](#get-health)\n",
+		"## pets\n\nEverything about pets\n\nSee [Pet guide](https://example.com/pets)\n",
+		"| `limit` | query | no | `integer(int32)` | max items (maximum: 100) |\n",
+		"`application/json` `[]Pet`\n\n- items `Pet`\n  - `id` `integer(int64)` **required**\n  - `kind` `string` (enum: `cat`, `dog`)\n  - `name` `string` **required** name of | the pet\n  - `parent` `Pet`\n",
+		"Example `cats`: cats\n\n```json\n[\n  {\n    \"id\": 1,\n    \"name\": \"kitty\"\n  }\n]\n```\n",
+		"> **Deprecated**: this operation is deprecated",
+		"#### Security\n\nOptional, any of:\n\n- `oauth` (write)\n",
+		"### GET /health\n\n**health**\n\n#### Security\n\nNo authentication required.\n",
+		"##### default\n\nerror\n",
+		"### Pet\n\n`object`\n\n- `id` `integer(int64)` **required**\n- `kind` `string` (enum: `cat`, `dog`)\n- `name` `string` **required** name of | the pet\n- `parent` `Pet`\n",
+	} {
+		assert.Contains(t, output, fragment)
+	}
+}
+
+func TestRendererOverride(t *testing.T) {
+	r := NewRenderer().Funcs(template.FuncMap{"lower": strings.ToLower})
+
+	assert.NoError(t, r.Override(`{{ define "operation" }}
+* {{ lower .Method }} {{ .Path }}{{ end }}`))
+
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, r.Render(buf, newOpenAPI()))
+
+	assert.Contains(t, buf.String(), "* get /pets\n\n* post /pets\n")
+	assert.NotContains(t, buf.String(), "#### Responses")
+
+	assert.Error(t, r.Override(`{{ define "operation" }}{{ end `))
+}
+
+func TestAnchor(t *testing.T) {
+	assert.Equal(t, "get-usersid", Anchor("GET /users/{id}"))
+	assert.Equal(t, "post-pet_store-v1", Anchor("POST /pet_store-v1"))
+}
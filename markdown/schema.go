@@ -0,0 +1,281 @@
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+// maxTreeDepth limits expanding of nested schemas
+const maxTreeDepth = 8
+
+func refName(s *oas.Schema) string {
+	if s == nil || s.Refer == nil {
+		return ""
+	}
+	if ref := oas.ParseComponentRefer(s.Refer.RefString()); ref != nil {
+		return ref.ID
+	}
+	return s.Refer.RefString()
+}
+
+// TypeSummary returns short type of the schema, like `[]Pet`, `integer(int64)`, `map[string]string`
+func TypeSummary(s *oas.Schema) string {
+	if s == nil {
+		return ""
+	}
+
+	if name := refName(s); name != "" {
+		return name
+	}
+
+	summary := ""
+
+	switch {
+	case len(s.AllOf) > 0:
+		summary = "allOf(" + typeSummaries(s.AllOf) + ")"
+	case len(s.OneOf) > 0:
+		summary = "oneOf(" + typeSummaries(s.OneOf) + ")"
+	case len(s.AnyOf) > 0:
+		summary = "anyOf(" + typeSummaries(s.AnyOf) + ")"
+	case s.Type == oas.TypeArray:
+		summary = "[]" + orAny(TypeSummary(s.Items))
+	case s.Type == oas.TypeObject && len(s.Properties) == 0 && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+		summary = "map[string]" + orAny(TypeSummary(s.AdditionalProperties.Schema))
+	case s.Type == "":
+		summary = "any"
+	case s.Format != "":
+		summary = string(s.Type) + "(" + s.Format + ")"
+	default:
+		summary = string(s.Type)
+	}
+
+	if s.Nullable {
+		summary += " (nullable)"
+	}
+
+	return summary
+}
+
+func typeSummaries(list []*oas.Schema) string {
+	summaries := make([]string, len(list))
+	for i := range list {
+		summaries[i] = orAny(TypeSummary(list[i]))
+	}
+	return strings.Join(summaries, ", ")
+}
+
+func orAny(s string) string {
+	if s == "" {
+		return "any"
+	}
+	return s
+}
+
+// Constraints returns readable validations, default value and flags of the schema
+func Constraints(s *oas.Schema) []string {
+	if s == nil {
+		return nil
+	}
+
+	list := make([]string, 0)
+
+	add := func(format string, args ...interface{}) {
+		list = append(list, fmt.Sprintf(format, args...))
+	}
+
+	if len(s.Enum) > 0 {
+		values := make([]string, len(s.Enum))
+		for i := range s.Enum {
+			values[i] = "`" + jsonString(s.Enum[i]) + "`"
+		}
+		add("enum: %s", strings.Join(values, ", "))
+	}
+	if s.Default.Present {
+		add("default: `%s`", jsonString(s.Default.Value))
+	}
+	if s.Minimum != nil {
+		if s.ExclusiveMinimum {
+			add("minimum: >%s", formatFloat(*s.Minimum))
+		} else {
+			add("minimum: %s", formatFloat(*s.Minimum))
+		}
+	}
+	if s.Maximum != nil {
+		if s.ExclusiveMaximum {
+			add("maximum: <%s", formatFloat(*s.Maximum))
+		} else {
+			add("maximum: %s", formatFloat(*s.Maximum))
+		}
+	}
+	if s.MultipleOf != nil {
+		add("multipleOf: %s", formatFloat(*s.MultipleOf))
+	}
+	if s.MinLength != nil {
+		add("minLength: %d", *s.MinLength)
+	}
+	if s.MaxLength != nil {
+		add("maxLength: %d", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		add("pattern: `%s`", s.Pattern)
+	}
+	if s.MinItems != nil {
+		add("minItems: %d", *s.MinItems)
+	}
+	if s.MaxItems != nil {
+		add("maxItems: %d", *s.MaxItems)
+	}
+	if s.UniqueItems {
+		add("uniqueItems")
+	}
+	if s.MinProperties != nil {
+		add("minProperties: %d", *s.MinProperties)
+	}
+	if s.MaxProperties != nil {
+		add("maxProperties: %d", *s.MaxProperties)
+	}
+	if s.ReadOnly {
+		add("readOnly")
+	}
+	if s.WriteOnly {
+		add("writeOnly")
+	}
+	if s.Deprecated {
+		add("deprecated")
+	}
+
+	return list
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func jsonString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// describe joins description and constraints of the schema into one line
+func describe(description string, s *oas.Schema) string {
+	parts := make([]string, 0, 2)
+	if description != "" {
+		parts = append(parts, description)
+	}
+	if constraints := Constraints(s); len(constraints) > 0 {
+		parts = append(parts, "("+strings.Join(constraints, ", ")+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// SchemaTree renders fields of the schema as nested markdown list,
+// refs are expanded unless already expanded by the ancestors.
+func SchemaTree(components *oas.ComponentsObject, s *oas.Schema) string {
+	b := &treeBuilder{components: components}
+	b.children(s, 0, map[string]bool{})
+	return b.String()
+}
+
+type treeBuilder struct {
+	components *oas.ComponentsObject
+	strings.Builder
+}
+
+func (b *treeBuilder) line(depth int, name string, s *oas.Schema, required bool) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString("- ")
+	b.WriteString(name)
+	b.WriteString(" `" + orAny(TypeSummary(s)) + "`")
+	if required {
+		b.WriteString(" **required**")
+	}
+
+	description := ""
+	if resolved := b.components.ResolveSchema(s); resolved != nil {
+		description = describe(oneLine(resolved.Description), resolved)
+	}
+	if description != "" {
+		b.WriteString(" ")
+		b.WriteString(description)
+	}
+	b.WriteString("\n")
+}
+
+func (b *treeBuilder) children(s *oas.Schema, depth int, expanded map[string]bool) {
+	if s == nil || depth > maxTreeDepth {
+		return
+	}
+
+	if name := refName(s); name != "" {
+		if expanded[name] {
+			return
+		}
+		s = b.components.ResolveSchema(s)
+		if s == nil {
+			return
+		}
+		expanded = with(expanded, name)
+	}
+
+	for _, sub := range s.AllOf {
+		b.children(sub, depth, expanded)
+	}
+
+	for _, group := range []struct {
+		name string
+		list []*oas.Schema
+	}{
+		{"oneOf", s.OneOf},
+		{"anyOf", s.AnyOf},
+	} {
+		for i, sub := range group.list {
+			b.line(depth, fmt.Sprintf("%s[%d]", group.name, i), sub, false)
+			b.children(sub, depth+1, expanded)
+		}
+	}
+
+	if s.Items != nil {
+		b.line(depth, "items", s.Items, false)
+		b.children(s.Items, depth+1, expanded)
+	}
+
+	required := map[string]bool{}
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		b.line(depth, "`"+name+"`", s.Properties[name], required[name])
+		b.children(s.Properties[name], depth+1, expanded)
+	}
+
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		b.line(depth, "`*`", s.AdditionalProperties.Schema, false)
+		b.children(s.AdditionalProperties.Schema, depth+1, expanded)
+	}
+}
+
+func with(set map[string]bool, key string) map[string]bool {
+	next := make(map[string]bool, len(set)+1)
+	for k := range set {
+		next[k] = true
+	}
+	next[key] = true
+	return next
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
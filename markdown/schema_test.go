@@ -0,0 +1,41 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/go-courier/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeSummary(t *testing.T) {
+	nullable := oas.String()
+	nullable.Nullable = true
+
+	cases := []struct {
+		schema  *oas.Schema
+		summary string
+	}{
+		{oas.RefSchema("#/components/schemas/Pet"), "Pet"},
+		{oas.ItemsOf(oas.Long()), "[]integer(int64)"},
+		{oas.MapOf(oas.String()), "map[string]string"},
+		{oas.OneOf(oas.String(), oas.Integer()), "oneOf(string, integer(int32))"},
+		{nullable, "string (nullable)"},
+		{&oas.Schema{}, "any"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.summary, TypeSummary(c.schema))
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	s := oas.Integer().WithValidation(&oas.SchemaValidation{
+		Minimum:          ptr.Float64(0),
+		ExclusiveMinimum: true,
+		MultipleOf:       ptr.Float64(2),
+	})
+	s.Default = oas.AnyValue(2)
+
+	assert.Equal(t, []string{"default: `2`", "minimum: >0", "multipleOf: 2"}, Constraints(s))
+}
@@ -0,0 +1,162 @@
+package markdown
+
+// defaultTemplates defines templates rendered from the Document,
+// each of them could be overridden by defining the template with the same name.
+const defaultTemplates = `
+{{- define "document" -}}
+# {{ .Title }}{{ if .Version }} ({{ .Version }}){{ end }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{- if .Servers }}
+## Servers
+
+{{ range .Servers }}- {{ code .URL }}{{ if .Description }} {{ .Description }}{{ end }}
+{{ end }}{{ end }}
+{{- if .Security }}
+## Security
+
+{{ template "security" .Security }}
+{{ end }}
+{{- template "toc" . }}
+{{- range .Tags }}
+
+{{ template "tag" . }}
+{{- end }}
+{{- if .Schemas }}
+
+{{ template "schemas" .Schemas }}
+{{- end }}
+{{- end }}
+
+{{- define "toc" }}
+## Operations
+
+{{ range .Tags }}- {{ .Name }}
+{{ range .Operations }}  - [{{ .Method }} {{ .Path }}](#{{ anchor (print .Method " " .Path) }}){{ if .Deprecated }} *(deprecated)*{{ end }}
+{{ end }}{{ end }}
+{{- end }}
+
+{{- define "tag" }}
+## {{ .Name }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{- with .ExternalDocs }}
+{{ template "externalDocs" . }}
+{{ end }}
+{{- range .Operations }}
+{{ template "operation" . }}
+{{- end }}
+{{- end }}
+
+{{- define "externalDocs" -}}
+See [{{ if .Description }}{{ .Description }}{{ else }}{{ .URL }}{{ end }}]({{ .URL }})
+{{- end }}
+
+{{- define "operation" }}
+### {{ .Method }} {{ .Path }}
+
+{{ if .Deprecated }}> **Deprecated**: this operation is deprecated and may be removed in the future.
+
+{{ end -}}
+**{{ .Title }}**{{ if and .Summary .OperationId }} ({{ code .OperationId }}){{ end }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{- with .ExternalDocs }}
+{{ template "externalDocs" . }}
+{{ end }}
+{{- with .Security }}
+#### Security
+
+{{ template "security" . }}
+{{ end }}
+{{- with .Parameters }}
+#### Parameters
+
+{{ template "parameters" . }}
+{{- end }}
+{{- with .RequestBody }}
+#### Request Body
+{{ template "requestBody" . }}
+{{- end }}
+{{- with .Responses }}
+#### Responses
+{{ range . }}
+{{ template "response" . }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{- define "security" -}}
+{{ if not .Requirements }}No authentication required.
+{{ else }}{{ if .Optional }}Optional, any of:{{ else }}Any of:{{ end }}
+
+{{ range .Requirements }}- {{ . }}
+{{ end }}{{ end }}
+{{- end }}
+
+{{- define "parameters" -}}
+| Name | In | Required | Type | Description |
+| --- | --- | --- | --- | --- |
+{{ range . }}| {{ code .Name }}{{ if .Deprecated }} *(deprecated)*{{ end }} | {{ .In }} | {{ if .Required }}yes{{ else }}no{{ end }} | {{ code .Type }} | {{ cell .Description }} |
+{{ end }}
+{{- end }}
+
+{{- define "requestBody" }}
+{{ if .Required }}**Required**{{ else }}Optional{{ end }}{{ if .Description }}. {{ .Description }}{{ end }}
+{{ range .Contents }}
+{{ template "content" . }}
+{{- end }}
+{{- end }}
+
+{{- define "response" -}}
+##### {{ .Status }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{- with .Headers }}
+Headers:
+
+{{ template "parameters" . }}
+{{- end }}
+{{- range .Contents }}
+{{ template "content" . }}
+{{- end }}
+{{- end }}
+
+{{- define "content" -}}
+{{ code .ContentType }}{{ if .Type }} {{ code .Type }}{{ end }}
+{{ if .Tree }}
+{{ .Tree }}{{ end }}
+{{- range .Examples }}
+{{ template "example" . }}
+{{- end }}
+{{- end }}
+
+{{- define "example" -}}
+Example {{ code .Name }}{{ if .Summary }}: {{ .Summary }}{{ end }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{- if .ExternalValue }}
+See [{{ .ExternalValue }}]({{ .ExternalValue }})
+{{ else }}
+` + "```json" + `
+{{ .Value }}
+` + "```" + `
+{{ end }}
+{{- end }}
+
+{{- define "schemas" -}}
+## Schemas
+{{ range . }}
+### {{ .Name }}
+
+{{ code .Type }}{{ if .Description }} {{ .Description }}{{ end }}
+{{ if .Tree }}
+{{ .Tree }}{{ end }}
+{{- end }}
+{{- end }}
+`
@@ -0,0 +1,373 @@
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+// Document is the data of templates, built from *oas.OpenAPI
+type Document struct {
+	OpenAPI     *oas.OpenAPI
+	Title       string
+	Version     string
+	Description string
+	Servers     []*oas.Server
+	Security    *SecurityView
+	Tags        []*TagView
+	Schemas     []*SchemaView
+}
+
+type TagView struct {
+	Name         string
+	Description  string
+	ExternalDocs *oas.ExternalDoc
+	Operations   []*OperationView
+}
+
+type OperationView struct {
+	Method       string
+	Path         string
+	OperationId  string
+	Summary      string
+	Description  string
+	Deprecated   bool
+	ExternalDocs *oas.ExternalDoc
+	Parameters   []*ParameterView
+	RequestBody  *BodyView
+	Responses    []*ResponseView
+	// Security is nil when no security declared
+	Security *SecurityView
+}
+
+// Title returns summary, or operationId when summary empty
+func (v *OperationView) Title() string {
+	if v.Summary != "" {
+		return v.Summary
+	}
+	return v.OperationId
+}
+
+type ParameterView struct {
+	Name        string
+	In          string
+	Required    bool
+	Deprecated  bool
+	Type        string
+	Description string
+}
+
+type BodyView struct {
+	Description string
+	Required    bool
+	Contents    []*ContentView
+}
+
+type ResponseView struct {
+	Status      string
+	Description string
+	Headers     []*ParameterView
+	Contents    []*ContentView
+}
+
+type ContentView struct {
+	ContentType string
+	Type        string
+	// Tree is the markdown list of fields of the schema
+	Tree     string
+	Examples []*ExampleView
+}
+
+type ExampleView struct {
+	Name        string
+	Summary     string
+	Description string
+	// Value is indented json
+	Value         string
+	ExternalValue string
+}
+
+// SecurityView lists requirements, any of them could be satisfied
+type SecurityView struct {
+	// Requirements like "`oauth` (read, write) and `apiKey`"
+	Requirements []string
+	// Optional when anonymous access allowed too
+	Optional bool
+}
+
+type SchemaView struct {
+	Name        string
+	Type        string
+	Description string
+	Tree        string
+}
+
+// DefaultTag groups operations without tags
+var DefaultTag = "default"
+
+var methodOrder = []oas.HttpMethod{oas.GET, oas.PUT, oas.POST, oas.DELETE, oas.OPTIONS, oas.HEAD, oas.PATCH, oas.TRACE}
+
+// NewDocument groups operations by tags, tags ordered by declaration of the document, then undeclared ones by name.
+func NewDocument(openapi *oas.OpenAPI) *Document {
+	doc := &Document{
+		OpenAPI:     openapi,
+		Title:       openapi.Title,
+		Version:     openapi.Version,
+		Description: openapi.Description,
+		Servers:     openapi.Servers,
+		Security:    newSecurityView(openapi.Security),
+	}
+
+	components := &openapi.ComponentsObject
+
+	tags := map[string]*TagView{}
+	declared := make([]*TagView, 0)
+	undeclared := make([]*TagView, 0)
+
+	for _, t := range openapi.Tags {
+		if t == nil || tags[t.Name] != nil {
+			continue
+		}
+		v := &TagView{Name: t.Name, Description: t.Description, ExternalDocs: t.ExternalDocs}
+		tags[t.Name] = v
+		declared = append(declared, v)
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(openapi.Paths.Paths)) {
+		pathItem := openapi.Paths.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+
+		for _, method := range methodOrder {
+			op := pathItem.Operations.Operations[method]
+			if op == nil {
+				continue
+			}
+
+			v := newOperationView(openapi, components, method, path, pathItem, op)
+
+			names := op.Tags
+			if len(names) == 0 {
+				names = []string{DefaultTag}
+			}
+
+			for _, name := range names {
+				t, ok := tags[name]
+				if !ok {
+					t = &TagView{Name: name}
+					tags[name] = t
+					undeclared = append(undeclared, t)
+				}
+				t.Operations = append(t.Operations, v)
+			}
+		}
+	}
+
+	sort.SliceStable(undeclared, func(i, j int) bool {
+		return undeclared[i].Name < undeclared[j].Name
+	})
+
+	doc.Tags = append(declared, undeclared...)
+
+	for _, name := range slices.Sorted(maps.Keys(openapi.Schemas)) {
+		s := openapi.Schemas[name]
+		if s == nil {
+			continue
+		}
+		doc.Schemas = append(doc.Schemas, &SchemaView{
+			Name:        name,
+			Type:        orAny(TypeSummary(s)),
+			Description: describe(s.Description, s),
+			Tree:        SchemaTree(components, openapi.RefSchema(name)),
+		})
+	}
+
+	return doc
+}
+
+func newOperationView(openapi *oas.OpenAPI, components *oas.ComponentsObject, method oas.HttpMethod, path string, pathItem *oas.PathItem, op *oas.Operation) *OperationView {
+	v := &OperationView{
+		Method:       strings.ToUpper(string(method)),
+		Path:         path,
+		OperationId:  op.OperationId,
+		Summary:      op.Summary,
+		Description:  op.Description,
+		Deprecated:   op.Deprecated,
+		ExternalDocs: op.ExternalDocs,
+	}
+
+	seen := map[string]bool{}
+	for _, list := range [][]*oas.Parameter{op.Parameters, pathItem.Parameters} {
+		for _, p := range list {
+			p = components.ResolveParameter(p)
+			if p == nil || seen[string(p.In)+"."+p.Name] {
+				continue
+			}
+			seen[string(p.In)+"."+p.Name] = true
+			v.Parameters = append(v.Parameters, newParameterView(components, p.Name, string(p.In), &p.ParameterCommonObject))
+		}
+	}
+
+	if rb := components.ResolveRequestBody(op.RequestBody); rb != nil {
+		v.RequestBody = &BodyView{
+			Description: rb.Description,
+			Required:    rb.Required,
+			Contents:    newContentViews(components, rb.Content),
+		}
+	}
+
+	for _, sr := range sortedResponses(&op.Responses.ResponsesObject) {
+		r := components.ResolveResponse(sr.response)
+		if r == nil {
+			continue
+		}
+		rv := &ResponseView{
+			Status:      sr.status,
+			Description: r.Description,
+			Contents:    newContentViews(components, r.Content),
+		}
+		for _, name := range slices.Sorted(maps.Keys(r.Headers)) {
+			if h := components.ResolveHeader(r.Headers[name]); h != nil {
+				rv.Headers = append(rv.Headers, newParameterView(components, name, string(oas.PositionHeader), &h.ParameterCommonObject))
+			}
+		}
+		v.Responses = append(v.Responses, rv)
+	}
+
+	if op.SecurityDeclared() || len(openapi.Security) > 0 {
+		v.Security = newSecurityView(openapi.EffectiveSecurity(op))
+		if v.Security == nil {
+			v.Security = &SecurityView{Optional: true}
+		}
+	}
+
+	return v
+}
+
+func newParameterView(components *oas.ComponentsObject, name string, in string, p *oas.ParameterCommonObject) *ParameterView {
+	s := p.Schema
+	if s == nil {
+		// parameters with content has single media type
+		for _, ct := range slices.Sorted(maps.Keys(p.Content)) {
+			if p.Content[ct] != nil {
+				s = p.Content[ct].Schema
+			}
+			break
+		}
+	}
+
+	return &ParameterView{
+		Name:        name,
+		In:          in,
+		Required:    p.Required,
+		Deprecated:  p.Deprecated,
+		Type:        orAny(TypeSummary(s)),
+		Description: describe(p.Description, components.ResolveSchema(s)),
+	}
+}
+
+func newContentViews(components *oas.ComponentsObject, content map[string]*oas.MediaType) []*ContentView {
+	views := make([]*ContentView, 0, len(content))
+
+	for _, ct := range slices.Sorted(maps.Keys(content)) {
+		mt := content[ct]
+		if mt == nil {
+			continue
+		}
+
+		v := &ContentView{ContentType: ct}
+		if mt.Schema != nil {
+			v.Type = orAny(TypeSummary(mt.Schema))
+			v.Tree = SchemaTree(components, mt.Schema)
+		}
+
+		if mt.Example.Present {
+			v.Examples = append(v.Examples, &ExampleView{Name: "example", Value: indentJSON(mt.Example.Value)})
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(mt.Examples)) {
+			e := components.ResolveExample(mt.Examples[name])
+			if e == nil {
+				continue
+			}
+			ev := &ExampleView{
+				Name:          name,
+				Summary:       e.Summary,
+				Description:   e.Description,
+				ExternalValue: e.ExternalValue,
+			}
+			if e.Value.Present {
+				ev.Value = indentJSON(e.Value.Value)
+			}
+			v.Examples = append(v.Examples, ev)
+		}
+
+		views = append(views, v)
+	}
+
+	return views
+}
+
+func indentJSON(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+type statusResponse struct {
+	status   string
+	response *oas.Response
+}
+
+// sortedResponses returns responses ordered by status codes and ranges, default at last
+func sortedResponses(o *oas.ResponsesObject) []statusResponse {
+	list := make([]statusResponse, 0)
+	for status := range o.Responses {
+		list = append(list, statusResponse{status: fmt.Sprintf("%d", status), response: o.Responses[status]})
+	}
+	for class := range o.Ranges {
+		list = append(list, statusResponse{status: fmt.Sprintf("%dXX", class), response: o.Ranges[class]})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].status < list[j].status
+	})
+	if o.Default != nil {
+		list = append(list, statusResponse{status: "default", response: o.Default})
+	}
+	return list
+}
+
+func newSecurityView(requirements []*oas.SecurityRequirement) *SecurityView {
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	v := &SecurityView{}
+
+	for _, sr := range requirements {
+		if sr == nil || len(*sr) == 0 {
+			v.Optional = true
+			continue
+		}
+
+		parts := make([]string, 0, len(*sr))
+		for _, name := range slices.Sorted(maps.Keys(*sr)) {
+			part := "`" + name + "`"
+			if scopes := (*sr)[name]; len(scopes) > 0 {
+				part += " (" + strings.Join(scopes, ", ") + ")"
+			}
+			parts = append(parts, part)
+		}
+		v.Requirements = append(v.Requirements, strings.Join(parts, " and "))
+	}
+
+	return v
+}
@@ -40,6 +40,24 @@ type MediaType struct {
 	SpecExtensions
 }
 
+func (i MediaType) WithExample(value interface{}) *MediaType {
+	c := i.Clone()
+	c.Example = AnyValue(deepClone(value))
+	return c
+}
+
+func (i MediaType) WithNamedExample(name string, e *Example) *MediaType {
+	c := i.Clone()
+	c.AddExample(name, e.Clone())
+	return c
+}
+
+func (i MediaType) WithPropertyEncoding(name string, e *Encoding) *MediaType {
+	c := i.Clone()
+	c.AddEncoding(name, e.Clone())
+	return c
+}
+
 func (i MediaType) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.MediaTypeObject, i.SpecExtensions)
 }
@@ -49,8 +67,8 @@ func (i *MediaType) UnmarshalJSON(data []byte) error {
 }
 
 type MediaTypeObject struct {
-	Schema  *Schema     `json:"schema,omitempty"`
-	Example interface{} `json:"example,omitempty"`
+	Schema  *Schema `json:"schema,omitempty"`
+	Example Any     `json:"example,omitzero"`
 	WithExamples
 	WithEncoding
 }
@@ -78,6 +96,25 @@ type Encoding struct {
 	SpecExtensions
 }
 
+func (i Encoding) WithContentType(contentType string) *Encoding {
+	c := i.Clone()
+	c.ContentType = contentType
+	return c
+}
+
+func (i Encoding) WithHeader(name string, h *Header) *Encoding {
+	c := i.Clone()
+	c.AddHeader(name, h.Clone())
+	return c
+}
+
+func (i Encoding) WithStyle(style ParameterStyle, explode bool) *Encoding {
+	c := i.Clone()
+	c.Style = style
+	c.Explode = &explode
+	return c
+}
+
 func (i Encoding) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.EncodingObject, i.SpecExtensions)
 }
@@ -86,10 +123,24 @@ func (i *Encoding) UnmarshalJSON(data []byte) error {
 	return flattenUnmarshalJSON(data, &i.EncodingObject, &i.SpecExtensions)
 }
 
+func (o *EncodingObject) StyleOrDefault() ParameterStyle {
+	if o.Style != "" {
+		return o.Style
+	}
+	return ParameterStyleForm
+}
+
+func (o *EncodingObject) ExplodeOrDefault() bool {
+	if o.Explode != nil {
+		return *o.Explode
+	}
+	return o.StyleOrDefault() == ParameterStyleForm
+}
+
 type EncodingObject struct {
 	ContentType string `json:"contentType,omitempty"`
 	WithHeaders
 	Style         ParameterStyle `json:"style,omitempty"`
-	Explode       bool           `json:"explode,omitempty"`
+	Explode       *bool          `json:"explode,omitempty"`
 	AllowReserved bool           `json:"allowReserved,omitempty"`
 }
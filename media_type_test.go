@@ -17,10 +17,10 @@ func TestMediaType(t *testing.T) {
 
 	g.It("with schema and example", `{"schema":{"type":"string"},"example":"some string","examples":{"some":{"value":"string","externalValue":"string"}}}`, func() *MediaType {
 		m := NewMediaTypeWithSchema(String())
-		m.Example = "some string"
+		m.Example = AnyValue("some string")
 
 		ex := NewExample()
-		ex.Value = "string"
+		ex.Value = AnyValue("string")
 		ex.ExternalValue = "string"
 
 		m.AddExample("some", ex)
@@ -40,5 +40,16 @@ func TestMediaType(t *testing.T) {
 		return m
 	}())
 
+	g.It("with builders", `{"schema":{"type":"string"},"example":"some string","examples":{"some":{"summary":"some","description":"desc","value":"string"}},"encoding":{"utf-8":{"contentType":"application/json","headers":{"x-a":{"schema":{"type":"string"}}},"style":"simple","explode":false}}}`,
+		NewMediaTypeWithSchema(String()).
+			WithExample("some string").
+			WithNamedExample("some", NewExample().WithExternalValue("string").WithValue("string").WithSummary("some").WithDesc("desc")).
+			WithPropertyEncoding("utf-8", NewEncoding().
+				WithContentType("application/json").
+				WithHeader("x-a", NewHeaderWithSchema(String())).
+				WithStyle(ParameterStyleSimple, false),
+			),
+	)
+
 	g.Run(t)
 }
@@ -0,0 +1,553 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CollisionPolicy decides how to resolve components with same name but different values
+type CollisionPolicy int
+
+const (
+	// CollisionReport keeps the first component and reports a conflict
+	CollisionReport CollisionPolicy = iota
+	// CollisionPrefix renames the later component with prefix of its document
+	CollisionPrefix
+	// CollisionRename renames the later component with numeric suffix, like Pet2
+	CollisionRename
+)
+
+// ExtensionPolicy decides how to merge values of same spec extension
+type ExtensionPolicy int
+
+const (
+	// ExtensionKeepFirst keeps the value merged first
+	ExtensionKeepFirst ExtensionPolicy = iota
+	// ExtensionOverride uses the value merged last
+	ExtensionOverride
+	// ExtensionReport keeps the first value and reports a conflict when values differ
+	ExtensionReport
+)
+
+type MergeConflict struct {
+	// Document is index of the document conflicts with documents merged before
+	Document int
+	Pointer  string
+	Message  string
+}
+
+func (c *MergeConflict) Error() string {
+	return fmt.Sprintf("document %d: %s: %s", c.Document, c.Pointer, c.Message)
+}
+
+type MergeConflicts []*MergeConflict
+
+func (errs MergeConflicts) Error() string {
+	buf := bytes.NewBuffer(nil)
+	for i, e := range errs {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(e.Error())
+	}
+	return buf.String()
+}
+
+// Merger merges documents into one.
+//
+// Components with same name and structurally equal values are merged into one,
+// the ones with different values are resolved by the Collision policy, refs to renamed components rewritten.
+// Conflicted operations, path items and operationIds are reported, the first merged kept,
+// so are paths same as the ones merged before when names of parameters ignored, like /pets/{id} and /pets/{petId}.
+//
+// Top-level security and servers of later documents are moved into their operations without ones declared
+// when different from the ones of the first document, so that every operation keeps its effective security and servers.
+type Merger struct {
+	Collision CollisionPolicy
+	// Prefix returns prefix for CollisionPrefix, title of the document in PascalCase used when nil
+	Prefix     func(index int, doc *OpenAPI) string
+	Extensions ExtensionPolicy
+}
+
+// Merge merges documents with default Merger
+func Merge(docs ...*OpenAPI) (*OpenAPI, error) {
+	return (&Merger{}).Merge(docs...)
+}
+
+// Merge returns the merged document, with MergeConflicts as error when conflicts reported.
+// Documents are not modified.
+func (m *Merger) Merge(docs ...*OpenAPI) (*OpenAPI, error) {
+	s := &mergeState{Merger: m, result: NewOpenAPI(), operationIds: map[string]string{}, shapes: map[string]string{}}
+
+	first := true
+	for i, doc := range docs {
+		if doc == nil {
+			continue
+		}
+
+		d := &OpenAPI{}
+		if err := cloneByJSON(doc, d); err != nil {
+			return nil, err
+		}
+
+		s.index = i
+		// components first, security requirements could be renamed
+		s.mergeComponents(d)
+
+		if first {
+			s.result.OpenAPI = d.OpenAPI
+			s.result.Info = d.Info
+			s.result.Security = d.Security
+			s.result.Servers = d.Servers
+			first = false
+		} else {
+			if !jsonEqual(s.result.Security, d.Security) {
+				s.pushDownSecurity(d)
+			}
+			if !jsonEqual(s.result.Servers, d.Servers) {
+				s.pushDownServers(d)
+			}
+		}
+
+		s.mergePaths(d)
+		s.mergeTags(d)
+
+		s.mergeExtensions("", &s.result.SpecExtensions, d.SpecExtensions)
+		s.mergeExtensions("/info", &s.result.Info.SpecExtensions, d.Info.SpecExtensions)
+		s.mergeExtensions("/paths", &s.result.Paths.SpecExtensions, d.Paths.SpecExtensions)
+		s.mergeExtensions("/components", &s.result.Components.SpecExtensions, d.Components.SpecExtensions)
+	}
+
+	if len(s.conflicts) > 0 {
+		return s.result, s.conflicts
+	}
+	return s.result, nil
+}
+
+var mergeGroups = append(componentGroups[:len(componentGroups):len(componentGroups)], "securitySchemes")
+
+type mergeState struct {
+	*Merger
+	result *OpenAPI
+	index  int
+	// operationIds maps operationId to pointer of the operation merged
+	operationIds map[string]string
+	// shapes maps shape of path templates to the path merged
+	shapes    map[string]string
+	conflicts MergeConflicts
+}
+
+func (s *mergeState) conflict(pointer string, format string, args ...interface{}) {
+	s.conflicts = append(s.conflicts, &MergeConflict{Document: s.index, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// pushDownSecurity sets top-level security of the document to its operations without security declared
+func (s *mergeState) pushDownSecurity(d *OpenAPI) {
+	security := d.Security
+	if security == nil {
+		// not declared means no security required
+		security = []*SecurityRequirement{}
+	}
+	for _, path := range slices.Sorted(maps.Keys(d.Paths.Paths)) {
+		item := d.Paths.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, method := range slices.Sorted(maps.Keys(item.Operations.Operations)) {
+			op := item.Operations.Operations[method]
+			if op != nil && !op.SecurityDeclared() {
+				_ = cloneByJSON(security, &op.Security)
+				if op.Security == nil {
+					op.Security = []*SecurityRequirement{}
+				}
+			}
+		}
+	}
+	d.Security = nil
+}
+
+// pushDownServers sets top-level servers of the document to its operations without servers declared,
+// the ones of path items kept
+func (s *mergeState) pushDownServers(d *OpenAPI) {
+	servers := d.Servers
+	if len(servers) == 0 {
+		// not declared means the server of url /
+		servers = []*Server{NewServer("/")}
+	}
+	for _, path := range slices.Sorted(maps.Keys(d.Paths.Paths)) {
+		item := d.Paths.Paths[path]
+		if item == nil || len(item.Servers) > 0 {
+			continue
+		}
+		for _, method := range slices.Sorted(maps.Keys(item.Operations.Operations)) {
+			op := item.Operations.Operations[method]
+			if op != nil && len(op.Servers) == 0 {
+				_ = cloneByJSON(servers, &op.Servers)
+			}
+		}
+	}
+	d.Servers = nil
+}
+
+func (s *mergeState) mergeComponents(d *OpenAPI) {
+	renames := s.resolveCollisions(d)
+
+	if len(renames) > 0 {
+		refVisitor(func(group string, ref *Reference) {
+			if r := ParseComponentRefer(ref.Refer.RefString()); r != nil {
+				if name, ok := renames[r.Group+"/"+r.ID]; ok {
+					ref.Refer = NewComponentRefer(r.Group, name)
+				}
+			}
+		}).openapi(d)
+
+		renameSecurity := func(requirements []*SecurityRequirement) {
+			for _, sr := range requirements {
+				if sr == nil {
+					continue
+				}
+				for _, name := range slices.Sorted(maps.Keys(*sr)) {
+					if renamed, ok := renames["securitySchemes/"+name]; ok {
+						(*sr)[renamed] = (*sr)[name]
+						delete(*sr, name)
+					}
+				}
+			}
+		}
+		renameSecurity(d.Security)
+		for _, item := range d.Paths.Paths {
+			if item != nil {
+				for _, op := range item.Operations.Operations {
+					if op != nil {
+						renameSecurity(op.Security)
+					}
+				}
+			}
+		}
+	}
+
+	for _, group := range mergeGroups {
+		for _, name := range d.componentNames(group) {
+			target := name
+			if renamed, ok := renames[group+"/"+name]; ok {
+				target = renamed
+			}
+			if s.result.componentValue(group, target) != nil {
+				// deduplicated or conflicted
+				continue
+			}
+			s.result.setComponent(group, target, d.componentValue(group, name))
+		}
+	}
+}
+
+// resolveCollisions returns new names of components of the document, keyed by group/name.
+// renaming changes refs, which may make other components different, so repeat until no more renames.
+func (s *mergeState) resolveCollisions(d *OpenAPI) map[string]string {
+	renames := map[string]string{}
+	reported := map[string]bool{}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, group := range mergeGroups {
+			for _, name := range d.componentNames(group) {
+				key := group + "/" + name
+				if _, ok := renames[key]; ok || reported[key] {
+					continue
+				}
+
+				existing := s.result.componentValue(group, name)
+				if existing == nil {
+					continue
+				}
+
+				value := renamedComponent(d, group, name, renames)
+				if componentEqual(group, existing, value) {
+					continue
+				}
+
+				switch s.Collision {
+				case CollisionPrefix, CollisionRename:
+					renames[key] = s.newName(d, group, name, value)
+					changed = true
+				default:
+					reported[key] = true
+					s.conflict("/components/"+group+"/"+escapeJSONPointer(name), "%s %q differs from the one merged before", group, name)
+				}
+			}
+		}
+	}
+
+	return renames
+}
+
+// newName returns name not used by the merged document, or used by structurally equal one
+func (s *mergeState) newName(d *OpenAPI, group string, name string, value interface{}) string {
+	base := name
+	if s.Collision == CollisionPrefix {
+		prefix := ""
+		if s.Prefix != nil {
+			prefix = s.Prefix(s.index, d)
+		} else {
+			prefix = pascalCase(d.Title)
+		}
+		if prefix == "" {
+			prefix = "Doc" + strconv.Itoa(s.index)
+		}
+		base = prefix + name
+	}
+
+	for i := 1; ; i++ {
+		n := base
+		if s.Collision == CollisionRename {
+			n = base + strconv.Itoa(i+1)
+		} else if i > 1 {
+			n = base + strconv.Itoa(i)
+		}
+		existing := s.result.componentValue(group, n)
+		if existing == nil && d.componentValue(group, n) == nil {
+			return n
+		}
+		if existing != nil && componentEqual(group, existing, value) {
+			return n
+		}
+	}
+}
+
+// renamedComponent returns json value of the component with refs renamed
+func renamedComponent(d *OpenAPI, group string, name string, renames map[string]string) interface{} {
+	value := d.componentValue(group, name)
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return value
+	}
+
+	var rename func(v interface{})
+	rename = func(v interface{}) {
+		switch x := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := x["$ref"].(string); ok {
+				if r := ParseComponentRefer(ref); r != nil {
+					if n, ok := renames[r.Group+"/"+r.ID]; ok {
+						x["$ref"] = NewComponentRefer(r.Group, n).RefString()
+					}
+				}
+				return
+			}
+			for k := range x {
+				rename(x[k])
+			}
+		case []interface{}:
+			for i := range x {
+				rename(x[i])
+			}
+		}
+	}
+	rename(v)
+
+	return v
+}
+
+func (s *mergeState) mergePaths(d *OpenAPI) {
+	for _, path := range slices.Sorted(maps.Keys(d.Paths.Paths)) {
+		item := d.Paths.Paths[path]
+		if item == nil {
+			continue
+		}
+		pointer := "/paths/" + escapeJSONPointer(path)
+
+		shape := pathShape(path)
+		if prev, ok := s.shapes[shape]; ok && prev != path {
+			s.conflict(pointer, "path %s conflicts with %s merged before", path, prev)
+			continue
+		}
+		s.shapes[shape] = path
+
+		existing := s.result.Paths.Paths[path]
+		if existing == nil {
+			existing = &PathItem{}
+			existing.PathItemObject = item.PathItemObject
+			existing.SpecExtensions = item.SpecExtensions
+			s.result.Paths.Paths[path] = existing
+		} else {
+			if existing.Summary == "" {
+				existing.Summary = item.Summary
+			}
+			if existing.Description == "" {
+				existing.Description = item.Description
+			}
+			if len(existing.Servers) == 0 {
+				existing.Servers = item.Servers
+			} else if len(item.Servers) > 0 && !jsonEqual(existing.Servers, item.Servers) {
+				s.conflict(pointer+"/servers", "servers differ from the ones merged before")
+			}
+			if len(existing.Parameters) == 0 {
+				existing.Parameters = item.Parameters
+			} else if len(item.Parameters) > 0 && !jsonEqual(existing.Parameters, item.Parameters) {
+				s.conflict(pointer+"/parameters", "parameters differ from the ones merged before")
+			}
+			s.mergeExtensions(pointer, &existing.SpecExtensions, item.SpecExtensions)
+		}
+
+		for _, method := range slices.Sorted(maps.Keys(item.Operations.Operations)) {
+			op := item.Operations.Operations[method]
+			if op == nil {
+				continue
+			}
+			opPointer := pointer + "/" + string(method)
+
+			if prev := existing.Operations.Operations[method]; prev != nil {
+				if !jsonEqual(prev, op) {
+					s.conflict(opPointer, "operation %s %s conflicts with the one merged before", strings.ToUpper(string(method)), path)
+				}
+				continue
+			}
+
+			if op.OperationId != "" {
+				if prev, ok := s.operationIds[op.OperationId]; ok {
+					s.conflict(opPointer+"/operationId", "operationId %q used by %s", op.OperationId, prev)
+				} else {
+					s.operationIds[op.OperationId] = opPointer
+				}
+			}
+
+			existing.AddOperation(method, op)
+		}
+	}
+}
+
+func (s *mergeState) mergeTags(d *OpenAPI) {
+	for _, t := range d.Tags {
+		if t == nil {
+			continue
+		}
+		merged := false
+		for i, existing := range s.result.Tags {
+			if existing != nil && existing.Name == t.Name {
+				if existing.Description == "" {
+					existing.Description = t.Description
+				}
+				if existing.ExternalDocs == nil {
+					existing.ExternalDocs = t.ExternalDocs
+				}
+				s.mergeExtensions("/tags/"+strconv.Itoa(i), &existing.SpecExtensions, t.SpecExtensions)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			s.result.AddTag(t)
+		}
+	}
+}
+
+func (s *mergeState) mergeExtensions(pointer string, dst *SpecExtensions, src SpecExtensions) {
+	for _, key := range slices.Sorted(maps.Keys(src.Extensions)) {
+		value := src.Extensions[key]
+		existing, ok := dst.Extensions[key]
+		if !ok {
+			dst.AddExtension(key, value)
+			continue
+		}
+		switch s.Extensions {
+		case ExtensionOverride:
+			dst.AddExtension(key, value)
+		case ExtensionReport:
+			if !jsonEqual(existing, value) {
+				s.conflict(pointer+"/"+escapeJSONPointer(key), "value of %s differs from the one merged before", key)
+			}
+		}
+	}
+}
+
+func (object *ComponentsObject) setComponent(group string, name string, value interface{}) {
+	switch v := value.(type) {
+	case *Schema:
+		object.AddSchema(name, v)
+	case *Response:
+		object.AddResponse(name, v)
+	case *Parameter:
+		object.AddParameter(name, v)
+	case *Example:
+		object.AddExample(name, v)
+	case *RequestBody:
+		object.AddRequestBody(name, v)
+	case *Header:
+		object.AddHeader(name, v)
+	case *Link:
+		object.AddLink(name, v)
+	case *Callback:
+		object.AddCallback(name, v)
+	case *SecurityScheme:
+		object.AddSecurityScheme(name, v)
+	}
+}
+
+func cloneByJSON(src interface{}, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// componentEqual compares components structurally, orders of required and enum of schemas ignored
+func componentEqual(group string, a interface{}, b interface{}) bool {
+	if group == "schemas" {
+		sa, sb := &Schema{}, &Schema{}
+		if cloneByJSON(a, sa) == nil && cloneByJSON(b, sb) == nil {
+			return SchemaEqual(sa, sb)
+		}
+	}
+	return jsonEqual(a, b)
+}
+
+// jsonEqual compares json values of a and b, keys of objects sorted
+func jsonEqual(a interface{}, b interface{}) bool {
+	dataA, errA := canonicalJSON(a)
+	dataB, errB := canonicalJSON(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+func canonicalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
+
+// pascalCase converts words of s into PascalCase, like "Pet Store" into "PetStore"
+func pascalCase(s string) string {
+	b := strings.Builder{}
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,194 @@
+package oas
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newServiceDoc(title string, path string, operationId string, petProps Props) *OpenAPI {
+	openapi := NewOpenAPI()
+	openapi.Title = title
+	openapi.Version = "1.0.0"
+	openapi.AddServer(NewServer("https://api.example.com"))
+	openapi.AddTag(NewTag("pets"))
+
+	openapi.AddSchema("Pet", ObjectOf(petProps))
+	openapi.AddSchema("Pets", ItemsOf(openapi.RefSchema("Pet")))
+
+	op := NewOperation(operationId)
+	resp := NewResponse("ok")
+	resp.AddContent("application/json", NewMediaTypeWithSchema(openapi.RefSchema("Pets")))
+	op.AddResponse(http.StatusOK, resp)
+	openapi.AddOperation(GET, path, op)
+
+	return openapi
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("structurally equal components deduplicated", func(t *testing.T) {
+		a := newServiceDoc("A", "/a/pets", "listA", Props{"name": String()})
+		b := newServiceDoc("B", "/b/pets", "listB", Props{"name": String()})
+		a.AddSchema("Kind", String().WithValidation(&SchemaValidation{Enum: []interface{}{"cat", "dog"}}))
+		b.AddSchema("Kind", String().WithValidation(&SchemaValidation{Enum: []interface{}{"dog", "cat"}}))
+
+		merged, err := Merge(a, b)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "A", merged.Title)
+		assert.Equal(t, []string{"Kind", "Pet", "Pets"}, slices.Sorted(maps.Keys(merged.Schemas)))
+		assert.Equal(t, []string{"/a/pets", "/b/pets"}, slices.Sorted(maps.Keys(merged.Paths.Paths)))
+		assert.Len(t, merged.Tags, 1)
+		assert.Len(t, merged.Servers, 1)
+
+		// inputs not touched
+		assert.Len(t, a.Paths.Paths, 1)
+	})
+
+	t.Run("collisions reported", func(t *testing.T) {
+		a := newServiceDoc("A", "/a/pets", "listA", Props{"name": String()})
+		b := newServiceDoc("B", "/b/pets", "listB", Props{"id": Long()})
+
+		merged, err := Merge(a, b)
+
+		conflicts := MergeConflicts{}
+		assert.True(t, errors.As(err, &conflicts))
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, 1, conflicts[0].Document)
+		assert.Equal(t, "/components/schemas/Pet", conflicts[0].Pointer)
+
+		assert.NotNil(t, merged.Schemas["Pet"].Properties["name"])
+	})
+
+	t.Run("collisions prefixed with refs rewritten", func(t *testing.T) {
+		a := newServiceDoc("A", "/a/pets", "listA", Props{"name": String()})
+		b := newServiceDoc("pet store", "/b/pets", "listB", Props{"id": Long()})
+
+		merged, err := (&Merger{Collision: CollisionPrefix}).Merge(a, b)
+		assert.NoError(t, err)
+
+		// Pets renamed too, since its ref changed
+		assert.Equal(t, []string{"Pet", "PetStorePet", "PetStorePets", "Pets"}, slices.Sorted(maps.Keys(merged.Schemas)))
+		assert.Equal(t, "#/components/schemas/PetStorePet", merged.Schemas["PetStorePets"].Items.Refer.RefString())
+
+		schema := merged.Paths.Paths["/b/pets"].Operations.Operations[GET].Responses.Responses[200].Content["application/json"].Schema
+		assert.Equal(t, "#/components/schemas/PetStorePets", schema.Refer.RefString())
+
+		schema = merged.Paths.Paths["/a/pets"].Operations.Operations[GET].Responses.Responses[200].Content["application/json"].Schema
+		assert.Equal(t, "#/components/schemas/Pets", schema.Refer.RefString())
+	})
+
+	t.Run("collisions renamed", func(t *testing.T) {
+		a := newServiceDoc("A", "/a/pets", "listA", Props{"name": String()})
+		b := newServiceDoc("B", "/b/pets", "listB", Props{"id": Long()})
+		c := newServiceDoc("C", "/c/pets", "listC", Props{"id": Long()})
+
+		merged, err := (&Merger{Collision: CollisionRename, Prefix: func(index int, doc *OpenAPI) string { return "X" }}).Merge(a, b, c)
+		assert.NoError(t, err)
+
+		// renamed ones of c equal to the ones of b
+		assert.Equal(t, []string{"Pet", "Pet2", "Pets", "Pets2"}, slices.Sorted(maps.Keys(merged.Schemas)))
+		schema := merged.Paths.Paths["/c/pets"].Operations.Operations[GET].Responses.Responses[200].Content["application/json"].Schema
+		assert.Equal(t, "#/components/schemas/Pets2", schema.Refer.RefString())
+	})
+
+	t.Run("path and operationId conflicts", func(t *testing.T) {
+		a := newServiceDoc("A", "/pets", "listPets", Props{"name": String()})
+		b := newServiceDoc("B", "/pets", "listAllPets", Props{"name": String()})
+		c := newServiceDoc("C", "/other/pets", "listPets", Props{"name": String()})
+
+		merged, err := Merge(a, b, c)
+
+		conflicts := MergeConflicts{}
+		assert.True(t, errors.As(err, &conflicts))
+		assert.Len(t, conflicts, 2)
+		assert.Equal(t, "/paths/~1pets/get", conflicts[0].Pointer)
+		assert.Equal(t, "/paths/~1other~1pets/get/operationId", conflicts[1].Pointer)
+		assert.Equal(t, "listPets", merged.Paths.Paths["/pets"].Operations.Operations[GET].OperationId)
+	})
+
+	t.Run("paths conflict when parameter names ignored", func(t *testing.T) {
+		a := newServiceDoc("A", "/pets/{id}", "getPet", Props{"name": String()})
+		b := newServiceDoc("B", "/pets/{petId}", "getPetById", Props{"name": String()})
+
+		merged, err := Merge(a, b)
+
+		conflicts := MergeConflicts{}
+		assert.True(t, errors.As(err, &conflicts))
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, "/paths/~1pets~1{petId}", conflicts[0].Pointer)
+		assert.Equal(t, []string{"/pets/{id}"}, slices.Sorted(maps.Keys(merged.Paths.Paths)))
+	})
+
+	t.Run("servers pushed down", func(t *testing.T) {
+		a := newServiceDoc("A", "/a/pets", "listA", Props{"name": String()})
+
+		b := newServiceDoc("B", "/b/pets", "listB", Props{"name": String()})
+		b.Servers = []*Server{NewServer("https://b.example.com")}
+		create := NewOperation("createB")
+		create.AddServer(NewServer("https://write.b.example.com"))
+		b.AddOperation(POST, "/b/pets", create)
+
+		c := newServiceDoc("C", "/c/pets", "listC", Props{"name": String()})
+		c.Servers = nil
+
+		d := newServiceDoc("D", "/d/pets", "listD", Props{"name": String()})
+
+		merged, err := Merge(a, b, c, d)
+		assert.NoError(t, err)
+
+		assert.Equal(t, a.Servers, merged.Servers)
+		assert.Empty(t, merged.Paths.Paths["/a/pets"].Operations.Operations[GET].Servers)
+		assert.Equal(t, b.Servers, merged.Paths.Paths["/b/pets"].Operations.Operations[GET].Servers)
+		assert.Equal(t, "https://write.b.example.com", merged.Paths.Paths["/b/pets"].Operations.Operations[POST].Servers[0].URL)
+		assert.Equal(t, []*Server{NewServer("/")}, merged.Paths.Paths["/c/pets"].Operations.Operations[GET].Servers)
+		assert.Empty(t, merged.Paths.Paths["/d/pets"].Operations.Operations[GET].Servers)
+
+		// inputs not touched
+		assert.Len(t, b.Servers, 1)
+	})
+
+	t.Run("security pushed down", func(t *testing.T) {
+		a := newServiceDoc("A", "/a/pets", "listA", Props{"name": String()})
+		a.AddSecurityScheme("token", NewHTTPSecurityScheme("bearer", "JWT"))
+		a.AddSecurityRequirement(&SecurityRequirement{"token": []string{}})
+
+		b := newServiceDoc("B", "/b/pets", "listB", Props{"name": String()})
+		b.AddSecurityScheme("token", NewAPIKeySecurityScheme("token", PositionHeader))
+		b.AddSecurityRequirement(&SecurityRequirement{"token": []string{}})
+
+		c := newServiceDoc("C", "/c/pets", "listC", Props{"name": String()})
+
+		merged, err := (&Merger{Collision: CollisionRename}).Merge(a, b, c)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"token", "token2"}, slices.Sorted(maps.Keys(merged.SecuritySchemes)))
+		assert.Equal(t, a.Security, merged.Security)
+		assert.False(t, merged.Paths.Paths["/a/pets"].Operations.Operations[GET].SecurityDeclared())
+		assert.Equal(t, []*SecurityRequirement{&SecurityRequirement{"token2": []string{}}}, merged.Paths.Paths["/b/pets"].Operations.Operations[GET].Security)
+		assert.Equal(t, []*SecurityRequirement{}, merged.Paths.Paths["/c/pets"].Operations.Operations[GET].Security)
+	})
+
+	t.Run("extensions", func(t *testing.T) {
+		a := newServiceDoc("A", "/a/pets", "listA", Props{"name": String()})
+		a.AddExtension("x-owner", "team-a")
+		b := newServiceDoc("B", "/b/pets", "listB", Props{"name": String()})
+		b.AddExtension("x-owner", "team-b")
+		b.AddExtension("x-audience", "public")
+
+		merged, err := Merge(a, b)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"x-owner": "team-a", "x-audience": "public"}, merged.Extensions)
+
+		merged, err = (&Merger{Extensions: ExtensionOverride}).Merge(a, b)
+		assert.NoError(t, err)
+		assert.Equal(t, "team-b", merged.Extensions["x-owner"])
+
+		_, err = (&Merger{Extensions: ExtensionReport}).Merge(a, b)
+		assert.EqualError(t, err, "document 1: /x-owner: value of x-owner differs from the one merged before")
+	})
+}
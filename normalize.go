@@ -0,0 +1,649 @@
+package oas
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+)
+
+type SchemaConflict struct {
+	Pointer string
+	Message string
+}
+
+func (c *SchemaConflict) Error() string {
+	return fmt.Sprintf("%s: %s", c.Pointer, c.Message)
+}
+
+type SchemaConflicts []*SchemaConflict
+
+func (errs SchemaConflicts) Error() string {
+	buf := bytes.NewBuffer(nil)
+	for i, e := range errs {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(e.Error())
+	}
+	return buf.String()
+}
+
+// Normalizer simplifies schemas.
+//
+// Members of allOf are merged into one schema, properties and required unioned,
+// bounds and enums intersected, contradictions like conflicting types reported as SchemaConflicts.
+// Refs to schemas with discriminator are kept in allOf, so that the polymorphism works still,
+// and keywords could not be merged, like different patterns, are kept in allOf too.
+//
+// oneOf or anyOf with single member is merged as allOf.
+type Normalizer struct {
+	// InlineRefs inlines refs to trivial schemas, which have no properties, items, compositions or discriminator
+	InlineRefs bool
+}
+
+// Normalize normalizes schemas of the document, returns the normalized copy with SchemaConflicts as error when conflicts reported.
+func (n *Normalizer) Normalize(openapi *OpenAPI) (*OpenAPI, error) {
+	o := &OpenAPI{}
+	if err := cloneByJSON(openapi, o); err != nil {
+		return nil, err
+	}
+
+	s := n.newState(&o.ComponentsObject)
+	for _, name := range slices.Sorted(maps.Keys(o.Schemas)) {
+		s.component(name)
+	}
+	o.eachSchema(func(pointer string, schema *Schema) {
+		*schema = *s.schema(pointer, schema)
+	})
+	for name, schema := range s.normalized {
+		if schema != nil {
+			o.Schemas[name] = schema
+		}
+	}
+
+	if len(s.conflicts) > 0 {
+		return o, s.conflicts
+	}
+	return o, nil
+}
+
+// NormalizeSchema returns the normalized copy of the schema, refs resolved by the components.
+func (n *Normalizer) NormalizeSchema(components *ComponentsObject, schema *Schema) (*Schema, error) {
+	if components == nil {
+		components = &ComponentsObject{}
+	}
+	s := n.newState(components)
+	normalized := s.schema("", schema)
+	if len(s.conflicts) > 0 {
+		return normalized, s.conflicts
+	}
+	return normalized, nil
+}
+
+// FlattenAllOf normalizes the schema with default Normalizer
+func FlattenAllOf(components *ComponentsObject, schema *Schema) (*Schema, error) {
+	return (&Normalizer{}).NormalizeSchema(components, schema)
+}
+
+func (n *Normalizer) newState(components *ComponentsObject) *normalizeState {
+	return &normalizeState{
+		Normalizer: n,
+		components: components,
+		normalized: map[string]*Schema{},
+		resolving:  map[string]bool{},
+	}
+}
+
+type normalizeState struct {
+	*Normalizer
+	components *ComponentsObject
+	// normalized holds normalized component schemas, nil for circular ones
+	normalized map[string]*Schema
+	resolving  map[string]bool
+	conflicts  SchemaConflicts
+}
+
+func (s *normalizeState) conflict(pointer string, format string, args ...interface{}) {
+	s.conflicts = append(s.conflicts, &SchemaConflict{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// component returns the normalized component schema, nil when not found or resolving in circle
+func (s *normalizeState) component(name string) *Schema {
+	if normalized, ok := s.normalized[name]; ok {
+		return normalized
+	}
+	schema := s.components.Schemas[name]
+	if schema == nil || s.resolving[name] {
+		return nil
+	}
+	s.resolving[name] = true
+	normalized := s.schema("/components/schemas/"+escapeJSONPointer(name), schema)
+	delete(s.resolving, name)
+	s.normalized[name] = normalized
+	return normalized
+}
+
+// schema returns the normalized copy of the schema
+func (s *normalizeState) schema(pointer string, schema *Schema) *Schema {
+	if schema == nil {
+		return nil
+	}
+
+	if schema.Refer != nil {
+		if s.InlineRefs {
+			if target := s.components.ResolveSchema(schema); target != nil && target.Refer == nil && isTrivialSchema(target) {
+				copied := *target
+				return &copied
+			}
+		}
+		copied := *schema
+		return &copied
+	}
+
+	out := *schema
+	out.Items = s.schema(pointer+"/items", schema.Items)
+	if schema.Properties != nil {
+		out.Properties = make(map[string]*Schema, len(schema.Properties))
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			out.Properties[name] = s.schema(pointer+"/properties/"+escapeJSONPointer(name), schema.Properties[name])
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		out.AdditionalProperties = &SchemaOrBool{
+			Allows: schema.AdditionalProperties.Allows,
+			Schema: s.schema(pointer+"/additionalProperties", schema.AdditionalProperties.Schema),
+		}
+	}
+	out.PropertyNames = s.schema(pointer+"/propertyNames", schema.PropertyNames)
+	out.Not = s.schema(pointer+"/not", schema.Not)
+	out.AllOf = s.schemas(pointer+"/allOf", schema.AllOf)
+	out.AnyOf = s.schemas(pointer+"/anyOf", schema.AnyOf)
+	out.OneOf = s.schemas(pointer+"/oneOf", schema.OneOf)
+
+	if len(out.OneOf) == 1 {
+		out.AllOf = append(out.AllOf, out.OneOf[0])
+		out.OneOf = nil
+	}
+	if len(out.AnyOf) == 1 {
+		out.AllOf = append(out.AllOf, out.AnyOf[0])
+		out.AnyOf = nil
+	}
+
+	if len(out.AllOf) > 0 {
+		s.flatten(pointer, &out)
+	}
+	return &out
+}
+
+func (s *normalizeState) schemas(pointer string, list []*Schema) []*Schema {
+	if list == nil {
+		return nil
+	}
+	normalized := make([]*Schema, 0, len(list))
+	for i, item := range list {
+		if item != nil {
+			normalized = append(normalized, s.schema(pointer+"/"+strconv.Itoa(i), item))
+		}
+	}
+	return normalized
+}
+
+// flatten merges members of allOf into the schema, members normalized already
+func (s *normalizeState) flatten(pointer string, out *Schema) {
+	members := out.AllOf
+	out.AllOf = nil
+	kept := make([]*Schema, 0)
+
+	for i, member := range members {
+		if member.Refer != nil {
+			name := componentID(member.Refer, "schemas")
+			target := s.components.Schemas[name]
+			if name == "" || target == nil || target.Discriminator != nil {
+				kept = append(kept, member)
+				continue
+			}
+			resolved := s.component(name)
+			if resolved == nil {
+				kept = append(kept, member)
+				continue
+			}
+			member = resolved
+		}
+		if len(member.AllOf) > 0 {
+			kept = append(kept, member.AllOf...)
+			copied := *member
+			copied.AllOf = nil
+			member = &copied
+		}
+		if residual := s.merge(pointer+"/allOf/"+strconv.Itoa(i), out, member); residual != nil {
+			kept = append(kept, residual)
+		}
+	}
+
+	if len(kept) > 0 {
+		out.AllOf = kept
+	}
+	s.checkBounds(pointer, out)
+}
+
+// merge merges src into dst, returns the residual schema with keywords could not be merged
+func (s *normalizeState) merge(pointer string, dst *Schema, src *Schema) *Schema {
+	residual := &Schema{}
+
+	if src.Type != "" {
+		// nullable only when all typed members nullable
+		if dst.Type == "" {
+			dst.Nullable = src.Nullable
+		} else {
+			dst.Nullable = dst.Nullable && src.Nullable
+		}
+	}
+
+	switch {
+	case src.Type == "" || dst.Type == src.Type:
+	case dst.Type == "":
+		dst.Type = src.Type
+	case dst.Type == TypeNumber && src.Type == TypeInteger:
+		dst.Type = TypeInteger
+	case dst.Type == TypeInteger && src.Type == TypeNumber:
+	default:
+		s.conflict(pointer+"/type", "type %s conflicts with %s", src.Type, dst.Type)
+	}
+
+	if src.Format != "" {
+		if dst.Format == "" {
+			dst.Format = src.Format
+		} else if dst.Format != src.Format {
+			s.conflict(pointer+"/format", "format %s conflicts with %s", src.Format, dst.Format)
+		}
+	}
+
+	dst.Items = s.mergeSubSchema(pointer+"/items", dst.Items, src.Items)
+	if len(src.Properties) > 0 {
+		if dst.Properties == nil {
+			dst.Properties = make(map[string]*Schema, len(src.Properties))
+		}
+		for _, name := range slices.Sorted(maps.Keys(src.Properties)) {
+			dst.Properties[name] = s.mergeSubSchema(pointer+"/properties/"+escapeJSONPointer(name), dst.Properties[name], src.Properties[name])
+		}
+	}
+	s.mergeAdditionalProperties(pointer, dst, src)
+	dst.PropertyNames = s.mergeSubSchema(pointer+"/propertyNames", dst.PropertyNames, src.PropertyNames)
+
+	dst.Required = unionStrings(dst.Required, src.Required)
+
+	mergeMaximum(&dst.Maximum, &dst.ExclusiveMaximum, src.Maximum, src.ExclusiveMaximum)
+	mergeMinimum(&dst.Minimum, &dst.ExclusiveMinimum, src.Minimum, src.ExclusiveMinimum)
+	dst.MaxLength = minUint64(dst.MaxLength, src.MaxLength)
+	dst.MinLength = maxUint64(dst.MinLength, src.MinLength)
+	dst.MaxItems = minUint64(dst.MaxItems, src.MaxItems)
+	dst.MinItems = maxUint64(dst.MinItems, src.MinItems)
+	dst.MaxProperties = minUint64(dst.MaxProperties, src.MaxProperties)
+	dst.MinProperties = maxUint64(dst.MinProperties, src.MinProperties)
+	dst.UniqueItems = dst.UniqueItems || src.UniqueItems
+
+	if src.MultipleOf != nil {
+		switch {
+		case dst.MultipleOf == nil || isMultipleOf(*src.MultipleOf, *dst.MultipleOf):
+			dst.MultipleOf = src.MultipleOf
+		case isMultipleOf(*dst.MultipleOf, *src.MultipleOf):
+		default:
+			residual.MultipleOf = src.MultipleOf
+		}
+	}
+
+	if src.Pattern != "" {
+		if dst.Pattern == "" {
+			dst.Pattern = src.Pattern
+		} else if dst.Pattern != src.Pattern {
+			residual.Pattern = src.Pattern
+		}
+	}
+
+	if len(src.Enum) > 0 {
+		if len(dst.Enum) == 0 {
+			dst.Enum = src.Enum
+		} else {
+			enum := make([]interface{}, 0)
+			for _, v := range dst.Enum {
+				for _, w := range src.Enum {
+					if jsonEqual(v, w) {
+						enum = append(enum, v)
+						break
+					}
+				}
+			}
+			if len(enum) == 0 {
+				s.conflict(pointer+"/enum", "no value allowed by enums of all members")
+			}
+			dst.Enum = enum
+		}
+	}
+
+	if len(src.AnyOf) > 0 {
+		if len(dst.AnyOf) == 0 {
+			dst.AnyOf = src.AnyOf
+		} else {
+			residual.AnyOf = src.AnyOf
+		}
+	}
+	if len(src.OneOf) > 0 {
+		if len(dst.OneOf) == 0 {
+			dst.OneOf = src.OneOf
+		} else {
+			residual.OneOf = src.OneOf
+		}
+	}
+	if src.Not != nil {
+		if dst.Not == nil {
+			dst.Not = src.Not
+		} else if !jsonEqual(dst.Not, src.Not) {
+			residual.Not = src.Not
+		}
+	}
+
+	if src.Discriminator != nil {
+		if dst.Discriminator == nil {
+			dst.Discriminator = src.Discriminator
+		} else if dst.Discriminator.PropertyName != src.Discriminator.PropertyName {
+			s.conflict(pointer+"/discriminator", "discriminator property %s conflicts with %s", src.Discriminator.PropertyName, dst.Discriminator.PropertyName)
+		} else {
+			mapping := map[string]string{}
+			for k, v := range dst.Discriminator.Mapping {
+				mapping[k] = v
+			}
+			for _, k := range slices.Sorted(maps.Keys(src.Discriminator.Mapping)) {
+				if v, ok := mapping[k]; ok && v != src.Discriminator.Mapping[k] {
+					s.conflict(pointer+"/discriminator/mapping/"+escapeJSONPointer(k), "mapping to %s conflicts with %s", src.Discriminator.Mapping[k], v)
+					continue
+				}
+				mapping[k] = src.Discriminator.Mapping[k]
+			}
+			if len(mapping) == 0 {
+				mapping = nil
+			}
+			dst.Discriminator = &Discriminator{PropertyName: dst.Discriminator.PropertyName, Mapping: mapping}
+		}
+	}
+
+	if dst.Title == "" {
+		dst.Title = src.Title
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if dst.Default.IsZero() {
+		dst.Default = src.Default
+	}
+	if dst.Example.IsZero() {
+		dst.Example = src.Example
+	}
+	if dst.XML == nil {
+		dst.XML = src.XML
+	}
+	if dst.ExternalDocs == nil {
+		dst.ExternalDocs = src.ExternalDocs
+	}
+	dst.ReadOnly = dst.ReadOnly || src.ReadOnly
+	dst.WriteOnly = dst.WriteOnly || src.WriteOnly
+	dst.Deprecated = dst.Deprecated || src.Deprecated
+
+	if len(src.Extensions) > 0 {
+		// copied, since dst shares the map with the source schema
+		extensions := make(map[string]interface{}, len(dst.Extensions)+len(src.Extensions))
+		for k, v := range src.Extensions {
+			extensions[k] = v
+		}
+		for k, v := range dst.Extensions {
+			extensions[k] = v
+		}
+		dst.Extensions = extensions
+	}
+
+	if jsonEqual(residual, &Schema{}) {
+		return nil
+	}
+	return residual
+}
+
+// mergeSubSchema merges schemas of same property or items, allOf of them normalized when both exists
+func (s *normalizeState) mergeSubSchema(pointer string, dst *Schema, src *Schema) *Schema {
+	if src == nil {
+		return dst
+	}
+	if dst == nil || jsonEqual(dst, src) {
+		return src
+	}
+	return s.schema(pointer, AllOf(dst, src))
+}
+
+func (s *normalizeState) mergeAdditionalProperties(pointer string, dst *Schema, src *Schema) {
+	a, b := dst.AdditionalProperties, src.AdditionalProperties
+	switch {
+	case b == nil:
+	case a == nil:
+		dst.AdditionalProperties = b
+	case b.Schema == nil && !b.Allows, a.Schema == nil && !a.Allows:
+		dst.AdditionalProperties = &SchemaOrBool{}
+	default:
+		dst.AdditionalProperties = &SchemaOrBool{
+			Allows: true,
+			Schema: s.mergeSubSchema(pointer+"/additionalProperties", a.Schema, b.Schema),
+		}
+	}
+}
+
+// checkBounds reports bounds no value could satisfy
+func (s *normalizeState) checkBounds(pointer string, schema *Schema) {
+	if schema.Minimum != nil && schema.Maximum != nil {
+		min, max := *schema.Minimum, *schema.Maximum
+		if min > max || (min == max && (schema.ExclusiveMinimum || schema.ExclusiveMaximum)) {
+			s.conflict(pointer, "minimum %v exceeds maximum %v", min, max)
+		}
+	}
+	for _, bounds := range []struct {
+		name     string
+		min, max *uint64
+	}{
+		{"length", schema.MinLength, schema.MaxLength},
+		{"items", schema.MinItems, schema.MaxItems},
+		{"properties", schema.MinProperties, schema.MaxProperties},
+	} {
+		if bounds.min != nil && bounds.max != nil && *bounds.min > *bounds.max {
+			s.conflict(pointer, "min %s %d exceeds max %s %d", bounds.name, *bounds.min, bounds.name, *bounds.max)
+		}
+	}
+}
+
+// isTrivialSchema returns true when the schema has no sub schemas or discriminator
+func isTrivialSchema(s *Schema) bool {
+	return s.Items == nil && len(s.Properties) == 0 && s.AdditionalProperties == nil && s.PropertyNames == nil &&
+		len(s.AllOf) == 0 && len(s.AnyOf) == 0 && len(s.OneOf) == 0 && s.Not == nil && s.Discriminator == nil
+}
+
+func mergeMaximum(max **float64, exclusive *bool, v *float64, vExclusive bool) {
+	switch {
+	case v == nil:
+	case *max == nil || *v < **max:
+		*max, *exclusive = v, vExclusive
+	case *v == **max:
+		*exclusive = *exclusive || vExclusive
+	}
+}
+
+func mergeMinimum(min **float64, exclusive *bool, v *float64, vExclusive bool) {
+	switch {
+	case v == nil:
+	case *min == nil || *v > **min:
+		*min, *exclusive = v, vExclusive
+	case *v == **min:
+		*exclusive = *exclusive || vExclusive
+	}
+}
+
+func minUint64(a *uint64, b *uint64) *uint64 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+func maxUint64(a *uint64, b *uint64) *uint64 {
+	if a == nil || (b != nil && *b > *a) {
+		return b
+	}
+	return a
+}
+
+// isMultipleOf returns true when a is multiple of b
+func isMultipleOf(a float64, b float64) bool {
+	if b == 0 {
+		return false
+	}
+	q := a / b
+	return math.Abs(q-math.Round(q)) < 1e-9
+}
+
+func unionStrings(a []string, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	union := append(make([]string, 0, len(a)+len(b)), a...)
+	for _, v := range b {
+		if !slices.Contains(union, v) {
+			union = append(union, v)
+		}
+	}
+	return union
+}
+
+// eachSchema calls fn with each schema not in components schemas, sub schemas excluded
+func (o *OpenAPI) eachSchema(fn func(pointer string, s *Schema)) {
+	w := &schemaWalker{fn: fn}
+	for _, group := range componentGroups {
+		if group == "schemas" {
+			continue
+		}
+		for _, name := range o.componentNames(group) {
+			pointer := "/components/" + group + "/" + escapeJSONPointer(name)
+			switch group {
+			case "responses":
+				w.response(pointer, o.Responses[name])
+			case "parameters":
+				w.parameter(pointer, o.Parameters[name])
+			case "requestBodies":
+				w.requestBody(pointer, o.RequestBodies[name])
+			case "headers":
+				w.header(pointer, o.Headers[name])
+			case "callbacks":
+				w.callback(pointer, o.Callbacks[name])
+			}
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(o.Paths.Paths)) {
+		w.pathItem("/paths/"+escapeJSONPointer(path), o.Paths.Paths[path])
+	}
+}
+
+type schemaWalker struct {
+	fn func(pointer string, s *Schema)
+}
+
+func (w *schemaWalker) schema(pointer string, s *Schema) {
+	if s != nil {
+		w.fn(pointer, s)
+	}
+}
+
+func (w *schemaWalker) pathItem(pointer string, item *PathItem) {
+	if item == nil {
+		return
+	}
+	for i, p := range item.Parameters {
+		w.parameter(pointer+"/parameters/"+strconv.Itoa(i), p)
+	}
+	for _, method := range slices.Sorted(maps.Keys(item.Operations.Operations)) {
+		op := item.Operations.Operations[method]
+		if op == nil {
+			continue
+		}
+		opPointer := pointer + "/" + string(method)
+		for i, p := range op.Parameters {
+			w.parameter(opPointer+"/parameters/"+strconv.Itoa(i), p)
+		}
+		w.requestBody(opPointer+"/requestBody", op.RequestBody)
+		w.response(opPointer+"/responses/default", op.Responses.Default)
+		for _, class := range slices.Sorted(maps.Keys(op.Responses.Ranges)) {
+			w.response(fmt.Sprintf("%s/responses/%dXX", opPointer, class), op.Responses.Ranges[class])
+		}
+		for _, status := range slices.Sorted(maps.Keys(op.Responses.Responses)) {
+			w.response(opPointer+"/responses/"+strconv.Itoa(status), op.Responses.Responses[status])
+		}
+		for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
+			w.callback(opPointer+"/callbacks/"+escapeJSONPointer(name), op.Callbacks[name])
+		}
+	}
+}
+
+func (w *schemaWalker) callback(pointer string, c *Callback) {
+	if c == nil || c.Refer != nil {
+		return
+	}
+	for _, expr := range slices.Sorted(maps.Keys(c.CallbackObject)) {
+		w.pathItem(pointer+"/"+escapeJSONPointer(string(expr)), c.CallbackObject[expr])
+	}
+}
+
+func (w *schemaWalker) parameter(pointer string, p *Parameter) {
+	if p != nil && p.Refer == nil {
+		w.parameterCommon(pointer, &p.ParameterCommonObject)
+	}
+}
+
+func (w *schemaWalker) header(pointer string, h *Header) {
+	if h != nil && h.Refer == nil {
+		w.parameterCommon(pointer, &h.ParameterCommonObject)
+	}
+}
+
+func (w *schemaWalker) parameterCommon(pointer string, o *ParameterCommonObject) {
+	w.schema(pointer+"/schema", o.Schema)
+	w.content(pointer+"/content", o.Content)
+}
+
+func (w *schemaWalker) requestBody(pointer string, rb *RequestBody) {
+	if rb != nil && rb.Refer == nil {
+		w.content(pointer+"/content", rb.Content)
+	}
+}
+
+func (w *schemaWalker) response(pointer string, r *Response) {
+	if r == nil || r.Refer != nil {
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Headers)) {
+		w.header(pointer+"/headers/"+escapeJSONPointer(name), r.Headers[name])
+	}
+	w.content(pointer+"/content", r.Content)
+}
+
+func (w *schemaWalker) content(pointer string, content map[string]*MediaType) {
+	for _, ct := range slices.Sorted(maps.Keys(content)) {
+		mt := content[ct]
+		if mt == nil {
+			continue
+		}
+		mtPointer := pointer + "/" + escapeJSONPointer(ct)
+		w.schema(mtPointer+"/schema", mt.Schema)
+		for _, name := range slices.Sorted(maps.Keys(mt.Encoding)) {
+			if e := mt.Encoding[name]; e != nil {
+				for _, h := range slices.Sorted(maps.Keys(e.Headers)) {
+					w.header(mtPointer+"/encoding/"+escapeJSONPointer(name)+"/headers/"+escapeJSONPointer(h), e.Headers[h])
+				}
+			}
+		}
+	}
+}
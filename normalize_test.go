@@ -0,0 +1,161 @@
+package oas
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/go-courier/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenAllOf(t *testing.T) {
+	components := &ComponentsObject{}
+	components.AddSchema("Base", ObjectOf(Props{
+		"id":   Long(),
+		"name": String().WithValidation(&SchemaValidation{MaxLength: ptr.Uint64(100)}),
+	}, "id"))
+	components.AddSchema("Named", AllOf(
+		components.RefSchema("Base"),
+		ObjectOf(Props{"name": String().WithValidation(&SchemaValidation{MinLength: ptr.Uint64(1), MaxLength: ptr.Uint64(50)})}, "name"),
+	))
+	components.AddSchema("Animal", ObjectOf(Props{"kind": String()}, "kind").WithDiscriminator(&Discriminator{PropertyName: "kind"}))
+
+	t.Run("nested allOf merged", func(t *testing.T) {
+		flattened, err := FlattenAllOf(components, AllOf(
+			components.RefSchema("Named"),
+			ObjectOf(Props{"tag": String()}).WithDesc("tagged"),
+		))
+		assert.NoError(t, err)
+
+		assert.Empty(t, flattened.AllOf)
+		assert.Equal(t, TypeObject, flattened.Type)
+		assert.Equal(t, "tagged", flattened.Description)
+		assert.Equal(t, []string{"id", "name", "tag"}, slices.Sorted(maps.Keys(flattened.Properties)))
+		assert.Equal(t, []string{"id", "name"}, flattened.Required)
+		assert.Equal(t, ptr.Uint64(1), flattened.Properties["name"].MinLength)
+		assert.Equal(t, ptr.Uint64(50), flattened.Properties["name"].MaxLength)
+
+		// components not touched
+		assert.Len(t, components.Schemas["Named"].AllOf, 2)
+		assert.Equal(t, []string{"id"}, components.Schemas["Base"].Required)
+	})
+
+	t.Run("bounds and enums intersected", func(t *testing.T) {
+		flattened, err := FlattenAllOf(components, AllOf(
+			Integer().WithValidation(&SchemaValidation{Minimum: ptr.Float64(0), Maximum: ptr.Float64(100), Enum: []interface{}{1, 2, 3, 50}}),
+			NewSchema(TypeNumber, "").WithValidation(&SchemaValidation{Minimum: ptr.Float64(2), Maximum: ptr.Float64(100), ExclusiveMaximum: true, Enum: []interface{}{2, 3, 4}}),
+		))
+		assert.NoError(t, err)
+
+		assert.Equal(t, TypeInteger, flattened.Type)
+		assert.Equal(t, ptr.Float64(2), flattened.Minimum)
+		assert.Equal(t, ptr.Float64(100), flattened.Maximum)
+		assert.True(t, flattened.ExclusiveMaximum)
+		assert.Equal(t, []interface{}{2, 3}, flattened.Enum)
+	})
+
+	t.Run("keywords could not be merged kept", func(t *testing.T) {
+		flattened, err := FlattenAllOf(components, AllOf(
+			String().WithValidation(&SchemaValidation{Pattern: "^a"}),
+			String().WithValidation(&SchemaValidation{Pattern: "b$"}),
+		))
+		assert.NoError(t, err)
+
+		assert.Equal(t, "^a", flattened.Pattern)
+		assert.Equal(t, []*Schema{{SchemaObject: SchemaObject{SchemaValidation: SchemaValidation{Pattern: "b$"}}}}, flattened.AllOf)
+	})
+
+	t.Run("discriminator ref kept", func(t *testing.T) {
+		flattened, err := FlattenAllOf(components, AllOf(
+			components.RefSchema("Animal"),
+			components.RefSchema("Base"),
+			ObjectOf(Props{"meow": Boolean()}),
+		))
+		assert.NoError(t, err)
+
+		assert.Equal(t, []*Schema{components.RefSchema("Animal")}, flattened.AllOf)
+		assert.Equal(t, []string{"id", "meow", "name"}, slices.Sorted(maps.Keys(flattened.Properties)))
+	})
+
+	t.Run("single member oneOf and anyOf", func(t *testing.T) {
+		flattened, err := FlattenAllOf(components, ObjectOf(Props{
+			"a": OneOf(components.RefSchema("Base")),
+			"b": AnyOf(String(), Integer()),
+		}))
+		assert.NoError(t, err)
+
+		assert.Empty(t, flattened.Properties["a"].OneOf)
+		assert.Equal(t, []string{"id", "name"}, slices.Sorted(maps.Keys(flattened.Properties["a"].Properties)))
+		assert.Len(t, flattened.Properties["b"].AnyOf, 2)
+	})
+
+	t.Run("contradictions", func(t *testing.T) {
+		_, err := FlattenAllOf(components, AllOf(
+			String().WithValidation(&SchemaValidation{MinLength: ptr.Uint64(10), Enum: []interface{}{"a"}}),
+			Integer(),
+			String().WithValidation(&SchemaValidation{MaxLength: ptr.Uint64(5), Enum: []interface{}{"b"}}),
+		))
+
+		conflicts := SchemaConflicts{}
+		assert.True(t, errors.As(err, &conflicts))
+		assert.Equal(t, "/allOf/1/type: type integer conflicts with string\n"+
+			"/allOf/2/enum: no value allowed by enums of all members\n"+
+			": min length 10 exceeds max length 5", err.Error())
+	})
+
+	t.Run("circular allOf kept", func(t *testing.T) {
+		circular := &ComponentsObject{}
+		circular.AddSchema("A", ObjectOf(Props{"a": String()}))
+		circular.AddSchema("B", ObjectOf(Props{"b": String()}))
+		circular.Schemas["A"].AllOf = []*Schema{circular.RefSchema("B")}
+		circular.Schemas["B"].AllOf = []*Schema{circular.RefSchema("A")}
+
+		flattened, err := FlattenAllOf(circular, circular.RefSchema("A"))
+		assert.NoError(t, err)
+		assert.Equal(t, circular.RefSchema("A"), flattened)
+
+		flattened, err = FlattenAllOf(circular, AllOf(circular.RefSchema("A")))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, slices.Sorted(maps.Keys(flattened.Properties)))
+		assert.Equal(t, []*Schema{circular.RefSchema("A")}, flattened.AllOf)
+	})
+}
+
+func TestNormalizer(t *testing.T) {
+	openapi := NewOpenAPI()
+	openapi.AddSchema("Id", Long().WithDesc("id"))
+	openapi.AddSchema("Base", ObjectOf(Props{"id": openapi.RefSchema("Id")}, "id"))
+	openapi.AddSchema("Pet", AllOf(openapi.RefSchema("Base"), ObjectOf(Props{"name": String()})))
+
+	op := NewOperation("createPet")
+	rb := NewRequestBody("", true)
+	rb.AddContent("application/json", NewMediaTypeWithSchema(AllOf(openapi.RefSchema("Pet"), ObjectOf(Props{"tag": String()}))))
+	op.SetRequestBody(rb)
+	op.AddParameter(QueryParameter("id", openapi.RefSchema("Id"), false))
+	op.AddResponse(http.StatusOK, NewResponse("ok"))
+	openapi.AddOperation(POST, "/pets", op)
+
+	normalized, err := (&Normalizer{}).Normalize(openapi)
+	assert.NoError(t, err)
+
+	assert.Empty(t, normalized.Schemas["Pet"].AllOf)
+	assert.Equal(t, []string{"id", "name"}, slices.Sorted(maps.Keys(normalized.Schemas["Pet"].Properties)))
+	assert.Equal(t, openapi.RefSchema("Id"), normalized.Schemas["Pet"].Properties["id"])
+
+	body := normalized.Paths.Paths["/pets"].Operations.Operations[POST].RequestBody.Content["application/json"].Schema
+	assert.Equal(t, []string{"id", "name", "tag"}, slices.Sorted(maps.Keys(body.Properties)))
+
+	// source not touched
+	assert.Len(t, openapi.Schemas["Pet"].AllOf, 2)
+
+	t.Run("inline trivial refs", func(t *testing.T) {
+		normalized, err := (&Normalizer{InlineRefs: true}).Normalize(openapi)
+		assert.NoError(t, err)
+
+		assert.Equal(t, Long().WithDesc("id"), normalized.Schemas["Pet"].Properties["id"])
+		assert.Equal(t, Long().WithDesc("id"), normalized.Paths.Paths["/pets"].Operations.Operations[POST].Parameters[0].Schema)
+	})
+}
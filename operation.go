@@ -12,18 +12,21 @@ type Operation struct {
 }
 
 func (op Operation) WithTags(tags ...string) *Operation {
-	op.Tags = append(op.Tags, tags...)
-	return &op
+	o := op.Clone()
+	o.Tags = append(o.Tags, tags...)
+	return o
 }
 
 func (op Operation) WithSummary(summary string) *Operation {
-	op.Summary = summary
-	return &op
+	o := op.Clone()
+	o.Summary = summary
+	return o
 }
 
 func (op Operation) WithDesc(desc string) *Operation {
-	op.Description = desc
-	return &op
+	o := op.Clone()
+	o.Description = desc
+	return o
 }
 
 func (op Operation) MarshalJSON() ([]byte, error) {
@@ -58,6 +61,10 @@ func (o *OperationObject) AddResponse(statusCode int, r *Response) {
 	o.Responses.AddResponse(statusCode, r)
 }
 
+func (o *OperationObject) AddResponseRange(class int, r *Response) {
+	o.Responses.AddResponseRange(class, r)
+}
+
 func (o *OperationObject) SetDefaultResponse(r *Response) {
 	o.Responses.SetDefaultResponse(r)
 }
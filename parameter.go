@@ -54,8 +54,16 @@ type Parameter struct {
 }
 
 func (p Parameter) WithDesc(desc string) *Parameter {
-	p.Description = desc
-	return &p
+	c := p.Clone()
+	c.Description = desc
+	return c
+}
+
+func (p Parameter) WithStyle(style ParameterStyle, explode bool) *Parameter {
+	c := p.Clone()
+	c.Style = style
+	c.Explode = &explode
+	return c
 }
 
 func (p Parameter) MarshalJSON() ([]byte, error) {
@@ -92,6 +100,30 @@ type Header struct {
 	SpecExtensions
 }
 
+func (h Header) WithDesc(desc string) *Header {
+	c := h.Clone()
+	c.Description = desc
+	return c
+}
+
+func (h Header) WithRequired() *Header {
+	c := h.Clone()
+	c.Required = true
+	return c
+}
+
+func (h Header) WithDeprecated() *Header {
+	c := h.Clone()
+	c.Deprecated = true
+	return c
+}
+
+func (h Header) WithExample(value interface{}) *Header {
+	c := h.Clone()
+	c.Example = AnyValue(deepClone(value))
+	return c
+}
+
 func (h Header) MarshalJSON() ([]byte, error) {
 	return h.MarshalJSONRefFirst(h.ParameterCommonObject, h.SpecExtensions)
 }
@@ -106,6 +138,26 @@ type ParameterObject struct {
 	ParameterCommonObject
 }
 
+// StyleOrDefault returns the style, or the default style of the parameter location when style not set.
+func (o *ParameterObject) StyleOrDefault() ParameterStyle {
+	if o.Style != "" {
+		return o.Style
+	}
+	switch o.In {
+	case PositionQuery, PositionCookie:
+		return ParameterStyleForm
+	}
+	return ParameterStyleSimple
+}
+
+// ExplodeOrDefault returns explode, or true when explode not set and style is form.
+func (o *ParameterObject) ExplodeOrDefault() bool {
+	if o.Explode != nil {
+		return *o.Explode
+	}
+	return o.StyleOrDefault() == ParameterStyleForm
+}
+
 type ParameterCommonObject struct {
 	Description     string `json:"description,omitempty"`
 	Required        bool   `json:"required,omitempty"`
@@ -113,11 +165,11 @@ type ParameterCommonObject struct {
 	AllowEmptyValue bool   `json:"allowEmptyValue,omitempty"`
 
 	Style         ParameterStyle `json:"style,omitempty"`
-	Explode       bool           `json:"explode,omitempty"`
+	Explode       *bool          `json:"explode,omitempty"`
 	AllowReserved bool           `json:"allowReserved,omitempty"`
 
 	WithContentOrSchema
-	Example interface{} `json:"example,omitempty"`
+	Example Any `json:"example,omitzero"`
 	WithExamples
 }
 
@@ -145,6 +197,32 @@ type Example struct {
 	SpecExtensions
 }
 
+func (e Example) WithSummary(summary string) *Example {
+	c := e.Clone()
+	c.Summary = summary
+	return c
+}
+
+func (e Example) WithDesc(desc string) *Example {
+	c := e.Clone()
+	c.Description = desc
+	return c
+}
+
+func (e Example) WithValue(value interface{}) *Example {
+	c := e.Clone()
+	c.Value = AnyValue(deepClone(value))
+	c.ExternalValue = ""
+	return c
+}
+
+func (e Example) WithExternalValue(url string) *Example {
+	c := e.Clone()
+	c.ExternalValue = url
+	c.Value = Any{}
+	return c
+}
+
 func (e Example) MarshalJSON() ([]byte, error) {
 	return e.MarshalJSONRefFirst(e.ExampleObject, e.SpecExtensions)
 }
@@ -154,10 +232,10 @@ func (e *Example) UnmarshalJSON(data []byte) error {
 }
 
 type ExampleObject struct {
-	Summary       string      `json:"summary,omitempty"`
-	Description   string      `json:"description,omitempty"`
-	Value         interface{} `json:"value,omitempty"`
-	ExternalValue string      `json:"externalValue,omitempty"`
+	Summary       string `json:"summary,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Value         Any    `json:"value,omitzero"`
+	ExternalValue string `json:"externalValue,omitempty"`
 }
 
 func NewRequestBody(desc string, required bool) *RequestBody {
@@ -175,6 +253,23 @@ type RequestBody struct {
 	SpecExtensions
 }
 
+func (r RequestBody) WithDesc(desc string) *RequestBody {
+	c := r.Clone()
+	c.Description = desc
+	return c
+}
+
+func (r RequestBody) WithContentOf(contentType string, mt *MediaType) *RequestBody {
+	c := r.Clone()
+	c.AddContent(contentType, mt.Clone())
+	return c
+}
+
+// WithSchema adds content of the content type with the schema
+func (r RequestBody) WithSchema(contentType string, s *Schema) *RequestBody {
+	return r.WithContentOf(contentType, NewMediaTypeWithSchema(s))
+}
+
 func (r RequestBody) MarshalJSON() ([]byte, error) {
 	return r.MarshalJSONRefFirst(r.RequestBodyObject, r.SpecExtensions)
 }
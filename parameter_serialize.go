@@ -0,0 +1,207 @@
+package oas
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Serialize serializes value by style and explode of the parameter.
+// https://swagger.io/specification/#style-examples
+//
+// returns the path segment for path parameter, escaped query string pairs joined with & for query parameter,
+// and the value for header parameter and cookie parameter.
+func (o *ParameterObject) Serialize(value interface{}) string {
+	style := o.StyleOrDefault()
+	explode := o.ExplodeOrDefault()
+
+	escape := func(s string) string {
+		return s
+	}
+
+	switch o.In {
+	case PositionPath:
+		escape = url.PathEscape
+	case PositionQuery:
+		escape = url.QueryEscape
+		if o.AllowReserved {
+			escape = escapeQueryAllowReserved
+		}
+	}
+
+	name := escape(o.Name)
+	if o.In == PositionCookie {
+		// name of cookie is written by the cookie
+		name = ""
+	}
+
+	scalar, list, pairs := flattenParameterValue(value)
+
+	for i := range list {
+		list[i] = escape(list[i])
+	}
+	for i := range pairs {
+		pairs[i][0] = escape(pairs[i][0])
+		pairs[i][1] = escape(pairs[i][1])
+	}
+
+	joinPairs := func(kvSep string, sep string) string {
+		parts := make([]string, len(pairs))
+		for i, kv := range pairs {
+			parts[i] = kv[0] + kvSep + kv[1]
+		}
+		return strings.Join(parts, sep)
+	}
+
+	named := func(v string) string {
+		if name == "" {
+			return v
+		}
+		return name + "=" + v
+	}
+
+	switch style {
+	case ParameterStyleMatrix:
+		switch {
+		case list != nil && explode:
+			return ";" + name + "=" + strings.Join(list, ";"+name+"=")
+		case list != nil:
+			return ";" + name + "=" + strings.Join(list, ",")
+		case pairs != nil && explode:
+			return ";" + joinPairs("=", ";")
+		case pairs != nil:
+			return ";" + name + "=" + joinPairs(",", ",")
+		}
+		return ";" + name + "=" + escape(scalar)
+	case ParameterStyleLabel:
+		switch {
+		case list != nil && explode:
+			return "." + strings.Join(list, ".")
+		case list != nil:
+			return "." + strings.Join(list, ",")
+		case pairs != nil && explode:
+			return "." + joinPairs("=", ".")
+		case pairs != nil:
+			return "." + joinPairs(",", ",")
+		}
+		return "." + escape(scalar)
+	case ParameterStyleForm:
+		switch {
+		case list != nil && explode:
+			if name == "" {
+				return strings.Join(list, ",")
+			}
+			parts := make([]string, len(list))
+			for i := range list {
+				parts[i] = named(list[i])
+			}
+			return strings.Join(parts, "&")
+		case list != nil:
+			return named(strings.Join(list, ","))
+		case pairs != nil && explode:
+			return joinPairs("=", "&")
+		case pairs != nil:
+			return named(joinPairs(",", ","))
+		}
+		return named(escape(scalar))
+	case ParameterStyleSpaceDelimited, ParameterStylePipeDelimited:
+		sep := "|"
+		if style == ParameterStyleSpaceDelimited {
+			sep = escape(" ")
+		}
+		switch {
+		case list != nil:
+			return named(strings.Join(list, sep))
+		case pairs != nil:
+			return named(joinPairs(sep, sep))
+		}
+		return named(escape(scalar))
+	case ParameterStyleDeepObject:
+		if pairs != nil {
+			parts := make([]string, len(pairs))
+			for i, kv := range pairs {
+				parts[i] = name + "[" + kv[0] + "]=" + kv[1]
+			}
+			return strings.Join(parts, "&")
+		}
+		return named(escape(scalar))
+	}
+
+	// simple
+	switch {
+	case list != nil:
+		return strings.Join(list, ",")
+	case pairs != nil && explode:
+		return joinPairs("=", ",")
+	case pairs != nil:
+		return joinPairs(",", ",")
+	}
+	return escape(scalar)
+}
+
+// flattenParameterValue returns scalar string, or items of array, or key value pairs sorted by key of object
+func flattenParameterValue(value interface{}) (string, []string, [][2]string) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return "", nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return "", nil, nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		list := make([]string, rv.Len())
+		for i := range list {
+			list[i] = scalarString(rv.Index(i))
+		}
+		return "", list, nil
+	case reflect.Map:
+		pairs := make([][2]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			pairs = append(pairs, [2]string{scalarString(k), scalarString(rv.MapIndex(k))})
+		}
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i][0] < pairs[j][0]
+		})
+		return "", nil, pairs
+	}
+
+	return scalarString(rv), nil, nil
+}
+
+func scalarString(rv reflect.Value) string {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+		return string(rv.Bytes())
+	}
+	return stringifyValue(rv.Interface())
+}
+
+func escapeQueryAllowReserved(s string) string {
+	b := strings.Builder{}
+	for _, c := range []byte(s) {
+		if isUnreservedChar(c) || strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%%%02X", c))
+	}
+	return b.String()
+}
+
+func isUnreservedChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
@@ -0,0 +1,63 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterSerialize(t *testing.T) {
+	primitive := 5
+	array := []int{3, 4, 5}
+	object := map[string]string{"role": "admin", "firstName": "Alex"}
+
+	param := func(in Position, style ParameterStyle, explode bool) *Parameter {
+		p := &Parameter{}
+		p.Name = "id"
+		p.In = in
+		return p.WithStyle(style, explode)
+	}
+
+	cases := []struct {
+		param  *Parameter
+		value  interface{}
+		expect string
+	}{
+		{param(PositionPath, ParameterStyleMatrix, false), primitive, ";id=5"},
+		{param(PositionPath, ParameterStyleMatrix, true), array, ";id=3;id=4;id=5"},
+		{param(PositionPath, ParameterStyleMatrix, false), array, ";id=3,4,5"},
+		{param(PositionPath, ParameterStyleMatrix, false), object, ";id=firstName,Alex,role,admin"},
+		{param(PositionPath, ParameterStyleMatrix, true), object, ";firstName=Alex;role=admin"},
+		{param(PositionPath, ParameterStyleLabel, false), primitive, ".5"},
+		{param(PositionPath, ParameterStyleLabel, false), array, ".3,4,5"},
+		{param(PositionPath, ParameterStyleLabel, true), array, ".3.4.5"},
+		{param(PositionPath, ParameterStyleLabel, true), object, ".firstName=Alex.role=admin"},
+		{param(PositionPath, ParameterStyleSimple, false), array, "3,4,5"},
+		{param(PositionPath, ParameterStyleSimple, false), object, "firstName,Alex,role,admin"},
+		{param(PositionPath, ParameterStyleSimple, true), object, "firstName=Alex,role=admin"},
+		{param(PositionPath, ParameterStyleSimple, false), "a/b", "a%2Fb"},
+		{param(PositionQuery, ParameterStyleForm, false), primitive, "id=5"},
+		{param(PositionQuery, ParameterStyleForm, true), array, "id=3&id=4&id=5"},
+		{param(PositionQuery, ParameterStyleForm, false), array, "id=3,4,5"},
+		{param(PositionQuery, ParameterStyleForm, false), object, "id=firstName,Alex,role,admin"},
+		{param(PositionQuery, ParameterStyleForm, true), object, "firstName=Alex&role=admin"},
+		{param(PositionQuery, ParameterStyleSpaceDelimited, false), array, "id=3+4+5"},
+		{param(PositionQuery, ParameterStylePipeDelimited, false), array, "id=3|4|5"},
+		{param(PositionQuery, ParameterStyleDeepObject, true), object, "id[firstName]=Alex&id[role]=admin"},
+		{param(PositionQuery, ParameterStyleForm, true), "a b&c", "id=a+b%26c"},
+		{param(PositionHeader, ParameterStyleSimple, false), array, "3,4,5"},
+		{param(PositionCookie, ParameterStyleForm, false), array, "3,4,5"},
+		{QueryParameter("id", String(), false), 1.5, "id=1.5"},
+		{PathParameter("id", String()), "1", "1"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expect, c.param.Serialize(c.value), "%s %s %v", c.param.In, c.param.Style, c.value)
+	}
+
+	t.Run("allow reserved", func(t *testing.T) {
+		p := QueryParameter("url", String(), false)
+		p.AllowReserved = true
+		assert.Equal(t, "url=http://a.com/b?c=d%20e", p.Serialize("http://a.com/b?c=d e"))
+	})
+}
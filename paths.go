@@ -2,6 +2,11 @@ package oas
 
 import (
 	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
 )
 
 type Paths struct {
@@ -54,6 +59,17 @@ const (
 	TRACE   HttpMethod = "trace"
 )
 
+var httpMethods = map[HttpMethod]bool{
+	GET:     true,
+	PUT:     true,
+	POST:    true,
+	DELETE:  true,
+	OPTIONS: true,
+	HEAD:    true,
+	PATCH:   true,
+	TRACE:   true,
+}
+
 type Operations struct {
 	Operations map[HttpMethod]*Operation
 }
@@ -72,8 +88,25 @@ func (v Operations) MarshalJSON() ([]byte, error) {
 	return json.Marshal(v.Operations)
 }
 
+// UnmarshalJSON decodes keys of http methods only,
+// other keys of the path item, like parameters or unknown fields, are left to PathItemObject or ignored.
+// UnmarshalStrict reports unknown keys.
 func (v *Operations) UnmarshalJSON(data []byte) error {
-	return json.Unmarshal(data, &v.Operations)
+	values := make(map[HttpMethod]json.RawMessage)
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	for method := range values {
+		if !httpMethods[method] {
+			continue
+		}
+		op := &Operation{}
+		if err := json.Unmarshal(values[method], op); err != nil {
+			return err
+		}
+		v.AddOperation(method, op)
+	}
+	return nil
 }
 
 type PathItemObject struct {
@@ -83,3 +116,45 @@ type PathItemObject struct {
 	Servers    []*Server    `json:"servers,omitempty"`
 	Parameters []*Parameter `json:"parameters,omitempty"`
 }
+
+// FindOperationByID returns method, path and the operation of the operationId
+func (p Paths) FindOperationByID(operationId string) (HttpMethod, string, *Operation) {
+	for _, path := range slices.Sorted(maps.Keys(p.Paths)) {
+		item := p.Paths[path]
+		if item == nil {
+			continue
+		}
+		for method, op := range item.Operations.Operations {
+			if op != nil && op.OperationId == operationId {
+				return method, path, op
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// ResolveOperationRef resolves local operation ref like `#/paths/~1users~1{userId}/get`
+func (p Paths) ResolveOperationRef(ref string) (HttpMethod, string, *Operation, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return "", "", nil, fmt.Errorf("operation ref %q is not supported, only local ref supported", ref)
+	}
+	// fragments of uri are percent-encoded, like #/paths/~1pets~1%7Bid%7D/get
+	pointer, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		return "", "", nil, fmt.Errorf("operation ref %q: %w", ref, err)
+	}
+	tokens, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if len(tokens) != 3 || tokens[0] != "paths" {
+		return "", "", nil, fmt.Errorf("operation ref %q should be like #/paths/{path}/{method}", ref)
+	}
+	path, method := tokens[1], HttpMethod(tokens[2])
+	if item := p.Paths[path]; item != nil {
+		if op := item.Operations.Operations[method]; op != nil {
+			return method, path, op, nil
+		}
+	}
+	return "", "", nil, fmt.Errorf("operation ref %q not found", ref)
+}
@@ -1,8 +1,11 @@
 package oas
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPaths(t *testing.T) {
@@ -45,3 +48,55 @@ func TestPaths(t *testing.T) {
 
 	g.Run(t)
 }
+
+func TestFindOperation(t *testing.T) {
+	paths := Paths{}
+	paths.AddOperation(GET, "/users/{userId}", NewOperation("getUser"))
+
+	method, path, op := paths.FindOperationByID("getUser")
+	assert.Equal(t, GET, method)
+	assert.Equal(t, "/users/{userId}", path)
+	assert.Equal(t, "getUser", op.OperationId)
+
+	_, _, op = paths.FindOperationByID("unknown")
+	assert.Nil(t, op)
+
+	_, _, op, err := paths.ResolveOperationRef("#/paths/~1users~1{userId}/get")
+	assert.NoError(t, err)
+	assert.Equal(t, "getUser", op.OperationId)
+
+	_, path, op, err = paths.ResolveOperationRef("#/paths/~1users~1%7BuserId%7D/get")
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/{userId}", path)
+	assert.Equal(t, "getUser", op.OperationId)
+
+	_, _, _, err = paths.ResolveOperationRef("#/paths/~1users~1%7BuserId/get%")
+	assert.Error(t, err)
+
+	_, _, _, err = paths.ResolveOperationRef("#/paths/~1users~1{userId}/post")
+	assert.Error(t, err)
+
+	_, _, _, err = paths.ResolveOperationRef("https://example.com/openapi.json#/paths/~1users/get")
+	assert.Error(t, err)
+}
+
+func TestPathItemUnmarshal(t *testing.T) {
+	item := &PathItem{}
+	err := json.Unmarshal([]byte(`{
+  "summary": "pets",
+  "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+  "get": {"operationId": "getPet", "responses": {}},
+  "fetch": {"operationId": "fetchPet"},
+  "x-owner": "pets"
+}`), item)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "pets", item.Summary)
+	assert.Len(t, item.Parameters, 1)
+	assert.Equal(t, "id", item.Parameters[0].Name)
+	assert.Equal(t, "pets", item.Extensions["x-owner"])
+
+	// keys not of http methods dropped
+	assert.Len(t, item.Operations.Operations, 1)
+	assert.Equal(t, "getPet", item.Operations.Operations[GET].OperationId)
+}
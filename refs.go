@@ -0,0 +1,296 @@
+package oas
+
+import (
+	"maps"
+	"slices"
+)
+
+// refVisitor is called with each reference and the components group it points to.
+// refs are not followed, the refer could be replaced by the visitor.
+type refVisitor func(group string, ref *Reference)
+
+func (v refVisitor) openapi(o *OpenAPI) {
+	for _, path := range slices.Sorted(maps.Keys(o.Paths.Paths)) {
+		v.pathItem(o.Paths.Paths[path])
+	}
+	v.components(&o.ComponentsObject)
+}
+
+func (v refVisitor) components(c *ComponentsObject) {
+	for _, group := range componentGroups {
+		for _, name := range c.componentNames(group) {
+			v.component(c, group, name)
+		}
+	}
+}
+
+// component visits the component itself, so refs of components to other components are visited too
+func (v refVisitor) component(c *ComponentsObject, group string, name string) {
+	switch group {
+	case "schemas":
+		v.schema(c.Schemas[name])
+	case "responses":
+		v.response(c.Responses[name])
+	case "parameters":
+		v.parameter(c.Parameters[name])
+	case "examples":
+		v.example(c.Examples[name])
+	case "requestBodies":
+		v.requestBody(c.RequestBodies[name])
+	case "headers":
+		v.header(c.Headers[name])
+	case "links":
+		v.link(c.Links[name])
+	case "callbacks":
+		v.callback(c.Callbacks[name])
+	}
+}
+
+func (v refVisitor) ref(group string, ref *Reference) bool {
+	if ref.Refer == nil {
+		return false
+	}
+	v(group, ref)
+	return true
+}
+
+func (v refVisitor) pathItem(i *PathItem) {
+	if i == nil {
+		return
+	}
+	for _, p := range i.Parameters {
+		v.parameter(p)
+	}
+	for _, method := range slices.Sorted(maps.Keys(i.Operations.Operations)) {
+		v.operation(i.Operations.Operations[method])
+	}
+}
+
+func (v refVisitor) operation(op *Operation) {
+	if op == nil {
+		return
+	}
+	for _, p := range op.Parameters {
+		v.parameter(p)
+	}
+	v.requestBody(op.RequestBody)
+	v.responses(&op.Responses.ResponsesObject)
+	for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
+		v.callback(op.Callbacks[name])
+	}
+}
+
+func (v refVisitor) responses(o *ResponsesObject) {
+	v.response(o.Default)
+	for _, class := range slices.Sorted(maps.Keys(o.Ranges)) {
+		v.response(o.Ranges[class])
+	}
+	for _, status := range slices.Sorted(maps.Keys(o.Responses)) {
+		v.response(o.Responses[status])
+	}
+}
+
+func (v refVisitor) response(r *Response) {
+	if r == nil || v.ref("responses", &r.Reference) {
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Headers)) {
+		v.header(r.Headers[name])
+	}
+	v.content(r.Content)
+	for _, name := range slices.Sorted(maps.Keys(r.Links)) {
+		v.link(r.Links[name])
+	}
+}
+
+func (v refVisitor) parameter(p *Parameter) {
+	if p == nil || v.ref("parameters", &p.Reference) {
+		return
+	}
+	v.parameterCommon(&p.ParameterCommonObject)
+}
+
+func (v refVisitor) header(h *Header) {
+	if h == nil || v.ref("headers", &h.Reference) {
+		return
+	}
+	v.parameterCommon(&h.ParameterCommonObject)
+}
+
+func (v refVisitor) parameterCommon(o *ParameterCommonObject) {
+	v.schema(o.Schema)
+	v.content(o.Content)
+	for _, name := range slices.Sorted(maps.Keys(o.Examples)) {
+		v.example(o.Examples[name])
+	}
+}
+
+func (v refVisitor) requestBody(rb *RequestBody) {
+	if rb == nil || v.ref("requestBodies", &rb.Reference) {
+		return
+	}
+	v.content(rb.Content)
+}
+
+func (v refVisitor) content(content map[string]*MediaType) {
+	for _, ct := range slices.Sorted(maps.Keys(content)) {
+		mt := content[ct]
+		if mt == nil {
+			continue
+		}
+		v.schema(mt.Schema)
+		for _, name := range slices.Sorted(maps.Keys(mt.Examples)) {
+			v.example(mt.Examples[name])
+		}
+		for _, name := range slices.Sorted(maps.Keys(mt.Encoding)) {
+			if e := mt.Encoding[name]; e != nil {
+				for _, h := range slices.Sorted(maps.Keys(e.Headers)) {
+					v.header(e.Headers[h])
+				}
+			}
+		}
+	}
+}
+
+func (v refVisitor) example(e *Example) {
+	if e != nil {
+		v.ref("examples", &e.Reference)
+	}
+}
+
+func (v refVisitor) link(l *Link) {
+	if l != nil {
+		v.ref("links", &l.Reference)
+	}
+}
+
+func (v refVisitor) callback(c *Callback) {
+	if c == nil || v.ref("callbacks", &c.Reference) {
+		return
+	}
+	for _, expr := range slices.Sorted(maps.Keys(c.CallbackObject)) {
+		v.pathItem(c.CallbackObject[expr])
+	}
+}
+
+func (v refVisitor) schema(s *Schema) {
+	if s == nil || v.ref("schemas", &s.Reference) {
+		return
+	}
+	v.schema(s.Items)
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		v.schema(s.Properties[name])
+	}
+	if s.AdditionalProperties != nil {
+		v.schema(s.AdditionalProperties.Schema)
+	}
+	v.schema(s.PropertyNames)
+	for _, list := range [][]*Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, sub := range list {
+			v.schema(sub)
+		}
+	}
+	v.schema(s.Not)
+	if s.Discriminator != nil {
+		for _, value := range slices.Sorted(maps.Keys(s.Discriminator.Mapping)) {
+			if r := ParseComponentRefer(s.Discriminator.Mapping[value]); r != nil {
+				ref := &Reference{Refer: r}
+				v("schemas", ref)
+				s.Discriminator.Mapping[value] = ref.Refer.RefString()
+			}
+		}
+	}
+}
+
+// componentGroups lists groups of components could be referred by $ref
+var componentGroups = []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "links", "callbacks"}
+
+func (object *ComponentsObject) componentNames(group string) []string {
+	switch group {
+	case "schemas":
+		return slices.Sorted(maps.Keys(object.Schemas))
+	case "responses":
+		return slices.Sorted(maps.Keys(object.Responses))
+	case "parameters":
+		return slices.Sorted(maps.Keys(object.Parameters))
+	case "examples":
+		return slices.Sorted(maps.Keys(object.Examples))
+	case "requestBodies":
+		return slices.Sorted(maps.Keys(object.RequestBodies))
+	case "headers":
+		return slices.Sorted(maps.Keys(object.Headers))
+	case "links":
+		return slices.Sorted(maps.Keys(object.Links))
+	case "callbacks":
+		return slices.Sorted(maps.Keys(object.Callbacks))
+	case "securitySchemes":
+		return slices.Sorted(maps.Keys(object.SecuritySchemes))
+	}
+	return nil
+}
+
+// componentValue returns the component, nil when not exists
+func (object *ComponentsObject) componentValue(group string, name string) interface{} {
+	switch group {
+	case "schemas":
+		if v, ok := object.Schemas[name]; ok {
+			return v
+		}
+	case "responses":
+		if v, ok := object.Responses[name]; ok {
+			return v
+		}
+	case "parameters":
+		if v, ok := object.Parameters[name]; ok {
+			return v
+		}
+	case "examples":
+		if v, ok := object.Examples[name]; ok {
+			return v
+		}
+	case "requestBodies":
+		if v, ok := object.RequestBodies[name]; ok {
+			return v
+		}
+	case "headers":
+		if v, ok := object.Headers[name]; ok {
+			return v
+		}
+	case "links":
+		if v, ok := object.Links[name]; ok {
+			return v
+		}
+	case "callbacks":
+		if v, ok := object.Callbacks[name]; ok {
+			return v
+		}
+	case "securitySchemes":
+		if v, ok := object.SecuritySchemes[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (object *ComponentsObject) deleteComponent(group string, name string) {
+	switch group {
+	case "schemas":
+		delete(object.Schemas, name)
+	case "responses":
+		delete(object.Responses, name)
+	case "parameters":
+		delete(object.Parameters, name)
+	case "examples":
+		delete(object.Examples, name)
+	case "requestBodies":
+		delete(object.RequestBodies, name)
+	case "headers":
+		delete(object.Headers, name)
+	case "links":
+		delete(object.Links, name)
+	case "callbacks":
+		delete(object.Callbacks, name)
+	case "securitySchemes":
+		delete(object.SecuritySchemes, name)
+	}
+}
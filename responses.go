@@ -1,9 +1,11 @@
 package oas
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 type Responses struct {
@@ -22,6 +24,8 @@ func (i *Responses) UnmarshalJSON(data []byte) error {
 type ResponsesObject struct {
 	Default   *Response
 	Responses map[int]*Response
+	// Ranges holds responses of status code ranges, keyed by the class digit, 2 for 2XX.
+	Ranges map[int]*Response
 }
 
 func (o *ResponsesObject) SetDefaultResponse(r *Response) {
@@ -41,11 +45,41 @@ func (o *ResponsesObject) AddResponse(statusCode int, r *Response) {
 	o.Responses[statusCode] = r
 }
 
+// AddResponseRange adds response of the status code range, like 4 for 4XX.
+// It panics when the class not in 1..5.
+func (o *ResponsesObject) AddResponseRange(class int, r *Response) {
+	if class < 1 || class > 5 {
+		panic(fmt.Errorf("class of response range should be in 1..5, but got %d", class))
+	}
+	if r == nil {
+		return
+	}
+	if o.Ranges == nil {
+		o.Ranges = make(map[int]*Response)
+	}
+	o.Ranges[class] = r
+}
+
+// ResponseFor resolves the response of the status code,
+// matches exact status code first, then the status code range, then default.
+func (o *ResponsesObject) ResponseFor(statusCode int) *Response {
+	if r, ok := o.Responses[statusCode]; ok && r != nil {
+		return r
+	}
+	if r, ok := o.Ranges[statusCode/100]; ok && r != nil {
+		return r
+	}
+	return o.Default
+}
+
 func (o ResponsesObject) MarshalJSON() ([]byte, error) {
 	responses := make(map[string]*Response)
 	if o.Default != nil {
 		responses["default"] = o.Default
 	}
+	for class := range o.Ranges {
+		responses[fmt.Sprintf("%dXX", class)] = o.Ranges[class]
+	}
 	for status := range o.Responses {
 		responses[fmt.Sprintf("%d", status)] = o.Responses[status]
 	}
@@ -53,24 +87,59 @@ func (o ResponsesObject) MarshalJSON() ([]byte, error) {
 }
 
 func (o *ResponsesObject) UnmarshalJSON(data []byte) error {
-	responses := make(map[string]*Response)
+	responses := make(map[string]json.RawMessage)
 	err := json.Unmarshal(data, &responses)
 	if err != nil {
 		return err
 	}
-	for statusOrDefault := range responses {
-		if statusOrDefault == "default" {
-			o.Default = responses[statusOrDefault]
-		} else if status, err := strconv.ParseInt(statusOrDefault, 10, 10); err == nil {
-			if o.Responses == nil {
-				o.Responses = make(map[int]*Response)
-			}
-			o.Responses[int(status)] = responses[statusOrDefault]
+	for key := range responses {
+		k := parseResponseKey(key)
+		if k == nil {
+			continue
+		}
+		if string(bytes.TrimSpace(responses[key])) == "null" {
+			return fmt.Errorf("response %s should be an object, but got null", key)
+		}
+		r := &Response{}
+		if err := json.Unmarshal(responses[key], r); err != nil {
+			return err
+		}
+		switch {
+		case k.isDefault:
+			o.Default = r
+		case k.class > 0:
+			o.AddResponseRange(k.class, r)
+		default:
+			o.AddResponse(k.statusCode, r)
 		}
 	}
 	return nil
 }
 
+type responseKey struct {
+	isDefault  bool
+	statusCode int
+	class      int
+}
+
+// parseResponseKey parses "default", status code like "200" and status code range like "2XX"
+func parseResponseKey(key string) *responseKey {
+	if key == "default" {
+		return &responseKey{isDefault: true}
+	}
+	if len(key) != 3 || key[0] < '1' || key[0] > '5' {
+		return nil
+	}
+	if strings.ToUpper(key[1:]) == "XX" {
+		return &responseKey{class: int(key[0] - '0')}
+	}
+	statusCode, err := strconv.Atoi(key)
+	if err != nil {
+		return nil
+	}
+	return &responseKey{statusCode: statusCode}
+}
+
 func NewResponse(desc string) *Response {
 	resp := &Response{}
 	resp.Description = desc
@@ -83,6 +152,29 @@ type Response struct {
 	SpecExtensions
 }
 
+func (r Response) WithHeader(name string, h *Header) *Response {
+	c := r.Clone()
+	c.AddHeader(name, h.Clone())
+	return c
+}
+
+func (r Response) WithContentOf(contentType string, mt *MediaType) *Response {
+	c := r.Clone()
+	c.AddContent(contentType, mt.Clone())
+	return c
+}
+
+// WithSchema adds content of the content type with the schema
+func (r Response) WithSchema(contentType string, s *Schema) *Response {
+	return r.WithContentOf(contentType, NewMediaTypeWithSchema(s))
+}
+
+func (r Response) WithLink(name string, l *Link) *Response {
+	c := r.Clone()
+	c.AddLink(name, l.Clone())
+	return c
+}
+
 func (r Response) MarshalJSON() ([]byte, error) {
 	return r.MarshalJSONRefFirst(r.ResponseObject, r.SpecExtensions)
 }
@@ -126,6 +218,30 @@ type Link struct {
 	SpecExtensions
 }
 
+func (l Link) WithDesc(desc string) *Link {
+	c := l.Clone()
+	c.Description = desc
+	return c
+}
+
+func (l Link) WithParameter(name string, expr RuntimeExpression) *Link {
+	c := l.Clone()
+	c.AddParameter(name, expr)
+	return c
+}
+
+func (l Link) WithRequestBody(expr RuntimeExpression) *Link {
+	c := l.Clone()
+	c.SetRequestBody(expr)
+	return c
+}
+
+func (l Link) WithServer(s *Server) *Link {
+	c := l.Clone()
+	c.Server = s.Clone()
+	return c
+}
+
 func (l Link) MarshalJSON() ([]byte, error) {
 	return l.MarshalJSONRefFirst(l.LinkObject, l.SpecExtensions)
 }
@@ -1,7 +1,11 @@
 package oas
 
 import (
+	"encoding/json"
+	"net/http"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestResponse(t *testing.T) {
@@ -24,5 +28,62 @@ func TestResponse(t *testing.T) {
 		}(),
 	)
 
+	g.It(
+		"with builders",
+		`{"description":"desc","headers":{"x-next":{"description":"next","required":true,"schema":{"type":"string"},"example":"abc"}},"content":{"application/json":{"schema":{"type":"string"}},"text/plain":{"schema":{"type":"string"}}},"links":{"GetUserByUserId":{"operationId":"getByUserId","parameters":{"userId":"$response.body#/id"},"requestBody":"$request.body","description":"get user","server":{"url":"/"}}}}`,
+		NewResponse("desc").
+			WithHeader("x-next", NewHeaderWithSchema(String()).WithDesc("next").WithRequired().WithExample("abc")).
+			WithSchema("application/json", String()).
+			WithContentOf("text/plain", NewMediaTypeWithSchema(String())).
+			WithLink("GetUserByUserId", NewLink("getByUserId").
+				WithParameter("userId", "$response.body#/id").
+				WithRequestBody("$request.body").
+				WithDesc("get user").
+				WithServer(NewServer("/")),
+			),
+	)
+
+	g.Run(t)
+}
+
+func TestResponses(t *testing.T) {
+	g := NewCaseGroup("Responses")
+
+	responses := &Responses{}
+	responses.AddResponse(http.StatusOK, NewResponse("ok"))
+	responses.AddResponseRange(4, NewResponse("client error"))
+	responses.AddResponseRange(5, nil)
+	responses.SetDefaultResponse(NewResponse("unexpected error"))
+
+	g.It("with ranges", `{"200":{"description":"ok"},"4XX":{"description":"client error"},"default":{"description":"unexpected error"}}`, responses)
+
 	g.Run(t)
+
+	t.Run("resolve response", func(t *testing.T) {
+		assert.Equal(t, "ok", responses.ResponseFor(http.StatusOK).Description)
+		assert.Equal(t, "client error", responses.ResponseFor(http.StatusNotFound).Description)
+		assert.Equal(t, "unexpected error", responses.ResponseFor(http.StatusCreated).Description)
+		assert.Nil(t, (&Responses{}).ResponseFor(http.StatusOK))
+	})
+
+	t.Run("unmarshal lower case range and skip extensions", func(t *testing.T) {
+		r := &Responses{}
+		assert.NoError(t, UnmarshalStrict([]byte(`{"5xx":{"description":"server error"},"x-a":"a"}`), r))
+		assert.Equal(t, "server error", r.ResponseFor(http.StatusBadGateway).Description)
+		assert.Equal(t, "a", r.Extensions["x-a"])
+	})
+
+	t.Run("null response", func(t *testing.T) {
+		for _, data := range []string{`{"200":null}`, `{"4XX": null}`, `{"default":null}`} {
+			assert.Error(t, json.Unmarshal([]byte(data), &Responses{}), data)
+		}
+	})
+
+	t.Run("range out of classes", func(t *testing.T) {
+		for _, class := range []int{0, 6, 20} {
+			assert.Panics(t, func() {
+				(&Responses{}).AddResponseRange(class, NewResponse("out of classes"))
+			})
+		}
+	})
 }
@@ -0,0 +1,156 @@
+package oas
+
+import (
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MatchPath finds the path template matches the request path,
+// the template with more literal chars wins when multiple templates matched.
+// Templates are compiled on every call, use PathMatcher to match many requests.
+func (p Paths) MatchPath(requestPath string) (string, map[string]string, bool) {
+	return p.Matcher().MatchPath(requestPath)
+}
+
+// FindOperation finds the operation of the http method and the request path.
+func (p Paths) FindOperation(method string, requestPath string) (*Operation, string, map[string]string) {
+	return p.Matcher().FindOperation(method, requestPath)
+}
+
+// Matcher compiles path templates of paths into PathMatcher
+func (p Paths) Matcher() *PathMatcher {
+	m := &PathMatcher{paths: p}
+	for _, tpl := range slices.Sorted(maps.Keys(p.Paths)) {
+		m.templates = append(m.templates, compilePathTemplate(tpl))
+	}
+	return m
+}
+
+// PathMatcher matches request paths with compiled path templates.
+// Segments are compared exactly, so /pets/ or //pets not matches /pets.
+type PathMatcher struct {
+	paths     Paths
+	templates []*pathTemplate
+}
+
+// MatchPath works like Paths.MatchPath
+func (m *PathMatcher) MatchPath(requestPath string) (string, map[string]string, bool) {
+	matched := ""
+	matchedLiterals := -1
+	var matchedParams map[string]string
+
+	parts := strings.Split(requestPath, "/")
+	for _, t := range m.templates {
+		params, ok := t.match(parts)
+		if !ok {
+			continue
+		}
+		// templates sorted, the first one wins when literals equal
+		if t.literals > matchedLiterals {
+			matched, matchedLiterals, matchedParams = t.path, t.literals, params
+		}
+	}
+
+	return matched, matchedParams, matchedLiterals >= 0
+}
+
+// FindOperation works like Paths.FindOperation
+func (m *PathMatcher) FindOperation(method string, requestPath string) (*Operation, string, map[string]string) {
+	tpl, params, ok := m.MatchPath(requestPath)
+	if !ok {
+		return nil, "", nil
+	}
+	item := m.paths.Paths[tpl]
+	if item == nil || item.Operations.Operations == nil {
+		return nil, tpl, params
+	}
+	return item.Operations.Operations[HttpMethod(strings.ToLower(method))], tpl, params
+}
+
+var reParamInPath = regexp.MustCompile(`\{([^}]+)\}`)
+
+func PathParamNames(tpl string) []string {
+	names := make([]string, 0)
+	for _, m := range reParamInPath.FindAllStringSubmatch(tpl, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// pathTemplate is the compiled path template,
+// segments with parameters hold patterns, literal segments hold nil
+type pathTemplate struct {
+	path     string
+	segments []string
+	patterns []*regexp.Regexp
+	names    [][]string
+	literals int
+}
+
+func compilePathTemplate(tpl string) *pathTemplate {
+	t := &pathTemplate{path: tpl, segments: strings.Split(tpl, "/")}
+	t.patterns = make([]*regexp.Regexp, len(t.segments))
+	t.names = make([][]string, len(t.segments))
+
+	for i, seg := range t.segments {
+		if !strings.Contains(seg, "{") {
+			t.literals += len(seg)
+			continue
+		}
+		expr := segmentPattern(seg, func(name string) {
+			t.names[i] = append(t.names[i], name)
+		})
+		t.patterns[i] = regexp.MustCompile(expr)
+		t.literals += len(reParamInPath.ReplaceAllString(seg, ""))
+	}
+	return t
+}
+
+func (t *pathTemplate) match(parts []string) (map[string]string, bool) {
+	if len(t.segments) != len(parts) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, seg := range t.segments {
+		if t.patterns[i] == nil {
+			if seg != parts[i] {
+				return nil, false
+			}
+			continue
+		}
+
+		m := t.patterns[i].FindStringSubmatch(parts[i])
+		if m == nil {
+			return nil, false
+		}
+		for j, name := range t.names[i] {
+			v, err := url.PathUnescape(m[j+1])
+			if err != nil {
+				return nil, false
+			}
+			params[name] = v
+		}
+	}
+	return params, true
+}
+
+func segmentPattern(tplPart string, onParam func(name string)) string {
+	b := strings.Builder{}
+	b.WriteString("^")
+
+	last := 0
+	for _, loc := range reParamInPath.FindAllStringSubmatchIndex(tplPart, -1) {
+		b.WriteString(regexp.QuoteMeta(tplPart[last:loc[0]]))
+		b.WriteString("([^/]+?)")
+		onParam(tplPart[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(tplPart[last:]))
+
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathsMatch(t *testing.T) {
+	paths := Paths{}
+	paths.AddOperation(GET, "/pets", NewOperation("listPets"))
+	paths.AddOperation(GET, "/pets/{petId}", NewOperation("getPet"))
+	paths.AddOperation(GET, "/pets/mine", NewOperation("listMyPets"))
+	paths.AddOperation(GET, "/files/{name}.{ext}", NewOperation("getFile"))
+
+	t.Run("literal wins", func(t *testing.T) {
+		op, tpl, params := paths.FindOperation("GET", "/pets/mine")
+		assert.Equal(t, "listMyPets", op.OperationId)
+		assert.Equal(t, "/pets/mine", tpl)
+		assert.Empty(t, params)
+	})
+
+	t.Run("with params", func(t *testing.T) {
+		op, tpl, params := paths.FindOperation("GET", "/pets/a%20b")
+		assert.Equal(t, "getPet", op.OperationId)
+		assert.Equal(t, "/pets/{petId}", tpl)
+		assert.Equal(t, map[string]string{"petId": "a b"}, params)
+	})
+
+	t.Run("with multiple params in segment", func(t *testing.T) {
+		_, _, params := paths.FindOperation("GET", "/files/readme.md")
+		assert.Equal(t, map[string]string{"name": "readme", "ext": "md"}, params)
+	})
+
+	t.Run("method not defined", func(t *testing.T) {
+		op, tpl, _ := paths.FindOperation("POST", "/pets")
+		assert.Nil(t, op)
+		assert.Equal(t, "/pets", tpl)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, _, ok := paths.MatchPath("/users")
+		assert.False(t, ok)
+	})
+
+	t.Run("segments compared exactly", func(t *testing.T) {
+		for _, path := range []string{"/pets/", "//pets", "pets", "/pets//", "/pets/mine/"} {
+			_, _, ok := paths.MatchPath(path)
+			assert.False(t, ok, path)
+		}
+		_, _, ok := paths.MatchPath("/pets//mine")
+		assert.False(t, ok)
+	})
+
+	t.Run("compiled matcher", func(t *testing.T) {
+		m := paths.Matcher()
+		op, tpl, params := m.FindOperation("GET", "/pets/1")
+		assert.Equal(t, "getPet", op.OperationId)
+		assert.Equal(t, "/pets/{petId}", tpl)
+		assert.Equal(t, map[string]string{"petId": "1"}, params)
+
+		op, _, _ = m.FindOperation("GET", "/pets/mine")
+		assert.Equal(t, "listMyPets", op.OperationId)
+	})
+
+	assert.Equal(t, []string{"name", "ext"}, PathParamNames("/files/{name}.{ext}"))
+}
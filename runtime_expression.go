@@ -0,0 +1,385 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ExpressionSource string
+
+const (
+	ExpressionSourceURL        ExpressionSource = "$url"
+	ExpressionSourceMethod     ExpressionSource = "$method"
+	ExpressionSourceStatusCode ExpressionSource = "$statusCode"
+	ExpressionSourceRequest    ExpressionSource = "$request"
+	ExpressionSourceResponse   ExpressionSource = "$response"
+)
+
+type ExpressionIn string
+
+const (
+	ExpressionInHeader ExpressionIn = "header"
+	ExpressionInQuery  ExpressionIn = "query"
+	ExpressionInPath   ExpressionIn = "path"
+	ExpressionInBody   ExpressionIn = "body"
+)
+
+// Expression is one parsed runtime expression, like `$request.path.id` or `$response.body#/id`
+type Expression struct {
+	Source ExpressionSource
+	// In and Name only for $request and $response
+	In   ExpressionIn
+	Name string
+	// Pointer is the json pointer of body
+	Pointer string
+}
+
+func (e *Expression) String() string {
+	switch e.Source {
+	case ExpressionSourceRequest, ExpressionSourceResponse:
+		if e.In == ExpressionInBody {
+			if e.Pointer != "" {
+				return fmt.Sprintf("%s.body#%s", e.Source, e.Pointer)
+			}
+			return fmt.Sprintf("%s.body", e.Source)
+		}
+		return fmt.Sprintf("%s.%s.%s", e.Source, e.In, e.Name)
+	}
+	return string(e.Source)
+}
+
+type ExpressionSyntaxError struct {
+	Expr   string
+	Offset int
+	Msg    string
+}
+
+func (e *ExpressionSyntaxError) Error() string {
+	return fmt.Sprintf("invalid runtime expression %q at %d: %s", e.Expr, e.Offset, e.Msg)
+}
+
+func ParseExpression(expr string) (*Expression, error) {
+	return parseExpression(expr, expr, 0)
+}
+
+func parseExpression(full string, expr string, offset int) (*Expression, error) {
+	syntaxErr := func(at int, format string, args ...interface{}) error {
+		return &ExpressionSyntaxError{Expr: full, Offset: offset + at, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	switch ExpressionSource(expr) {
+	case ExpressionSourceURL, ExpressionSourceMethod, ExpressionSourceStatusCode:
+		return &Expression{Source: ExpressionSource(expr)}, nil
+	}
+
+	e := &Expression{}
+	rest := ""
+
+	switch {
+	case strings.HasPrefix(expr, string(ExpressionSourceRequest)+"."):
+		e.Source = ExpressionSourceRequest
+		rest = expr[len(ExpressionSourceRequest)+1:]
+	case strings.HasPrefix(expr, string(ExpressionSourceResponse)+"."):
+		e.Source = ExpressionSourceResponse
+		rest = expr[len(ExpressionSourceResponse)+1:]
+	default:
+		return nil, syntaxErr(0, "should be one of $url, $method, $statusCode, $request.{source}, $response.{source}")
+	}
+
+	at := len(expr) - len(rest)
+
+	if rest == string(ExpressionInBody) || strings.HasPrefix(rest, string(ExpressionInBody)+"#") {
+		e.In = ExpressionInBody
+		if len(rest) > len(ExpressionInBody) {
+			e.Pointer = rest[len(ExpressionInBody)+1:]
+			if _, err := ParseJSONPointer(e.Pointer); err != nil {
+				return nil, syntaxErr(at+len(ExpressionInBody)+1, "%s", err)
+			}
+		}
+		return e, nil
+	}
+
+	parts := strings.SplitN(rest, ".", 2)
+	switch in := ExpressionIn(parts[0]); in {
+	case ExpressionInHeader, ExpressionInQuery, ExpressionInPath:
+		e.In = in
+	default:
+		return nil, syntaxErr(at, "source should be one of header.{token}, query.{name}, path.{name}, body#{json-pointer}")
+	}
+
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, syntaxErr(len(expr), "missing name of %s", e.In)
+	}
+
+	e.Name = parts[1]
+
+	if e.In == ExpressionInHeader {
+		for i, c := range e.Name {
+			if !isTokenChar(c) {
+				return nil, syntaxErr(at+len(e.In)+1+i, "invalid char %q in header name", c)
+			}
+		}
+	}
+
+	return e, nil
+}
+
+// https://tools.ietf.org/html/rfc7230#section-3.2.6
+func isTokenChar(c rune) bool {
+	if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", c)
+}
+
+// ExpressionTemplate is parsed RuntimeExpression,
+// which is a single expression, or literals with embedded expressions like `http://example.com?id={$request.path.id}`.
+type ExpressionTemplate struct {
+	Parts []ExpressionPart
+}
+
+// ExpressionPart is a literal, or an expression when Expr not nil
+type ExpressionPart struct {
+	Literal string
+	Expr    *Expression
+}
+
+func (t *ExpressionTemplate) String() string {
+	b := strings.Builder{}
+	if t.IsExpression() {
+		return t.Parts[0].Expr.String()
+	}
+	for _, p := range t.Parts {
+		if p.Expr != nil {
+			b.WriteString("{" + p.Expr.String() + "}")
+			continue
+		}
+		b.WriteString(p.Literal)
+	}
+	return b.String()
+}
+
+// IsExpression returns true when the template is a single bare expression
+func (t *ExpressionTemplate) IsExpression() bool {
+	return len(t.Parts) == 1 && t.Parts[0].Expr != nil && t.Parts[0].Literal == ""
+}
+
+func (e RuntimeExpression) Parse() (*ExpressionTemplate, error) {
+	s := string(e)
+
+	if strings.HasPrefix(s, "$") {
+		expr, err := ParseExpression(s)
+		if err != nil {
+			return nil, err
+		}
+		return &ExpressionTemplate{Parts: []ExpressionPart{{Expr: expr}}}, nil
+	}
+
+	t := &ExpressionTemplate{}
+	literal := strings.Builder{}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '{' && i+1 < len(s) && s[i+1] == '$' {
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return nil, &ExpressionSyntaxError{Expr: s, Offset: i, Msg: "missing }"}
+			}
+			expr, err := parseExpression(s, s[i+1:i+end], i+1)
+			if err != nil {
+				return nil, err
+			}
+			if literal.Len() > 0 {
+				t.Parts = append(t.Parts, ExpressionPart{Literal: literal.String()})
+				literal.Reset()
+			}
+			t.Parts = append(t.Parts, ExpressionPart{Expr: expr})
+			i += end
+			continue
+		}
+		literal.WriteByte(s[i])
+	}
+
+	if literal.Len() > 0 || len(t.Parts) == 0 {
+		t.Parts = append(t.Parts, ExpressionPart{Literal: literal.String()})
+	}
+
+	return t, nil
+}
+
+// Evaluate evaluates the expression by the exchange,
+// a single expression returns the value as is, for example a number from body; others return the expanded string.
+func (e RuntimeExpression) Evaluate(x *Exchange) (interface{}, error) {
+	t, err := e.Parse()
+	if err != nil {
+		return nil, err
+	}
+	return t.Evaluate(x)
+}
+
+func (t *ExpressionTemplate) Evaluate(x *Exchange) (interface{}, error) {
+	if t.IsExpression() {
+		return x.Evaluate(t.Parts[0].Expr)
+	}
+
+	b := strings.Builder{}
+	for _, p := range t.Parts {
+		if p.Expr == nil {
+			b.WriteString(p.Literal)
+			continue
+		}
+		v, err := x.Evaluate(p.Expr)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(stringifyValue(v))
+	}
+	return b.String(), nil
+}
+
+func stringifyValue(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(x)
+		return string(data)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+var ErrExpressionValueNotFound = errors.New("value not found")
+
+// Exchange is a captured http request and response pair, for evaluating runtime expressions
+type Exchange struct {
+	Request     *http.Request
+	RequestBody []byte
+	// PathParams of request, matched by path template of the operation
+	PathParams   map[string]string
+	Response     *http.Response
+	ResponseBody []byte
+}
+
+// CaptureExchange reads bodies of request and response, and resets them to be read again
+func CaptureExchange(req *http.Request, resp *http.Response) (*Exchange, error) {
+	x := &Exchange{Request: req, Response: resp}
+
+	if req != nil && req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		x.RequestBody = data
+	}
+
+	if resp != nil && resp.Body != nil {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		x.ResponseBody = data
+	}
+
+	return x, nil
+}
+
+func (x *Exchange) Evaluate(e *Expression) (interface{}, error) {
+	notFound := func() error {
+		return fmt.Errorf("%s: %w", e, ErrExpressionValueNotFound)
+	}
+
+	switch e.Source {
+	case ExpressionSourceURL:
+		if x.Request == nil {
+			return nil, notFound()
+		}
+		return requestURL(x.Request), nil
+	case ExpressionSourceMethod:
+		if x.Request == nil {
+			return nil, notFound()
+		}
+		return strings.ToUpper(x.Request.Method), nil
+	case ExpressionSourceStatusCode:
+		if x.Response == nil {
+			return nil, notFound()
+		}
+		return x.Response.StatusCode, nil
+	}
+
+	var header http.Header
+	var body []byte
+
+	if e.Source == ExpressionSourceRequest {
+		if x.Request == nil {
+			return nil, notFound()
+		}
+		header, body = x.Request.Header, x.RequestBody
+	} else {
+		if x.Response == nil {
+			return nil, notFound()
+		}
+		header, body = x.Response.Header, x.ResponseBody
+	}
+
+	switch e.In {
+	case ExpressionInHeader:
+		if values, ok := header[http.CanonicalHeaderKey(e.Name)]; ok && len(values) > 0 {
+			return values[0], nil
+		}
+	case ExpressionInQuery:
+		if e.Source == ExpressionSourceRequest {
+			if values, ok := x.Request.URL.Query()[e.Name]; ok && len(values) > 0 {
+				return values[0], nil
+			}
+		}
+	case ExpressionInPath:
+		if e.Source == ExpressionSourceRequest {
+			if v, ok := x.PathParams[e.Name]; ok {
+				return v, nil
+			}
+		}
+	case ExpressionInBody:
+		if len(body) == 0 {
+			return nil, notFound()
+		}
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			if e.Pointer == "" {
+				return string(body), nil
+			}
+			return nil, fmt.Errorf("%s: %w", e, err)
+		}
+		resolved, err := ResolveJSONPointer(v, e.Pointer)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", e, err, ErrExpressionValueNotFound)
+		}
+		return resolved, nil
+	}
+
+	return nil, notFound()
+}
+
+func requestURL(r *http.Request) string {
+	if r.URL.IsAbs() {
+		return r.URL.String()
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	u := *r.URL
+	u.Scheme = scheme
+	u.Host = r.Host
+	return u.String()
+}
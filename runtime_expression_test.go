@@ -0,0 +1,116 @@
+package oas
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpression(t *testing.T) {
+	cases := map[string]*Expression{
+		"$url":                      {Source: ExpressionSourceURL},
+		"$method":                   {Source: ExpressionSourceMethod},
+		"$statusCode":               {Source: ExpressionSourceStatusCode},
+		"$request.path.id":          {Source: ExpressionSourceRequest, In: ExpressionInPath, Name: "id"},
+		"$request.query.queryUrl":   {Source: ExpressionSourceRequest, In: ExpressionInQuery, Name: "queryUrl"},
+		"$request.header.X-Token":   {Source: ExpressionSourceRequest, In: ExpressionInHeader, Name: "X-Token"},
+		"$request.body":             {Source: ExpressionSourceRequest, In: ExpressionInBody},
+		"$response.body#/users/0":   {Source: ExpressionSourceResponse, In: ExpressionInBody, Pointer: "/users/0"},
+		"$response.header.Location": {Source: ExpressionSourceResponse, In: ExpressionInHeader, Name: "Location"},
+	}
+
+	for s, expect := range cases {
+		e, err := ParseExpression(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expect, e, s)
+		assert.Equal(t, s, e.String())
+	}
+
+	invalids := map[string]int{
+		"$uri":                    0,
+		"$request.cookie.id":      9,
+		"$request.path":           13,
+		"$request.header.X Token": 17,
+		"$response.body#users":    15,
+	}
+
+	for s, offset := range invalids {
+		_, err := ParseExpression(s)
+		syntaxErr, ok := err.(*ExpressionSyntaxError)
+		if assert.True(t, ok, s) {
+			assert.Equal(t, offset, syntaxErr.Offset, s)
+		}
+	}
+}
+
+func TestRuntimeExpressionParse(t *testing.T) {
+	t.Run("template", func(t *testing.T) {
+		tpl, err := RuntimeExpression("http://notificationServer.com?transactionId={$request.body#/id}&email={$request.body#/email}").Parse()
+		assert.NoError(t, err)
+		assert.Len(t, tpl.Parts, 4)
+		assert.False(t, tpl.IsExpression())
+		assert.Equal(t, "http://notificationServer.com?transactionId={$request.body#/id}&email={$request.body#/email}", tpl.String())
+	})
+
+	t.Run("constant", func(t *testing.T) {
+		tpl, err := RuntimeExpression(`{"a":1}`).Parse()
+		assert.NoError(t, err)
+		assert.Equal(t, []ExpressionPart{{Literal: `{"a":1}`}}, tpl.Parts)
+	})
+
+	t.Run("invalid embedded", func(t *testing.T) {
+		_, err := RuntimeExpression("http://x.com/{$request.query.id").Parse()
+		assert.EqualError(t, err, `invalid runtime expression "http://x.com/{$request.query.id" at 13: missing }`)
+
+		_, err = RuntimeExpression("http://x.com/{$request.form.id}").Parse()
+		assert.Equal(t, 23, err.(*ExpressionSyntaxError).Offset)
+	})
+}
+
+func TestRuntimeExpressionEvaluate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/users/1?queryUrl=http://callback.com", strings.NewReader(`{"id":"x1","email":"a@b.com"}`))
+	req.Header.Set("X-Token", "token")
+
+	resp := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Location": []string{"/users/2"}},
+		Body:       httptestBody(`{"users":[{"id":2}]}`),
+	}
+
+	x, err := CaptureExchange(req, resp)
+	assert.NoError(t, err)
+	x.PathParams = map[string]string{"id": "1"}
+
+	cases := map[RuntimeExpression]interface{}{
+		"$url":                      "http://example.com/users/1?queryUrl=http://callback.com",
+		"$method":                   "POST",
+		"$statusCode":               http.StatusCreated,
+		"$request.path.id":          "1",
+		"$request.query.queryUrl":   "http://callback.com",
+		"$request.header.x-token":   "token",
+		"$request.body#/email":      "a@b.com",
+		"$response.body#/users/0":   map[string]interface{}{"id": float64(2)},
+		"$response.header.Location": "/users/2",
+		"{$request.query.queryUrl}?transactionId={$request.body#/id}&user={$response.body#/users/0/id}": "http://callback.com?transactionId=x1&user=2",
+	}
+
+	for expr, expect := range cases {
+		v, err := expr.Evaluate(x)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expect, v, expr)
+	}
+
+	_, err = RuntimeExpression("$request.header.X-Missing").Evaluate(x)
+	assert.ErrorIs(t, err, ErrExpressionValueNotFound)
+
+	_, err = RuntimeExpression("$response.body#/users/3").Evaluate(x)
+	assert.ErrorIs(t, err, ErrExpressionValueNotFound)
+}
+
+func httptestBody(s string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(s))
+}
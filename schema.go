@@ -2,6 +2,11 @@ package oas
 
 import (
 	"encoding/json"
+	"math"
+	"reflect"
+	"slices"
+
+	"github.com/go-courier/ptr"
 )
 
 func NewSchema(tpe Type, fmt string) *Schema {
@@ -71,6 +76,105 @@ func Boolean() *Schema {
 	return NewSchema(TypeBoolean, "")
 }
 
+func UUID() *Schema {
+	return NewSchema(TypeString, "uuid")
+}
+
+func Email() *Schema {
+	return NewSchema(TypeString, "email")
+}
+
+func URI() *Schema {
+	return NewSchema(TypeString, "uri")
+}
+
+func Hostname() *Schema {
+	return NewSchema(TypeString, "hostname")
+}
+
+func IPv4() *Schema {
+	return NewSchema(TypeString, "ipv4")
+}
+
+func IPv6() *Schema {
+	return NewSchema(TypeString, "ipv6")
+}
+
+func Int8() *Schema {
+	return NewSchema(TypeInteger, "int8").WithMin(math.MinInt8).WithMax(math.MaxInt8)
+}
+
+func Int16() *Schema {
+	return NewSchema(TypeInteger, "int16").WithMin(math.MinInt16).WithMax(math.MaxInt16)
+}
+
+func Uint8() *Schema {
+	return NewSchema(TypeInteger, "uint8").WithMin(0).WithMax(math.MaxUint8)
+}
+
+func Uint16() *Schema {
+	return NewSchema(TypeInteger, "uint16").WithMin(0).WithMax(math.MaxUint16)
+}
+
+func Uint32() *Schema {
+	return NewSchema(TypeInteger, "uint32").WithMin(0).WithMax(math.MaxUint32)
+}
+
+func Uint64() *Schema {
+	return NewSchema(TypeInteger, "uint64").WithMin(0)
+}
+
+// Enum creates schema of the values, with type inferred when values are all in the same json type
+func Enum(values ...interface{}) *Schema {
+	s := &Schema{}
+	s.Enum = values
+	for i, v := range values {
+		tpe := typeOfValue(v)
+		switch {
+		case i == 0 || tpe == s.Type:
+		case isNumeric(tpe) && isNumeric(s.Type):
+			// integers are numbers too
+			tpe = TypeNumber
+		default:
+			s.Type = ""
+			return s
+		}
+		s.Type = tpe
+	}
+	return s
+}
+
+func isNumeric(tpe Type) bool {
+	return tpe == TypeInteger || tpe == TypeNumber
+}
+
+// Const creates schema of the only value, as enum with single value
+func Const(value interface{}) *Schema {
+	return Enum(value)
+}
+
+func typeOfValue(v interface{}) Type {
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.String:
+		return TypeString
+	case reflect.Bool:
+		return TypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInteger
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); f == math.Trunc(f) {
+			return TypeInteger
+		}
+		return TypeNumber
+	case reflect.Slice, reflect.Array:
+		return TypeArray
+	case reflect.Map, reflect.Struct:
+		return TypeObject
+	}
+	return ""
+}
+
 func ItemsOf(items *Schema) *Schema {
 	return &Schema{
 		SchemaObject: SchemaObject{
@@ -150,31 +254,34 @@ type Schema struct {
 }
 
 func (s Schema) WithValidation(validation *SchemaValidation) *Schema {
-	s.Enum = validation.Enum
+	c := s.Clone()
+	validation = validation.Clone()
+
+	c.Enum = validation.Enum
 
-	switch s.Type {
+	switch c.Type {
 	case TypeInteger, TypeNumber:
-		s.MultipleOf = validation.MultipleOf
-		s.Maximum = validation.Maximum
-		s.ExclusiveMaximum = validation.ExclusiveMaximum
-		s.Minimum = validation.Minimum
-		s.ExclusiveMinimum = validation.ExclusiveMinimum
+		c.MultipleOf = validation.MultipleOf
+		c.Maximum = validation.Maximum
+		c.ExclusiveMaximum = validation.ExclusiveMaximum
+		c.Minimum = validation.Minimum
+		c.ExclusiveMinimum = validation.ExclusiveMinimum
 	case TypeString:
-		s.MaxLength = validation.MaxLength
-		s.MinLength = validation.MinLength
-		s.Pattern = validation.Pattern
+		c.MaxLength = validation.MaxLength
+		c.MinLength = validation.MinLength
+		c.Pattern = validation.Pattern
 	case TypeArray:
-		s.MaxItems = validation.MaxItems
-		s.MinItems = validation.MinItems
-		s.UniqueItems = validation.UniqueItems
+		c.MaxItems = validation.MaxItems
+		c.MinItems = validation.MinItems
+		c.UniqueItems = validation.UniqueItems
 	case TypeObject:
-		s.MaxProperties = validation.MaxProperties
-		s.MinProperties = validation.MinProperties
-		if len(s.Properties) > 0 {
-			s.Required = validation.Required
+		c.MaxProperties = validation.MaxProperties
+		c.MinProperties = validation.MinProperties
+		if len(c.Properties) > 0 {
+			c.Required = validation.Required
 		}
 	}
-	return &s
+	return c
 }
 
 func (s *Schema) SetProperty(name string, propSchema *Schema, required bool) {
@@ -191,18 +298,166 @@ func (s *Schema) SetProperty(name string, propSchema *Schema, required bool) {
 }
 
 func (s Schema) WithDesc(desc string) *Schema {
-	s.Description = desc
-	return &s
+	c := s.Clone()
+	c.Description = desc
+	return c
 }
 
 func (s Schema) WithTitle(title string) *Schema {
-	s.Title = title
-	return &s
+	c := s.Clone()
+	c.Title = title
+	return c
 }
 
 func (s Schema) WithDiscriminator(discriminator *Discriminator) *Schema {
-	s.Discriminator = discriminator
-	return &s
+	c := s.Clone()
+	c.Discriminator = discriminator.Clone()
+	return c
+}
+
+func (s Schema) WithFormat(format string) *Schema {
+	c := s.Clone()
+	c.Format = format
+	return c
+}
+
+func (s Schema) WithEnum(values ...interface{}) *Schema {
+	c := s.Clone()
+	c.Enum = deepClone(values)
+	return c
+}
+
+// WithConst limits the value to the only one, as enum with single value
+func (s Schema) WithConst(value interface{}) *Schema {
+	return s.WithEnum(value)
+}
+
+func (s Schema) WithDefault(value interface{}) *Schema {
+	c := s.Clone()
+	c.Default = AnyValue(deepClone(value))
+	return c
+}
+
+func (s Schema) WithExample(value interface{}) *Schema {
+	c := s.Clone()
+	c.Example = AnyValue(deepClone(value))
+	return c
+}
+
+func (s Schema) WithNullable() *Schema {
+	c := s.Clone()
+	c.Nullable = true
+	return c
+}
+
+func (s Schema) WithReadOnly() *Schema {
+	c := s.Clone()
+	c.ReadOnly = true
+	return c
+}
+
+func (s Schema) WithWriteOnly() *Schema {
+	c := s.Clone()
+	c.WriteOnly = true
+	return c
+}
+
+func (s Schema) WithDeprecated() *Schema {
+	c := s.Clone()
+	c.Deprecated = true
+	return c
+}
+
+func (s Schema) WithRequired(names ...string) *Schema {
+	c := s.Clone()
+	for _, name := range names {
+		if !slices.Contains(c.Required, name) {
+			c.Required = append(c.Required, name)
+		}
+	}
+	return c
+}
+
+func (s Schema) WithMin(min float64) *Schema {
+	c := s.Clone()
+	c.Minimum = ptr.Float64(min)
+	c.ExclusiveMinimum = false
+	return c
+}
+
+func (s Schema) WithExclusiveMin(min float64) *Schema {
+	c := s.Clone()
+	c.Minimum = ptr.Float64(min)
+	c.ExclusiveMinimum = true
+	return c
+}
+
+func (s Schema) WithMax(max float64) *Schema {
+	c := s.Clone()
+	c.Maximum = ptr.Float64(max)
+	c.ExclusiveMaximum = false
+	return c
+}
+
+func (s Schema) WithExclusiveMax(max float64) *Schema {
+	c := s.Clone()
+	c.Maximum = ptr.Float64(max)
+	c.ExclusiveMaximum = true
+	return c
+}
+
+func (s Schema) WithMultipleOf(multipleOf float64) *Schema {
+	c := s.Clone()
+	c.MultipleOf = ptr.Float64(multipleOf)
+	return c
+}
+
+func (s Schema) WithMinLength(n uint64) *Schema {
+	c := s.Clone()
+	c.MinLength = ptr.Uint64(n)
+	return c
+}
+
+func (s Schema) WithMaxLength(n uint64) *Schema {
+	c := s.Clone()
+	c.MaxLength = ptr.Uint64(n)
+	return c
+}
+
+func (s Schema) WithPattern(pattern string) *Schema {
+	c := s.Clone()
+	c.Pattern = pattern
+	return c
+}
+
+func (s Schema) WithMinItems(n uint64) *Schema {
+	c := s.Clone()
+	c.MinItems = ptr.Uint64(n)
+	return c
+}
+
+func (s Schema) WithMaxItems(n uint64) *Schema {
+	c := s.Clone()
+	c.MaxItems = ptr.Uint64(n)
+	return c
+}
+
+func (s Schema) WithUniqueItems() *Schema {
+	c := s.Clone()
+	c.UniqueItems = true
+	return c
+}
+
+func (s Schema) WithMinProperties(n uint64) *Schema {
+	c := s.Clone()
+	c.MinProperties = ptr.Uint64(n)
+	return c
+}
+
+func (s Schema) WithMaxProperties(n uint64) *Schema {
+	c := s.Clone()
+	c.MaxProperties = ptr.Uint64(n)
+	return c
 }
 
 func (s Schema) MarshalJSON() ([]byte, error) {
@@ -260,7 +515,7 @@ type SchemaObject struct {
 
 	Description string `json:"description,omitempty"`
 
-	Default interface{} `json:"default,omitempty"`
+	Default Any `json:"default,omitzero"`
 
 	Nullable      bool           `json:"nullable,omitempty"`
 	Discriminator *Discriminator `json:"discriminator,omitempty"`
@@ -268,7 +523,7 @@ type SchemaObject struct {
 	WriteOnly     bool           `json:"writeOnly,omitempty"`
 	XML           *XML           `json:"xml,omitempty"`
 	ExternalDocs  *ExternalDoc   `json:"external_docs,omitempty"`
-	Example       interface{}    `json:"example,omitempty"`
+	Example       Any            `json:"example,omitzero"`
 	Deprecated    bool           `json:"deprecated,omitempty"`
 }
 
@@ -303,15 +558,16 @@ type SchemaOrBool struct {
 }
 
 func (s *SchemaOrBool) UnmarshalJSON(data []byte) error {
-	s.Allows = true
 	if len(data) > 0 && data[0] == '{' {
 		var schema Schema
 		if err := json.Unmarshal(data, &schema); err != nil {
 			return err
 		}
+		s.Allows = true
 		s.Schema = &schema
+		return nil
 	}
-	return nil
+	return json.Unmarshal(data, &s.Allows)
 }
 
 func (s *SchemaOrBool) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,336 @@
+package oas
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+type Compatibility string
+
+const (
+	// CompatibilityFull means schemas could read data written by each other
+	CompatibilityFull Compatibility = "full"
+	// CompatibilityBackward means new schema could read data written by old one
+	CompatibilityBackward Compatibility = "backward"
+	// CompatibilityForward means old schema could read data written by new one
+	CompatibilityForward Compatibility = "forward"
+	CompatibilityNone    Compatibility = "none"
+)
+
+type Incompatibility struct {
+	// Direction is CompatibilityBackward when new schema could not read data written by old one,
+	// CompatibilityForward when old schema could not read data written by new one
+	Direction Compatibility
+	Pointer   string
+	Message   string
+}
+
+func (i *Incompatibility) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Direction, i.Pointer, i.Message)
+}
+
+type CompatibilityReport struct {
+	Compatibility     Compatibility
+	Incompatibilities []*Incompatibility
+}
+
+// CompatibilityChecker checks whether schemas could read data written by each other
+type CompatibilityChecker struct {
+	// Old resolves refs of the old schema
+	Old *ComponentsObject
+	// New resolves refs of the new schema
+	New *ComponentsObject
+}
+
+// CheckCompatibility checks schemas with refs resolved by components of the documents
+func CheckCompatibility(old *OpenAPI, oldSchema *Schema, new *OpenAPI, newSchema *Schema) *CompatibilityReport {
+	c := &CompatibilityChecker{}
+	if old != nil {
+		c.Old = &old.ComponentsObject
+	}
+	if new != nil {
+		c.New = &new.ComponentsObject
+	}
+	return c.Check(oldSchema, newSchema)
+}
+
+// Check returns the verdict with incompatibilities of both directions
+func (c *CompatibilityChecker) Check(old *Schema, new *Schema) *CompatibilityReport {
+	oldComponents, newComponents := c.Old, c.New
+	if oldComponents == nil {
+		oldComponents = &ComponentsObject{}
+	}
+	if newComponents == nil {
+		newComponents = &ComponentsObject{}
+	}
+
+	backward := &compatState{direction: CompatibilityBackward, reader: newComponents, writer: oldComponents, checking: map[[2]*Schema]bool{}, flattened: map[*Schema]*Schema{}}
+	backward.readable("", new, old)
+	forward := &compatState{direction: CompatibilityForward, reader: oldComponents, writer: newComponents, checking: map[[2]*Schema]bool{}, flattened: map[*Schema]*Schema{}}
+	forward.readable("", old, new)
+
+	report := &CompatibilityReport{
+		Incompatibilities: append(backward.incompatibilities, forward.incompatibilities...),
+	}
+	switch {
+	case len(backward.incompatibilities) == 0 && len(forward.incompatibilities) == 0:
+		report.Compatibility = CompatibilityFull
+	case len(backward.incompatibilities) == 0:
+		report.Compatibility = CompatibilityBackward
+	case len(forward.incompatibilities) == 0:
+		report.Compatibility = CompatibilityForward
+	default:
+		report.Compatibility = CompatibilityNone
+	}
+	return report
+}
+
+type compatState struct {
+	direction Compatibility
+	reader    *ComponentsObject
+	writer    *ComponentsObject
+	checking  map[[2]*Schema]bool
+	// flattened caches schemas with allOf flattened, so that circular refs could be detected
+	flattened         map[*Schema]*Schema
+	incompatibilities []*Incompatibility
+}
+
+func (s *compatState) report(pointer string, format string, args ...interface{}) {
+	s.incompatibilities = append(s.incompatibilities, &Incompatibility{Direction: s.direction, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// accepts returns true when reader could read all data written by writer
+func (s *compatState) accepts(reader *Schema, writer *Schema) bool {
+	tmp := &compatState{direction: s.direction, reader: s.reader, writer: s.writer, checking: s.checking, flattened: s.flattened}
+	tmp.readable("", reader, writer)
+	return len(tmp.incompatibilities) == 0
+}
+
+// readable reports cases data written by writer could not be read by reader
+func (s *compatState) readable(pointer string, reader *Schema, writer *Schema) {
+	resolvedReader, resolvedWriter := s.resolve(s.reader, reader), s.resolve(s.writer, writer)
+	// values accepted or written by dangling refs are unknown
+	if reader != nil && resolvedReader == nil {
+		s.report(pointer, "ref %s of reader not resolved", reader.Refer.RefString())
+		return
+	}
+	if writer != nil && resolvedWriter == nil {
+		s.report(pointer, "ref %s of writer not resolved", writer.Refer.RefString())
+		return
+	}
+	reader, writer = resolvedReader, resolvedWriter
+	if reader == nil {
+		return
+	}
+	if writer == nil {
+		// any values written
+		writer = unconstrained
+	}
+
+	// refs could be circular
+	key := [2]*Schema{reader, writer}
+	if s.checking[key] {
+		return
+	}
+	s.checking[key] = true
+	defer delete(s.checking, key)
+
+	if len(reader.OneOf) > 0 || len(writer.OneOf) > 0 {
+		s.branches(pointer, "oneOf", reader.OneOf, writer.OneOf, reader, writer)
+		return
+	}
+	if len(reader.AnyOf) > 0 || len(writer.AnyOf) > 0 {
+		s.branches(pointer, "anyOf", reader.AnyOf, writer.AnyOf, reader, writer)
+		return
+	}
+
+	s.types(pointer, reader, writer)
+	s.enum(pointer, reader, writer)
+	s.bounds(pointer, reader, writer)
+
+	if writer.Type == "" || writer.Type == TypeArray {
+		s.readable(pointer+"/items", reader.Items, writer.Items)
+	}
+	s.properties(pointer, reader, writer)
+}
+
+// unconstrained is the schema of writers without schema, shared so that circular refs of readers could be detected
+var unconstrained = &Schema{}
+
+// resolve resolves refs and flattens allOf
+func (s *compatState) resolve(components *ComponentsObject, schema *Schema) *Schema {
+	schema = components.ResolveSchema(schema)
+	if schema == nil || len(schema.AllOf) == 0 {
+		return schema
+	}
+	if flattened, ok := s.flattened[schema]; ok {
+		return flattened
+	}
+	flattened, _ := FlattenAllOf(components, schema)
+	s.flattened[schema] = flattened
+	return flattened
+}
+
+// branches checks every branch of writer accepted by any branch of reader
+func (s *compatState) branches(pointer string, keyword string, readerBranches []*Schema, writerBranches []*Schema, reader *Schema, writer *Schema) {
+	if len(readerBranches) == 0 {
+		for i, branch := range writerBranches {
+			s.readable(pointer+"/"+keyword+"/"+strconv.Itoa(i), reader, branch)
+		}
+		return
+	}
+
+	if len(writerBranches) == 0 {
+		for _, branch := range readerBranches {
+			if s.accepts(branch, writer) {
+				return
+			}
+		}
+		s.report(pointer, "%s accepts none of written values", keyword)
+		return
+	}
+
+	for i, wb := range writerBranches {
+		accepted := false
+		for _, rb := range readerBranches {
+			if s.accepts(rb, wb) {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			s.report(pointer+"/"+keyword+"/"+strconv.Itoa(i), "branch not accepted by any of %s", keyword)
+		}
+	}
+}
+
+func (s *compatState) types(pointer string, reader *Schema, writer *Schema) {
+	switch {
+	case reader.Type == "" || reader.Type == writer.Type:
+	case writer.Type == "":
+		s.report(pointer+"/type", "type %s required, but not constrained", reader.Type)
+	case reader.Type == TypeNumber && writer.Type == TypeInteger:
+		// widening
+	default:
+		s.report(pointer+"/type", "type %s could not read %s", reader.Type, writer.Type)
+	}
+
+	if writer.Nullable && !reader.Nullable && reader.Type != "" {
+		s.report(pointer+"/nullable", "null not allowed")
+	}
+
+	if reader.Format != "" && reader.Format != writer.Format && !widenFormats[[2]string{writer.Format, reader.Format}] {
+		if writer.Format == "" {
+			s.report(pointer+"/format", "format %s required, but not constrained", reader.Format)
+		} else {
+			s.report(pointer+"/format", "format %s could not read %s", reader.Format, writer.Format)
+		}
+	}
+}
+
+// widenFormats holds pairs of format written and format could read it
+var widenFormats = map[[2]string]bool{
+	{"int32", "int64"}:  true,
+	{"float", "double"}: true,
+}
+
+func (s *compatState) enum(pointer string, reader *Schema, writer *Schema) {
+	if len(reader.Enum) == 0 {
+		return
+	}
+	if len(writer.Enum) == 0 {
+		s.report(pointer+"/enum", "enum required, but values not constrained")
+		return
+	}
+	for _, v := range writer.Enum {
+		found := false
+		for _, w := range reader.Enum {
+			if jsonEqual(v, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.report(pointer+"/enum", "value %v not in enum", v)
+		}
+	}
+}
+
+func (s *compatState) bounds(pointer string, reader *Schema, writer *Schema) {
+	if reader.Maximum != nil && (writer.Maximum == nil || *writer.Maximum > *reader.Maximum ||
+		(*writer.Maximum == *reader.Maximum && reader.ExclusiveMaximum && !writer.ExclusiveMaximum)) {
+		s.report(pointer+"/maximum", "maximum tightened to %v", *reader.Maximum)
+	}
+	if reader.Minimum != nil && (writer.Minimum == nil || *writer.Minimum < *reader.Minimum ||
+		(*writer.Minimum == *reader.Minimum && reader.ExclusiveMinimum && !writer.ExclusiveMinimum)) {
+		s.report(pointer+"/minimum", "minimum tightened to %v", *reader.Minimum)
+	}
+	if reader.MultipleOf != nil && (writer.MultipleOf == nil || !isMultipleOf(*writer.MultipleOf, *reader.MultipleOf)) {
+		s.report(pointer+"/multipleOf", "multipleOf tightened to %v", *reader.MultipleOf)
+	}
+
+	for _, b := range []struct {
+		keyword        string
+		reader, writer *uint64
+		max            bool
+	}{
+		{"maxLength", reader.MaxLength, writer.MaxLength, true},
+		{"minLength", reader.MinLength, writer.MinLength, false},
+		{"maxItems", reader.MaxItems, writer.MaxItems, true},
+		{"minItems", reader.MinItems, writer.MinItems, false},
+		{"maxProperties", reader.MaxProperties, writer.MaxProperties, true},
+		{"minProperties", reader.MinProperties, writer.MinProperties, false},
+	} {
+		if b.reader == nil {
+			continue
+		}
+		if b.writer == nil || (b.max && *b.writer > *b.reader) || (!b.max && *b.writer < *b.reader) {
+			s.report(pointer+"/"+b.keyword, "%s tightened to %d", b.keyword, *b.reader)
+		}
+	}
+
+	if reader.Pattern != "" && reader.Pattern != writer.Pattern {
+		s.report(pointer+"/pattern", "pattern changed to %s", reader.Pattern)
+	}
+	if reader.UniqueItems && !writer.UniqueItems {
+		s.report(pointer+"/uniqueItems", "unique items required")
+	}
+}
+
+func (s *compatState) properties(pointer string, reader *Schema, writer *Schema) {
+	for _, name := range reader.Required {
+		if !slices.Contains(writer.Required, name) {
+			s.report(pointer+"/required", "property %s required, but could be omitted", name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(writer.Properties)) {
+		propPointer := pointer + "/properties/" + escapeJSONPointer(name)
+		if p, ok := reader.Properties[name]; ok {
+			s.readable(propPointer, p, writer.Properties[name])
+			continue
+		}
+		switch ap := reader.AdditionalProperties; {
+		case ap == nil:
+		case ap.Schema != nil:
+			s.readable(propPointer, ap.Schema, writer.Properties[name])
+		case !ap.Allows:
+			s.report(propPointer, "property %s not allowed", name)
+		}
+	}
+
+	if rap := reader.AdditionalProperties; rap != nil {
+		wap := writer.AdditionalProperties
+		switch {
+		case rap.Schema == nil && rap.Allows:
+		case wap == nil || (wap.Schema == nil && wap.Allows):
+			s.report(pointer+"/additionalProperties", "additional properties restricted")
+		case rap.Schema != nil && wap.Schema != nil:
+			s.readable(pointer+"/additionalProperties", rap.Schema, wap.Schema)
+		case rap.Schema == nil && wap.Schema != nil:
+			s.report(pointer+"/additionalProperties", "additional properties not allowed")
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package oas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompatibilityChecker(t *testing.T) {
+	parse := func(data string) *Schema {
+		s := &Schema{}
+		assert.NoError(t, json.Unmarshal([]byte(data), s))
+		return s
+	}
+
+	check := func(old string, new string) *CompatibilityReport {
+		return (&CompatibilityChecker{}).Check(parse(old), parse(new))
+	}
+
+	messages := func(report *CompatibilityReport) []string {
+		list := make([]string, len(report.Incompatibilities))
+		for i, x := range report.Incompatibilities {
+			list[i] = x.String()
+		}
+		return list
+	}
+
+	t.Run("same", func(t *testing.T) {
+		report := check(`{"type": "object", "properties": {"id": {"type": "integer"}}}`, `{"type": "object", "properties": {"id": {"type": "integer"}}}`)
+		assert.Equal(t, CompatibilityFull, report.Compatibility)
+		assert.Empty(t, report.Incompatibilities)
+	})
+
+	t.Run("type widening", func(t *testing.T) {
+		report := check(`{"type": "integer", "format": "int32"}`, `{"type": "number", "format": "double"}`)
+		assert.Equal(t, CompatibilityNone, report.Compatibility)
+
+		report = check(`{"type": "integer", "format": "int32"}`, `{"type": "integer", "format": "int64"}`)
+		assert.Equal(t, CompatibilityBackward, report.Compatibility)
+		assert.Equal(t, []string{"forward: /format: format int32 could not read int64"}, messages(report))
+
+		report = check(`{"type": "number"}`, `{"type": "integer"}`)
+		assert.Equal(t, CompatibilityForward, report.Compatibility)
+		assert.Equal(t, []string{"backward: /type: type integer could not read number"}, messages(report))
+	})
+
+	t.Run("required", func(t *testing.T) {
+		report := check(
+			`{"type": "object", "properties": {"id": {"type": "string"}, "name": {"type": "string"}}, "required": ["id"]}`,
+			`{"type": "object", "properties": {"id": {"type": "string"}, "name": {"type": "string"}}, "required": ["id", "name"]}`,
+		)
+		assert.Equal(t, CompatibilityForward, report.Compatibility)
+		assert.Equal(t, []string{"backward: /required: property name required, but could be omitted"}, messages(report))
+	})
+
+	t.Run("additional properties", func(t *testing.T) {
+		report := check(
+			`{"type": "object", "properties": {"id": {"type": "string"}, "name": {"type": "string"}}}`,
+			`{"type": "object", "properties": {"id": {"type": "string"}}, "additionalProperties": false}`,
+		)
+		assert.Equal(t, CompatibilityForward, report.Compatibility)
+		assert.Equal(t, []string{
+			"backward: /properties/name: property name not allowed",
+			"backward: /additionalProperties: additional properties restricted",
+		}, messages(report))
+
+		report = check(
+			`{"type": "object", "additionalProperties": {"type": "string"}}`,
+			`{"type": "object", "additionalProperties": {"type": "integer"}}`,
+		)
+		assert.Equal(t, CompatibilityNone, report.Compatibility)
+	})
+
+	t.Run("enum", func(t *testing.T) {
+		report := check(`{"type": "string", "enum": ["a", "b"]}`, `{"type": "string", "enum": ["a", "b", "c"]}`)
+		assert.Equal(t, CompatibilityBackward, report.Compatibility)
+		assert.Equal(t, []string{"forward: /enum: value c not in enum"}, messages(report))
+
+		report = check(`{"type": "string"}`, `{"type": "string", "enum": ["a"]}`)
+		assert.Equal(t, CompatibilityForward, report.Compatibility)
+	})
+
+	t.Run("items not constrained", func(t *testing.T) {
+		report := check(`{"type": "array"}`, `{"type": "array", "items": {"type": "string", "maxLength": 8}}`)
+		assert.Equal(t, CompatibilityForward, report.Compatibility)
+		assert.Equal(t, []string{
+			"backward: /items/type: type string required, but not constrained",
+			"backward: /items/maxLength: maxLength tightened to 8",
+		}, messages(report))
+
+		report = (&CompatibilityChecker{}).Check(nil, parse(`{"type": "object", "required": ["id"]}`))
+		assert.Equal(t, CompatibilityForward, report.Compatibility)
+	})
+
+	t.Run("circular items not constrained", func(t *testing.T) {
+		node := &Schema{}
+		node.Type = TypeArray
+		node.Items = node
+
+		report := (&CompatibilityChecker{}).Check(ItemsOf(nil), ItemsOf(node))
+		assert.Equal(t, CompatibilityForward, report.Compatibility)
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		report := check(
+			`{"type": "object", "properties": {
+				"age": {"type": "integer", "minimum": 0, "maximum": 200},
+				"name": {"type": "string", "maxLength": 100},
+				"tags": {"type": "array", "items": {"type": "string"}, "minItems": 0}
+			}}`,
+			`{"type": "object", "properties": {
+				"age": {"type": "integer", "minimum": 0, "maximum": 150, "exclusiveMinimum": true},
+				"name": {"type": "string", "maxLength": 200, "pattern": "^[a-z]+$"},
+				"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "uniqueItems": true}
+			}}`,
+		)
+		assert.Equal(t, CompatibilityNone, report.Compatibility)
+		assert.Equal(t, []string{
+			"backward: /properties/age/maximum: maximum tightened to 150",
+			"backward: /properties/age/minimum: minimum tightened to 0",
+			"backward: /properties/name/pattern: pattern changed to ^[a-z]+$",
+			"backward: /properties/tags/minItems: minItems tightened to 1",
+			"backward: /properties/tags/uniqueItems: unique items required",
+			"forward: /properties/name/maxLength: maxLength tightened to 100",
+		}, messages(report))
+	})
+
+	t.Run("oneOf branches", func(t *testing.T) {
+		report := check(
+			`{"oneOf": [{"type": "string"}, {"type": "integer"}]}`,
+			`{"oneOf": [{"type": "string"}, {"type": "integer"}, {"type": "boolean"}]}`,
+		)
+		assert.Equal(t, CompatibilityBackward, report.Compatibility)
+		assert.Equal(t, []string{"forward: /oneOf/2: branch not accepted by any of oneOf"}, messages(report))
+
+		report = check(`{"type": "string"}`, `{"oneOf": [{"type": "string"}, {"type": "integer"}]}`)
+		assert.Equal(t, CompatibilityBackward, report.Compatibility)
+		assert.Equal(t, []string{"forward: /oneOf/1/type: type string could not read integer"}, messages(report))
+	})
+
+	t.Run("refs resolved by documents", func(t *testing.T) {
+		old := NewOpenAPI()
+		old.AddSchema("Base", ObjectOf(Props{"id": String()}, "id"))
+		old.AddSchema("Node", ObjectOf(Props{}))
+		old.Schemas["Node"].SetProperty("children", ItemsOf(old.RefSchema("Node")), false)
+		old.AddSchema("Pet", AllOf(old.RefSchema("Base"), ObjectOf(Props{"node": old.RefSchema("Node")})))
+
+		new := NewOpenAPI()
+		new.AddSchema("Pet", ObjectOf(Props{"id": String(), "node": ObjectOf(Props{"children": ItemsOf(old.RefSchema("Node"))})}, "id"))
+		new.AddSchema("Node", ObjectOf(Props{}))
+		new.Schemas["Node"].SetProperty("children", ItemsOf(new.RefSchema("Node")), false)
+
+		report := CheckCompatibility(old, old.RefSchema("Pet"), new, new.RefSchema("Pet"))
+		assert.Equal(t, CompatibilityFull, report.Compatibility)
+		assert.Empty(t, report.Incompatibilities)
+	})
+
+	t.Run("dangling refs", func(t *testing.T) {
+		old := NewOpenAPI()
+		old.AddSchema("Pet", ObjectOf(Props{"id": String()}))
+
+		new := NewOpenAPI()
+		new.AddSchema("Pet", ObjectOf(Props{"id": RefSchemaByRefer(NewComponentRefer("schemas", "ID"))}))
+
+		report := CheckCompatibility(old, old.RefSchema("Pet"), new, new.RefSchema("Pet"))
+		assert.Equal(t, CompatibilityNone, report.Compatibility)
+		assert.Equal(t, []string{
+			"backward: /properties/id: ref #/components/schemas/ID of reader not resolved",
+			"forward: /properties/id: ref #/components/schemas/ID of writer not resolved",
+		}, messages(report))
+
+		report = CheckCompatibility(old, RefSchemaByRefer(NewComponentRefer("schemas", "Missing")), new, new.RefSchema("Pet"))
+		assert.Equal(t, CompatibilityNone, report.Compatibility)
+	})
+}
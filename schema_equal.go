@@ -0,0 +1,103 @@
+package oas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// SchemaComparer compares schemas structurally,
+// orders of keys, required and enum values ignored.
+type SchemaComparer struct {
+	// IgnoreDocs ignores description and example of schemas
+	IgnoreDocs bool
+}
+
+// SchemaEqual compares schemas with default SchemaComparer
+func SchemaEqual(a *Schema, b *Schema) bool {
+	return (&SchemaComparer{}).Equal(a, b)
+}
+
+// SchemaHash hashes schema with default SchemaComparer
+func SchemaHash(s *Schema) string {
+	return (&SchemaComparer{}).Hash(s)
+}
+
+// Equal returns true when schemas structurally equal
+func (c *SchemaComparer) Equal(a *Schema, b *Schema) bool {
+	return c.Hash(a) == c.Hash(b)
+}
+
+// Hash returns stable hash of the schema, same for the structurally equal ones
+func (c *SchemaComparer) Hash(s *Schema) string {
+	sum := sha256.Sum256(c.canonicalJSON(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *SchemaComparer) canonicalJSON(s *Schema) []byte {
+	if s == nil {
+		return []byte("null")
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	// keys of maps sorted when marshal
+	data, _ = json.Marshal(c.canonical(v))
+	return data
+}
+
+// canonical normalizes decoded schema value, sub schemas included
+func (c *SchemaComparer) canonical(v interface{}) interface{} {
+	s, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	if c.IgnoreDocs {
+		delete(s, "description")
+		delete(s, "example")
+	}
+
+	if required, ok := s["required"].([]interface{}); ok {
+		slices.SortFunc(required, func(a, b interface{}) int {
+			return compareJSON(a, b)
+		})
+	}
+	if enum, ok := s["enum"].([]interface{}); ok {
+		slices.SortFunc(enum, func(a, b interface{}) int {
+			return compareJSON(a, b)
+		})
+	}
+
+	for _, key := range []string{"items", "additionalProperties", "propertyNames", "not"} {
+		if sub, ok := s[key]; ok {
+			s[key] = c.canonical(sub)
+		}
+	}
+	if props, ok := s["properties"].(map[string]interface{}); ok {
+		for name := range props {
+			props[name] = c.canonical(props[name])
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if list, ok := s[key].([]interface{}); ok {
+			for i := range list {
+				list[i] = c.canonical(list[i])
+			}
+		}
+	}
+	return s
+}
+
+func compareJSON(a interface{}, b interface{}) int {
+	dataA, _ := json.Marshal(a)
+	dataB, _ := json.Marshal(b)
+	return strings.Compare(string(dataA), string(dataB))
+}
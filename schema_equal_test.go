@@ -0,0 +1,59 @@
+package oas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaEqual(t *testing.T) {
+	parse := func(data string) *Schema {
+		s := &Schema{}
+		assert.NoError(t, json.Unmarshal([]byte(data), s))
+		return s
+	}
+
+	a := parse(`{"type": "object", "required": ["id", "name"], "properties": {
+		"id": {"type": "integer"},
+		"kind": {"type": "string", "enum": ["cat", "dog"], "description": "kind"},
+		"tags": {"type": "array", "items": {"type": "object", "properties": {"description": {"type": "string"}}, "example": {"description": "x"}}}
+	}}`)
+	b := parse(`{"properties": {
+		"tags": {"items": {"properties": {"description": {"type": "string"}}, "type": "object", "example": {"description": "x"}}, "type": "array"},
+		"kind": {"enum": ["dog", "cat"], "type": "string", "description": "kind"},
+		"id": {"type": "integer"}
+	}, "required": ["name", "id"], "type": "object"}`)
+
+	assert.True(t, SchemaEqual(a, b))
+	assert.Equal(t, SchemaHash(a), SchemaHash(b))
+	assert.Len(t, SchemaHash(a), 64)
+
+	t.Run("differences", func(t *testing.T) {
+		c := parse(`{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}`)
+		d := parse(`{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer", "format": "int64"}}}`)
+		assert.False(t, SchemaEqual(c, d))
+		assert.False(t, SchemaEqual(c, nil))
+		assert.True(t, SchemaEqual(nil, nil))
+
+		// order of compositions matters
+		e := parse(`{"oneOf": [{"type": "string"}, {"type": "integer"}]}`)
+		f := parse(`{"oneOf": [{"type": "integer"}, {"type": "string"}]}`)
+		assert.False(t, SchemaEqual(e, f))
+	})
+
+	t.Run("ignore docs", func(t *testing.T) {
+		c := parse(`{"type": "object", "description": "a", "properties": {"description": {"type": "string", "example": "a"}}}`)
+		d := parse(`{"type": "object", "description": "b", "properties": {"description": {"type": "string"}}}`)
+
+		assert.False(t, SchemaEqual(c, d))
+		assert.True(t, (&SchemaComparer{IgnoreDocs: true}).Equal(c, d))
+
+		// property named description kept
+		e := parse(`{"type": "object", "properties": {"name": {"type": "string"}}}`)
+		assert.False(t, (&SchemaComparer{IgnoreDocs: true}).Equal(d, e))
+
+		// source not touched
+		assert.Equal(t, "a", c.Description)
+	})
+}
@@ -0,0 +1,239 @@
+package oas
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// SchemaExtractor moves repeated inline schemas into components schemas, and replaces them with refs.
+//
+// Only schemas with properties, enum or compositions are extracted.
+// Inline schemas structurally equal to existing components are replaced with refs to them too.
+// Schemas are compared by the SchemaComparer, the first occurrence kept when docs ignored.
+type SchemaExtractor struct {
+	SchemaComparer
+	// MinOccurrences of inline schemas to be extracted, 2 by default
+	MinOccurrences int
+	// Name suggests name of the extracted schema with json pointers of its occurrences,
+	// title in PascalCase or name generated from the location used when nil or empty returned
+	Name func(s *Schema, pointers []string) string
+}
+
+// ExtractedSchema records the component and pointers of inline schemas replaced by ref to it
+type ExtractedSchema struct {
+	Name     string
+	Pointers []string
+}
+
+// ExtractSchemas extracts repeated inline schemas with default SchemaExtractor
+func ExtractSchemas(openapi *OpenAPI) (*OpenAPI, []*ExtractedSchema, error) {
+	return (&SchemaExtractor{}).Extract(openapi)
+}
+
+// Extract returns the refactored copy of the document, and the extracted schemas
+func (e *SchemaExtractor) Extract(openapi *OpenAPI) (*OpenAPI, []*ExtractedSchema, error) {
+	o := &OpenAPI{}
+	if err := cloneByJSON(openapi, o); err != nil {
+		return nil, nil, err
+	}
+
+	minOccurrences := e.MinOccurrences
+	if minOccurrences <= 0 {
+		minOccurrences = 2
+	}
+
+	extracted := make([]*ExtractedSchema, 0)
+	byName := map[string]*ExtractedSchema{}
+
+	// extracts the largest group each round, so that outer schemas extracted before the inner ones
+	for {
+		existing := map[string]string{}
+		for _, name := range slices.Sorted(maps.Keys(o.Schemas)) {
+			if s := o.Schemas[name]; s != nil && s.Refer == nil {
+				if h := e.Hash(s); existing[h] == "" {
+					existing[h] = name
+				}
+			}
+		}
+
+		groups := map[string]*schemaOccurrences{}
+		visit := func(pointer string, s *Schema) {
+			h := e.Hash(s)
+			g, ok := groups[h]
+			if !ok {
+				g = &schemaOccurrences{size: len(e.canonicalJSON(s))}
+				groups[h] = g
+			}
+			g.pointers = append(g.pointers, pointer)
+			g.schemas = append(g.schemas, s)
+		}
+		for _, name := range slices.Sorted(maps.Keys(o.Schemas)) {
+			if s := o.Schemas[name]; s != nil && s.Refer == nil {
+				eachSubSchema("/components/schemas/"+escapeJSONPointer(name), s, func(pointer string, sub *Schema) {
+					walkInlineSchemas(pointer, sub, visit)
+				})
+			}
+		}
+		o.eachSchema(func(pointer string, s *Schema) {
+			walkInlineSchemas(pointer, s, visit)
+		})
+
+		var picked *schemaOccurrences
+		pickedHash := ""
+		for _, h := range slices.Sorted(maps.Keys(groups)) {
+			g := groups[h]
+			if existing[h] == "" && len(g.pointers) < minOccurrences {
+				continue
+			}
+			if picked == nil || g.size > picked.size {
+				picked, pickedHash = g, h
+			}
+		}
+		if picked == nil {
+			break
+		}
+
+		name := existing[pickedHash]
+		if name == "" {
+			schema := &Schema{}
+			if err := cloneByJSON(picked.schemas[0], schema); err != nil {
+				return nil, nil, err
+			}
+			name = e.nameOf(o, schema, picked.pointers)
+			o.AddSchema(name, schema)
+		}
+		for _, s := range picked.schemas {
+			*s = *o.RefSchema(name)
+		}
+
+		if x, ok := byName[name]; ok {
+			x.Pointers = append(x.Pointers, picked.pointers...)
+		} else {
+			byName[name] = &ExtractedSchema{Name: name, Pointers: picked.pointers}
+			extracted = append(extracted, byName[name])
+		}
+	}
+
+	return o, extracted, nil
+}
+
+type schemaOccurrences struct {
+	size     int
+	pointers []string
+	schemas  []*Schema
+}
+
+func (e *SchemaExtractor) nameOf(o *OpenAPI, s *Schema, pointers []string) string {
+	name := ""
+	if e.Name != nil {
+		name = e.Name(s, pointers)
+	}
+	if name == "" {
+		name = pascalCase(s.Title)
+	}
+	if name == "" {
+		name = suggestSchemaName(o, pointers[0])
+	}
+	if name == "" {
+		name = "Schema"
+	}
+	unique := name
+	for i := 2; o.Schemas[unique] != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	return unique
+}
+
+// suggestSchemaName names schema by its location, like property name, or operationId with Request or Response
+func suggestSchemaName(o *OpenAPI, pointer string) string {
+	tokens, err := ParseJSONPointer(pointer)
+	if err != nil || len(tokens) < 3 {
+		return ""
+	}
+
+	suffix := ""
+	for i := len(tokens) - 1; i > 0; i-- {
+		if tokens[i-1] == "properties" {
+			return pascalCase(tokens[i]) + suffix
+		}
+		if tokens[i] == "items" && suffix == "" {
+			suffix = "Item"
+		}
+	}
+
+	base := ""
+	switch tokens[0] {
+	case "components":
+		base = pascalCase(tokens[2])
+	case "paths":
+		if item := o.Paths.Paths[tokens[1]]; item != nil {
+			if op := item.Operations.Operations[HttpMethod(tokens[2])]; op != nil && op.OperationId != "" {
+				base = pascalCase(op.OperationId)
+			}
+		}
+		if base == "" {
+			base = pascalCase(tokens[2] + " " + tokens[1])
+		}
+	}
+
+	for _, token := range tokens {
+		switch token {
+		case "requestBody":
+			return base + "Request" + suffix
+		case "responses":
+			return base + "Response" + suffix
+		case "parameters":
+			return base + "Parameter" + suffix
+		}
+	}
+	return base + suffix
+}
+
+// walkInlineSchemas calls fn with candidates of extracting in pre-order, refs skipped
+func walkInlineSchemas(pointer string, s *Schema, fn func(pointer string, s *Schema)) {
+	if s == nil || s.Refer != nil {
+		return
+	}
+	if len(s.Properties) > 0 || len(s.Enum) > 0 || len(s.AllOf) > 0 || len(s.AnyOf) > 0 || len(s.OneOf) > 0 {
+		fn(pointer, s)
+	}
+	eachSubSchema(pointer, s, func(pointer string, sub *Schema) {
+		walkInlineSchemas(pointer, sub, fn)
+	})
+}
+
+// eachSubSchema calls fn with direct sub schemas of s
+func eachSubSchema(pointer string, s *Schema, fn func(pointer string, sub *Schema)) {
+	if s.Items != nil {
+		fn(pointer+"/items", s.Items)
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		if sub := s.Properties[name]; sub != nil {
+			fn(pointer+"/properties/"+escapeJSONPointer(name), sub)
+		}
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		fn(pointer+"/additionalProperties", s.AdditionalProperties.Schema)
+	}
+	if s.PropertyNames != nil {
+		fn(pointer+"/propertyNames", s.PropertyNames)
+	}
+	for _, composition := range []struct {
+		key     string
+		schemas []*Schema
+	}{
+		{"allOf", s.AllOf},
+		{"anyOf", s.AnyOf},
+		{"oneOf", s.OneOf},
+	} {
+		for i, sub := range composition.schemas {
+			if sub != nil {
+				fn(pointer+"/"+composition.key+"/"+strconv.Itoa(i), sub)
+			}
+		}
+	}
+	if s.Not != nil {
+		fn(pointer+"/not", s.Not)
+	}
+}
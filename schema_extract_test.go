@@ -0,0 +1,97 @@
+package oas
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaExtractor(t *testing.T) {
+	newAddress := func() *Schema {
+		return ObjectOf(Props{"city": String(), "street": String()}, "city")
+	}
+	newUser := func(desc string) *Schema {
+		return ObjectOf(Props{
+			"name":    String(),
+			"address": newAddress(),
+			"role":    String().WithValidation(&SchemaValidation{Enum: []interface{}{"admin", "member"}}),
+		}, "name").WithDesc(desc)
+	}
+
+	openapi := NewOpenAPI()
+	openapi.AddSchema("Role", String().WithValidation(&SchemaValidation{Enum: []interface{}{"member", "admin"}}))
+	openapi.AddSchema("Company", ObjectOf(Props{"address": newAddress()}))
+
+	create := NewOperation("createUser")
+	rb := NewRequestBody("", true)
+	rb.AddContent("application/json", NewMediaTypeWithSchema(newUser("new user")))
+	create.SetRequestBody(rb)
+	resp := NewResponse("ok")
+	resp.AddContent("application/json", NewMediaTypeWithSchema(newUser("created user")))
+	create.AddResponse(http.StatusOK, resp)
+	openapi.AddOperation(POST, "/users", create)
+
+	list := NewOperation("listUsers")
+	resp = NewResponse("ok")
+	resp.AddContent("application/json", NewMediaTypeWithSchema(ItemsOf(ObjectOf(Props{"id": Long()}))))
+	list.AddResponse(http.StatusOK, resp)
+	openapi.AddOperation(GET, "/users", list)
+
+	t.Run("exact", func(t *testing.T) {
+		refactored, extracted, err := ExtractSchemas(openapi)
+		assert.NoError(t, err)
+
+		// users differ in description, only address and role extracted
+		assert.Equal(t, []string{"Address", "Company", "Role"}, slices.Sorted(maps.Keys(refactored.Schemas)))
+		assert.Equal(t, []*ExtractedSchema{
+			{Name: "Address", Pointers: []string{
+				"/components/schemas/Company/properties/address",
+				"/paths/~1users/post/requestBody/content/application~1json/schema/properties/address",
+				"/paths/~1users/post/responses/200/content/application~1json/schema/properties/address",
+			}},
+			{Name: "Role", Pointers: []string{
+				"/paths/~1users/post/requestBody/content/application~1json/schema/properties/role",
+				"/paths/~1users/post/responses/200/content/application~1json/schema/properties/role",
+			}},
+		}, extracted)
+
+		body := refactored.Paths.Paths["/users"].Operations.Operations[POST].RequestBody.Content["application/json"].Schema
+		assert.Equal(t, refactored.RefSchema("Address"), body.Properties["address"])
+		assert.Equal(t, refactored.RefSchema("Role"), body.Properties["role"])
+		assert.Equal(t, refactored.RefSchema("Address"), refactored.Schemas["Company"].Properties["address"])
+
+		// source not touched
+		assert.Equal(t, []string{"Company", "Role"}, slices.Sorted(maps.Keys(openapi.Schemas)))
+	})
+
+	t.Run("ignore docs with names suggested", func(t *testing.T) {
+		refactored, extracted, err := (&SchemaExtractor{
+			SchemaComparer: SchemaComparer{IgnoreDocs: true},
+			Name: func(s *Schema, pointers []string) string {
+				if _, ok := s.Properties["name"]; ok {
+					return "User"
+				}
+				return ""
+			},
+		}).Extract(openapi)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"Address", "Company", "Role", "User"}, slices.Sorted(maps.Keys(refactored.Schemas)))
+		assert.Equal(t, "User", extracted[0].Name)
+		assert.Equal(t, "new user", refactored.Schemas["User"].Description)
+		assert.Equal(t, refactored.RefSchema("Address"), refactored.Schemas["User"].Properties["address"])
+
+		resp := refactored.Paths.Paths["/users"].Operations.Operations[POST].Responses.Responses[200]
+		assert.Equal(t, refactored.RefSchema("User"), resp.Content["application/json"].Schema)
+	})
+
+	t.Run("min occurrences", func(t *testing.T) {
+		refactored, _, err := (&SchemaExtractor{MinOccurrences: 1}).Extract(openapi)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"Address", "Company", "CreateUserRequest", "CreateUserResponse", "ListUsersResponseItem", "Role"}, slices.Sorted(maps.Keys(refactored.Schemas)))
+	})
+}
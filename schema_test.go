@@ -122,5 +122,48 @@ func TestSchema(t *testing.T) {
 		).WithValidation(validation),
 	)
 
+	g.It("uuid", `{"type":"string","format":"uuid"}`, UUID())
+	g.It("email", `{"type":"string","format":"email"}`, Email())
+	g.It("uri", `{"type":"string","format":"uri"}`, URI())
+	g.It("hostname", `{"type":"string","format":"hostname"}`, Hostname())
+	g.It("ipv4", `{"type":"string","format":"ipv4"}`, IPv4())
+	g.It("ipv6", `{"type":"string","format":"ipv6"}`, IPv6())
+	g.It("int8", `{"type":"integer","format":"int8","maximum":127,"minimum":-128}`, Int8())
+	g.It("int16", `{"type":"integer","format":"int16","maximum":32767,"minimum":-32768}`, Int16())
+	g.It("uint8", `{"type":"integer","format":"uint8","maximum":255,"minimum":0}`, Uint8())
+	g.It("uint16", `{"type":"integer","format":"uint16","maximum":65535,"minimum":0}`, Uint16())
+	g.It("uint32", `{"type":"integer","format":"uint32","maximum":4294967295,"minimum":0}`, Uint32())
+	g.It("uint64", `{"type":"integer","format":"uint64","minimum":0}`, Uint64())
+
+	g.It("enum", `{"type":"string","enum":["cat","dog"]}`, Enum("cat", "dog"))
+	g.It("enum of mixed types", `{"enum":["1",true]}`, Enum("1", true))
+	g.It("enum of integers", `{"type":"integer","enum":[1,2]}`, Enum(1.0, 2.0))
+	g.It("enum of integers and numbers", `{"type":"number","enum":[1,1.5,2]}`, Enum(1.0, 1.5, 2.0))
+	g.It("enum of numbers and mixed types", `{"enum":[1.5,2,"3"]}`, Enum(1.5, 2.0, "3"))
+	g.It("const", `{"type":"boolean","enum":[true]}`, Const(true))
+
+	g.It(
+		"with validation helpers",
+		`{"type":"string","maxLength":10,"minLength":1,"pattern":"^[a-z]+$","default":"a","nullable":true,"example":"abc"}`,
+		String().WithMinLength(1).WithMaxLength(10).WithPattern("^[a-z]+$").WithDefault("a").WithExample("abc").WithNullable(),
+	)
+	g.It(
+		"with bounds",
+		`{"type":"number","multipleOf":0.5,"maximum":10,"exclusiveMaximum":true,"minimum":1}`,
+		NewSchema(TypeNumber, "").WithExclusiveMin(0).WithMin(1).WithExclusiveMax(10).WithMultipleOf(0.5),
+	)
+	g.It(
+		"with items and properties validation",
+		`{"type":"object","properties":{"tags":{"type":"array","items":{"type":"string"},"maxItems":3,"minItems":1,"uniqueItems":true}},"maxProperties":2,"minProperties":1,"required":["tags"]}`,
+		ObjectOf(Props{
+			"tags": ItemsOf(String()).WithMinItems(1).WithMaxItems(3).WithUniqueItems(),
+		}).WithRequired("tags", "tags").WithMinProperties(1).WithMaxProperties(2),
+	)
+	g.It(
+		"with enum",
+		`{"type":"string","format":"color","enum":["red"],"readOnly":true,"deprecated":true}`,
+		String().WithFormat("color").WithEnum("blue").WithConst("red").WithReadOnly().WithDeprecated(),
+	)
+
 	g.Run(t)
 }
@@ -0,0 +1,264 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+type ValidationError struct {
+	Pointer string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s: %s", pointer, e.Message)
+}
+
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	buf := bytes.NewBuffer(nil)
+	for i, e := range errs {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(e.Error())
+	}
+	return buf.String()
+}
+
+// ValidateValue validates the json decoded value by the schema, refs of schema resolved by components.
+// values not decoded from json, like structs, will be marshaled and unmarshaled first.
+func (object *ComponentsObject) ValidateValue(s *Schema, value interface{}) error {
+	v, err := normalizeJSONValue(value)
+	if err != nil {
+		return err
+	}
+	errs := object.validate(s, v, "", 0)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func normalizeJSONValue(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case nil, bool, float64, string:
+		return value, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (object *ComponentsObject) validate(s *Schema, v interface{}, pointer string, depth int) ValidationErrors {
+	if s == nil {
+		return nil
+	}
+
+	if depth > maxRefDepth*4 {
+		return nil
+	}
+
+	if s.Refer != nil {
+		resolved := object.ResolveSchema(s)
+		if resolved == nil {
+			return ValidationErrors{{Pointer: pointer, Message: fmt.Sprintf("unresolved ref %s", s.Refer.RefString())}}
+		}
+		s = resolved
+	}
+
+	errs := ValidationErrors{}
+	report := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, sub := range s.AllOf {
+		errs = append(errs, object.validate(sub, v, pointer, depth+1)...)
+	}
+
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if len(object.validate(sub, v, pointer, depth+1)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			report("should match any of schemas")
+		}
+	}
+
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, sub := range s.OneOf {
+			if len(object.validate(sub, v, pointer, depth+1)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			report("should match exactly one of schemas, but matched %d", matched)
+		}
+	}
+
+	if s.Not != nil && len(object.validate(s.Not, v, pointer, depth+1)) == 0 {
+		report("should not match the schema")
+	}
+
+	if v == nil {
+		if s.Type != "" && !s.Nullable {
+			report("should not be null")
+		}
+		return errs
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if ev, err := normalizeJSONValue(e); err == nil && reflect.DeepEqual(ev, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report("should be one of %v", s.Enum)
+		}
+	}
+
+	switch x := v.(type) {
+	case bool:
+		if s.Type != "" && s.Type != TypeBoolean {
+			report("should be %s, but got boolean", s.Type)
+		}
+	case float64:
+		switch s.Type {
+		case "", TypeNumber:
+		case TypeInteger:
+			if x != math.Trunc(x) {
+				report("should be integer, but got %s", strconv.FormatFloat(x, 'f', -1, 64))
+			}
+		default:
+			report("should be %s, but got number", s.Type)
+			return errs
+		}
+		object.validateNumber(s, x, report)
+	case string:
+		if s.Type != "" && s.Type != TypeString {
+			report("should be %s, but got string", s.Type)
+			return errs
+		}
+		length := uint64(utf8.RuneCountInString(x))
+		if s.MaxLength != nil && length > *s.MaxLength {
+			report("length should be less than or equal to %d", *s.MaxLength)
+		}
+		if s.MinLength != nil && length < *s.MinLength {
+			report("length should be greater than or equal to %d", *s.MinLength)
+		}
+		if s.Pattern != "" {
+			re, err := regexp.Compile(s.Pattern)
+			if err != nil {
+				report("invalid pattern %q", s.Pattern)
+			} else if !re.MatchString(x) {
+				report("should match pattern %q", s.Pattern)
+			}
+		}
+	case []interface{}:
+		if s.Type != "" && s.Type != TypeArray {
+			report("should be %s, but got array", s.Type)
+			return errs
+		}
+		length := uint64(len(x))
+		if s.MaxItems != nil && length > *s.MaxItems {
+			report("items should be less than or equal to %d", *s.MaxItems)
+		}
+		if s.MinItems != nil && length < *s.MinItems {
+			report("items should be greater than or equal to %d", *s.MinItems)
+		}
+		if s.UniqueItems {
+			for i := range x {
+				for j := i + 1; j < len(x); j++ {
+					if reflect.DeepEqual(x[i], x[j]) {
+						report("items should be unique, but %d and %d are equal", i, j)
+					}
+				}
+			}
+		}
+		for i := range x {
+			errs = append(errs, object.validate(s.Items, x[i], pointer+"/"+strconv.Itoa(i), depth+1)...)
+		}
+	case map[string]interface{}:
+		if s.Type != "" && s.Type != TypeObject {
+			report("should be %s, but got object", s.Type)
+			return errs
+		}
+		length := uint64(len(x))
+		if s.MaxProperties != nil && length > *s.MaxProperties {
+			report("properties should be less than or equal to %d", *s.MaxProperties)
+		}
+		if s.MinProperties != nil && length < *s.MinProperties {
+			report("properties should be greater than or equal to %d", *s.MinProperties)
+		}
+		for _, name := range s.Required {
+			if _, ok := x[name]; !ok {
+				errs = append(errs, &ValidationError{Pointer: pointer + "/" + escapeJSONPointer(name), Message: "required"})
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(x)) {
+			p := pointer + "/" + escapeJSONPointer(key)
+			if propSchema, ok := s.Properties[key]; ok {
+				errs = append(errs, object.validate(propSchema, x[key], p, depth+1)...)
+				continue
+			}
+			if s.AdditionalProperties != nil {
+				if s.AdditionalProperties.Schema != nil {
+					errs = append(errs, object.validate(s.AdditionalProperties.Schema, x[key], p, depth+1)...)
+				} else if !s.AdditionalProperties.Allows {
+					errs = append(errs, &ValidationError{Pointer: p, Message: "additional property not allowed"})
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+func (object *ComponentsObject) validateNumber(s *Schema, x float64, report func(format string, args ...interface{})) {
+	if s.Maximum != nil {
+		if s.ExclusiveMaximum && x >= *s.Maximum {
+			report("should be less than %v", *s.Maximum)
+		} else if x > *s.Maximum {
+			report("should be less than or equal to %v", *s.Maximum)
+		}
+	}
+	if s.Minimum != nil {
+		if s.ExclusiveMinimum && x <= *s.Minimum {
+			report("should be greater than %v", *s.Minimum)
+		} else if x < *s.Minimum {
+			report("should be greater than or equal to %v", *s.Minimum)
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf != 0 {
+		if q := x / *s.MultipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+			report("should be multiple of %v", *s.MultipleOf)
+		}
+	}
+}
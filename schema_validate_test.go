@@ -0,0 +1,76 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/go-courier/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValue(t *testing.T) {
+	components := &Components{}
+	components.AddSchema("Tag", String().WithValidation(&SchemaValidation{
+		MinLength: ptr.Uint64(1),
+		MaxLength: ptr.Uint64(3),
+		Pattern:   "^[a-z]+$",
+	}))
+
+	pet := ObjectOf(Props{
+		"id":   Long().WithValidation(&SchemaValidation{Minimum: ptr.Float64(1)}),
+		"name": String(),
+		"tags": ItemsOf(components.RefSchema("Tag")).WithValidation(&SchemaValidation{UniqueItems: true, MaxItems: ptr.Uint64(2)}),
+		"kind": String().WithValidation(&SchemaValidation{Enum: []interface{}{"cat", "dog"}}),
+	}, "id", "name")
+	pet.AdditionalProperties = &SchemaOrBool{}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, components.ValidateValue(pet, map[string]interface{}{
+			"id":   1,
+			"name": "kitty",
+			"tags": []string{"a", "b"},
+			"kind": "cat",
+		}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		err := components.ValidateValue(pet, map[string]interface{}{
+			"id":    0.5,
+			"tags":  []string{"ab", "ab", "ABCD"},
+			"kind":  "bird",
+			"extra": true,
+		})
+
+		assert.Equal(t, `/name: required
+/extra: additional property not allowed
+/id: should be integer, but got 0.5
+/id: should be greater than or equal to 1
+/kind: should be one of [cat dog]
+/tags: items should be less than or equal to 2
+/tags: items should be unique, but 0 and 1 are equal
+/tags/2: length should be less than or equal to 3
+/tags/2: should match pattern "^[a-z]+$"`, err.Error())
+	})
+
+	t.Run("composition", func(t *testing.T) {
+		s := OneOf(String(), Integer())
+		assert.NoError(t, components.ValidateValue(s, "1"))
+		assert.Error(t, components.ValidateValue(s, true))
+
+		assert.Error(t, components.ValidateValue(OneOf(Double(), Integer()), 1))
+		assert.NoError(t, components.ValidateValue(AnyOf(Double(), Integer()), 1))
+		assert.Error(t, components.ValidateValue(Not(String()), "1"))
+		assert.Error(t, components.ValidateValue(AllOf(String(), Integer()), "1"))
+	})
+
+	t.Run("nullable", func(t *testing.T) {
+		s := String()
+		assert.Error(t, components.ValidateValue(s, nil))
+		s.Nullable = true
+		assert.NoError(t, components.ValidateValue(s, nil))
+	})
+
+	t.Run("unresolved ref", func(t *testing.T) {
+		assert.NoError(t, components.ValidateValue(components.RefSchema("Tag"), "a"))
+		assert.EqualError(t, components.ValidateValue(RefSchema("#/components/schemas/Unknown"), "a"), "/: unresolved ref #/components/schemas/Unknown")
+	})
+}
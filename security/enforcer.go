@@ -0,0 +1,370 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-courier/oas"
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrNoVerifier         = errors.New("no verifier")
+	ErrNoOperation        = errors.New("no operation matched")
+)
+
+// Authentication holds the satisfied requirement and the principals of each scheme of it.
+type Authentication struct {
+	Requirement oas.SecurityRequirement
+	Principals  map[string]*Principal
+}
+
+type contextKeyAuthentication struct{}
+
+func ContextWithAuthentication(ctx context.Context, a *Authentication) context.Context {
+	return context.WithValue(ctx, contextKeyAuthentication{}, a)
+}
+
+// AuthenticationFromContext returns the authentication put by Enforcer,
+// it is nil when the operation allows anonymous access.
+func AuthenticationFromContext(ctx context.Context) *Authentication {
+	if a, ok := ctx.Value(contextKeyAuthentication{}).(*Authentication); ok {
+		return a
+	}
+	return nil
+}
+
+// Error collects why each requirement not satisfied.
+type Error struct {
+	// Forbidden is true when any requirement is authenticated but scopes not granted
+	Forbidden bool
+	Reasons   []error
+}
+
+func (e *Error) StatusCode() int {
+	if e.Forbidden {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
+func (e *Error) Error() string {
+	reasons := make([]string, len(e.Reasons))
+	for i := range e.Reasons {
+		reasons[i] = e.Reasons[i].Error()
+	}
+	return fmt.Sprintf("security requirements not satisfied: %s", strings.Join(reasons, "; "))
+}
+
+type SchemeError struct {
+	Scheme string
+	Err    error
+}
+
+func (e *SchemeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *SchemeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Scheme, e.Err)
+}
+
+type ScopeError struct {
+	Scheme  string
+	Missing []string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("%s: scopes not granted: %s", e.Scheme, strings.Join(e.Missing, ", "))
+}
+
+// Enforcer checks security requirements of operations, by credentials from requests and the verifiers of scheme types.
+// Schemes in one requirement must be all satisfied, and any of requirements could be satisfied.
+type Enforcer struct {
+	OpenAPI *oas.OpenAPI
+
+	APIKey        APIKeyVerifier
+	Basic         BasicVerifier
+	Bearer        BearerVerifier
+	OAuth2        OAuth2Verifier
+	OpenIDConnect OpenIDConnectVerifier
+
+	// FindOperation resolves operation of request, matches paths of OpenAPI by default.
+	FindOperation func(r *http.Request) *oas.Operation
+	// BasePaths are prefixes stripped from request paths before matching, paths of root servers by default.
+	BasePaths []string
+	// AllowUnmatched passes requests not matched any operation to the next handler without checks,
+	// they are denied by default.
+	AllowUnmatched bool
+	// OnError writes the response when requirements not satisfied, writes 401 or 403 by default.
+	OnError func(rw http.ResponseWriter, r *http.Request, err *Error)
+
+	// paths of OpenAPI compiled once on the first request
+	compileOnce sync.Once
+	matcher     *oas.PathMatcher
+}
+
+func (e *Enforcer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		op := e.findOperation(r)
+		if op == nil && e.AllowUnmatched {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		var a *Authentication
+		var err *Error
+		if op == nil {
+			err = &Error{Forbidden: true, Reasons: []error{ErrNoOperation}}
+		} else {
+			a, err = e.Authenticate(r, e.OpenAPI.EffectiveSecurity(op))
+		}
+		if err != nil {
+			if e.OnError != nil {
+				e.OnError(rw, r, err)
+				return
+			}
+			http.Error(rw, err.Error(), err.StatusCode())
+			return
+		}
+
+		if a != nil {
+			r = r.WithContext(ContextWithAuthentication(r.Context(), a))
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// findOperation matches the decoded request path, with and then without base paths,
+// HEAD requests fall back to GET operations as http.ServeMux does.
+func (e *Enforcer) findOperation(r *http.Request) *oas.Operation {
+	if e.FindOperation != nil {
+		return e.FindOperation(r)
+	}
+
+	e.compileOnce.Do(func() {
+		e.matcher = e.OpenAPI.Paths.Matcher()
+	})
+
+	for _, path := range e.candidatePaths(r.URL.Path) {
+		op, _, _ := e.matcher.FindOperation(r.Method, path)
+		if op == nil && r.Method == http.MethodHead {
+			op, _, _ = e.matcher.FindOperation(http.MethodGet, path)
+		}
+		if op != nil {
+			return op
+		}
+	}
+	return nil
+}
+
+func (e *Enforcer) candidatePaths(path string) []string {
+	paths := make([]string, 0)
+	for _, base := range e.basePaths() {
+		if rest, ok := strings.CutPrefix(path, base); ok && (rest == "" || rest[0] == '/') {
+			paths = append(paths, "/"+strings.TrimPrefix(rest, "/"))
+		}
+	}
+	return append(paths, path)
+}
+
+func (e *Enforcer) basePaths() []string {
+	bases := e.BasePaths
+	if bases == nil {
+		for _, s := range e.OpenAPI.Servers {
+			if u, err := s.Expand(nil); err == nil {
+				bases = append(bases, u.Path)
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(bases))
+	for _, base := range bases {
+		if base = strings.TrimRight(base, "/"); base != "" {
+			paths = append(paths, base)
+		}
+	}
+	// longest first
+	sort.Slice(paths, func(i, j int) bool { return len(paths[i]) > len(paths[j]) })
+	return paths
+}
+
+// Authenticate returns the authentication of the first satisfied requirement,
+// nil authentication without error means anonymous access allowed.
+func (e *Enforcer) Authenticate(r *http.Request, requirements []*oas.SecurityRequirement) (*Authentication, *Error) {
+	if len(requirements) == 0 {
+		return nil, nil
+	}
+
+	failed := &Error{}
+	anonymous := false
+
+	for _, requirement := range requirements {
+		if requirement == nil || len(*requirement) == 0 {
+			anonymous = true
+			continue
+		}
+
+		a, err := e.authenticateRequirement(r, *requirement)
+		if err == nil {
+			return a, nil
+		}
+
+		var scopeErr *ScopeError
+		if errors.As(err, &scopeErr) {
+			failed.Forbidden = true
+		}
+		failed.Reasons = append(failed.Reasons, err)
+	}
+
+	if anonymous {
+		return nil, nil
+	}
+
+	return nil, failed
+}
+
+func (e *Enforcer) authenticateRequirement(r *http.Request, requirement oas.SecurityRequirement) (*Authentication, error) {
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	a := &Authentication{
+		Requirement: requirement,
+		Principals:  map[string]*Principal{},
+	}
+
+	for _, name := range names {
+		scheme := e.OpenAPI.SecuritySchemes[name]
+		if scheme == nil {
+			return nil, &SchemeError{Scheme: name, Err: errors.New("security scheme not defined")}
+		}
+
+		p, err := e.verify(r, name, scheme)
+		if err != nil {
+			return nil, &SchemeError{Scheme: name, Err: err}
+		}
+
+		if scheme.Type == oas.SecurityTypeOAuth2 || scheme.Type == oas.SecurityTypeOpenIdConnect {
+			if missing := missingScopes(requirement[name], p.Scopes); len(missing) > 0 {
+				return nil, &ScopeError{Scheme: name, Missing: missing}
+			}
+		}
+
+		a.Principals[name] = p
+	}
+
+	return a, nil
+}
+
+func (e *Enforcer) verify(r *http.Request, name string, scheme *oas.SecurityScheme) (*Principal, error) {
+	ctx := r.Context()
+
+	switch scheme.Type {
+	case oas.SecurityTypeAPIKey:
+		key := apiKeyFrom(r, scheme.Name, scheme.In)
+		if key == "" {
+			return nil, ErrMissingCredentials
+		}
+		if e.APIKey == nil {
+			return nil, ErrNoVerifier
+		}
+		return nonNil(e.APIKey.VerifyAPIKey(ctx, name, scheme, key))
+	case oas.SecurityTypeHttp:
+		switch strings.ToLower(scheme.Scheme) {
+		case "basic":
+			username, password, ok := r.BasicAuth()
+			if !ok {
+				return nil, ErrMissingCredentials
+			}
+			if e.Basic == nil {
+				return nil, ErrNoVerifier
+			}
+			return nonNil(e.Basic.VerifyBasic(ctx, name, scheme, username, password))
+		case "bearer":
+			token := bearerTokenFrom(r)
+			if token == "" {
+				return nil, ErrMissingCredentials
+			}
+			if e.Bearer == nil {
+				return nil, ErrNoVerifier
+			}
+			return nonNil(e.Bearer.VerifyBearer(ctx, name, scheme, token))
+		}
+		return nil, fmt.Errorf("unsupported http scheme %q", scheme.Scheme)
+	case oas.SecurityTypeOAuth2:
+		token := bearerTokenFrom(r)
+		if token == "" {
+			return nil, ErrMissingCredentials
+		}
+		if e.OAuth2 == nil {
+			return nil, ErrNoVerifier
+		}
+		return nonNil(e.OAuth2.VerifyOAuth2(ctx, name, scheme, token))
+	case oas.SecurityTypeOpenIdConnect:
+		token := bearerTokenFrom(r)
+		if token == "" {
+			return nil, ErrMissingCredentials
+		}
+		if e.OpenIDConnect == nil {
+			return nil, ErrNoVerifier
+		}
+		return nonNil(e.OpenIDConnect.VerifyOpenIDConnect(ctx, name, scheme, token))
+	}
+
+	return nil, fmt.Errorf("unsupported security type %q", scheme.Type)
+}
+
+func nonNil(p *Principal, err error) (*Principal, error) {
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		p = &Principal{}
+	}
+	return p, nil
+}
+
+func apiKeyFrom(r *http.Request, name string, in oas.Position) string {
+	switch in {
+	case oas.PositionHeader:
+		return r.Header.Get(name)
+	case oas.PositionQuery:
+		return r.URL.Query().Get(name)
+	case oas.PositionCookie:
+		if c, err := r.Cookie(name); err == nil {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+func bearerTokenFrom(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+func missingScopes(required []string, granted []string) []string {
+	grantedSet := map[string]bool{}
+	for _, s := range granted {
+		grantedSet[s] = true
+	}
+	missing := make([]string, 0)
+	for _, s := range required {
+		if !grantedSet[s] {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,206 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOpenAPI() *oas.OpenAPI {
+	openapi := oas.NewOpenAPI()
+
+	openapi.AddSecurityScheme("key", oas.NewAPIKeySecurityScheme("api_key", oas.PositionQuery))
+	openapi.AddSecurityScheme("cookie", oas.NewAPIKeySecurityScheme("session", oas.PositionCookie))
+	openapi.AddSecurityScheme("basic", oas.NewHTTPSecurityScheme("basic", ""))
+	openapi.AddSecurityScheme("oauth", oas.NewOAuth2SecurityScheme(oas.OAuthFlowsObject{
+		ClientCredentials: oas.NewOAuthFlow("", "https://example.com/token", "", map[string]string{
+			"read":  "read",
+			"write": "write",
+		}),
+	}))
+
+	basic := openapi.RequireSecurity("basic")
+	openapi.AddSecurityRequirement(&basic)
+
+	openapi.AddOperation(oas.GET, "/pets", oas.NewOperation("listPets"))
+
+	{
+		op := oas.NewOperation("createPet")
+		write := openapi.RequireSecurity("oauth", "write")
+		keyAndCookie := oas.MergeSecurityRequirements(openapi.RequireSecurity("key"), openapi.RequireSecurity("cookie"))
+		op.AddSecurityRequirement(&write)
+		op.AddSecurityRequirement(&keyAndCookie)
+		openapi.AddOperation(oas.POST, "/pets", op)
+	}
+
+	{
+		op := oas.NewOperation("health")
+		op.DisableSecurity()
+		openapi.AddOperation(oas.GET, "/health", op)
+	}
+
+	{
+		op := oas.NewOperation("getPet")
+		read := openapi.RequireSecurity("oauth", "read")
+		op.AddSecurityRequirement(&read)
+		op.AddOptionalSecurity()
+		openapi.AddOperation(oas.GET, "/pets/{petId}", op)
+	}
+
+	return openapi
+}
+
+func newEnforcer() *Enforcer {
+	return &Enforcer{
+		OpenAPI: newOpenAPI(),
+		APIKey: APIKeyVerifierFunc(func(ctx context.Context, name string, scheme *oas.SecurityScheme, key string) (*Principal, error) {
+			if key != "secret" {
+				return nil, errors.New("invalid key")
+			}
+			return &Principal{Subject: name}, nil
+		}),
+		Basic: BasicVerifierFunc(func(ctx context.Context, name string, scheme *oas.SecurityScheme, username string, password string) (*Principal, error) {
+			if username != "admin" || password != "admin" {
+				return nil, errors.New("invalid password")
+			}
+			return &Principal{Subject: username}, nil
+		}),
+		OAuth2: OAuth2VerifierFunc(func(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error) {
+			if token == "reader" {
+				return &Principal{Subject: token, Scopes: []string{"read"}}, nil
+			}
+			return &Principal{Subject: token, Scopes: []string{"read", "write"}}, nil
+		}),
+	}
+}
+
+func serve(e *Enforcer, r *http.Request) (*httptest.ResponseRecorder, *Authentication) {
+	var a *Authentication
+	h := e.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		a = AuthenticationFromContext(r.Context())
+	}))
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, r)
+	return rw, a
+}
+
+func TestEnforcer(t *testing.T) {
+	e := newEnforcer()
+
+	t.Run("global requirement", func(t *testing.T) {
+		rw, _ := serve(e, httptest.NewRequest(http.MethodGet, "/pets", nil))
+		assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+		req.SetBasicAuth("admin", "admin")
+		rw, a := serve(e, req)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "admin", a.Principals["basic"].Subject)
+	})
+
+	t.Run("security disabled", func(t *testing.T) {
+		rw, a := serve(e, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Nil(t, a)
+
+		// not the path of the operation without security
+		for _, path := range []string{"/health/", "//health"} {
+			rw, _ = serve(e, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusForbidden, rw.Code, path)
+		}
+	})
+
+	t.Run("all schemes of requirement must be satisfied", func(t *testing.T) {
+		rw, _ := serve(e, httptest.NewRequest(http.MethodPost, "/pets?api_key=secret", nil))
+		assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+		req := httptest.NewRequest(http.MethodPost, "/pets?api_key=secret", nil)
+		req.AddCookie(&http.Cookie{Name: "session", Value: "secret"})
+		rw, a := serve(e, req)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Len(t, a.Principals, 2)
+	})
+
+	t.Run("scopes", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/pets", nil)
+		req.Header.Set("Authorization", "Bearer reader")
+		rw, _ := serve(e, req)
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/pets", nil)
+		req.Header.Set("Authorization", "Bearer writer")
+		rw, a := serve(e, req)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "writer", a.Principals["oauth"].Subject)
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		rw, a := serve(e, httptest.NewRequest(http.MethodGet, "/pets/1", nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Nil(t, a)
+
+		req := httptest.NewRequest(http.MethodGet, "/pets/1", nil)
+		req.Header.Set("Authorization", "Bearer reader")
+		_, a = serve(e, req)
+		assert.Equal(t, "reader", a.Principals["oauth"].Subject)
+	})
+
+	t.Run("missing verifier", func(t *testing.T) {
+		e := newEnforcer()
+		e.Basic = nil
+
+		req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+		req.SetBasicAuth("admin", "admin")
+
+		_, err := e.Authenticate(req, e.OpenAPI.Security)
+		assert.True(t, errors.Is(err.Reasons[0], ErrNoVerifier))
+	})
+
+	t.Run("encoded path", func(t *testing.T) {
+		rw, _ := serve(e, httptest.NewRequest(http.MethodGet, "/%70ets", nil))
+		assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	})
+
+	t.Run("head checked as get", func(t *testing.T) {
+		rw, _ := serve(e, httptest.NewRequest(http.MethodHead, "/pets", nil))
+		assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+		rw, _ = serve(e, httptest.NewRequest(http.MethodHead, "/health", nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("unknown operation denied", func(t *testing.T) {
+		rw, _ := serve(e, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+
+		rw, _ = serve(e, httptest.NewRequest(http.MethodDelete, "/pets", nil))
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+	})
+
+	t.Run("unknown operation allowed", func(t *testing.T) {
+		e := newEnforcer()
+		e.AllowUnmatched = true
+
+		rw, _ := serve(e, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("base path of servers", func(t *testing.T) {
+		e := newEnforcer()
+		e.OpenAPI.AddServer(oas.NewServer("https://api.example.com/api/v1/"))
+
+		rw, _ := serve(e, httptest.NewRequest(http.MethodGet, "/api/v1/pets", nil))
+		assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+		rw, _ = serve(e, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+
+		rw, _ = serve(e, httptest.NewRequest(http.MethodGet, "/api/v1pets", nil))
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+	})
+}
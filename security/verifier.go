@@ -0,0 +1,65 @@
+package security
+
+import (
+	"context"
+
+	"github.com/go-courier/oas"
+)
+
+// Principal is the authenticated identity returned by verifiers
+type Principal struct {
+	// Subject identifies who is authenticated, defined by verifiers
+	Subject interface{}
+	// Scopes granted, used to check scopes of oauth2 and openIdConnect requirements
+	Scopes []string
+}
+
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, name string, scheme *oas.SecurityScheme, key string) (*Principal, error)
+}
+
+type BasicVerifier interface {
+	VerifyBasic(ctx context.Context, name string, scheme *oas.SecurityScheme, username string, password string) (*Principal, error)
+}
+
+type BearerVerifier interface {
+	VerifyBearer(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error)
+}
+
+type OAuth2Verifier interface {
+	VerifyOAuth2(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error)
+}
+
+type OpenIDConnectVerifier interface {
+	VerifyOpenIDConnect(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error)
+}
+
+type APIKeyVerifierFunc func(ctx context.Context, name string, scheme *oas.SecurityScheme, key string) (*Principal, error)
+
+func (fn APIKeyVerifierFunc) VerifyAPIKey(ctx context.Context, name string, scheme *oas.SecurityScheme, key string) (*Principal, error) {
+	return fn(ctx, name, scheme, key)
+}
+
+type BasicVerifierFunc func(ctx context.Context, name string, scheme *oas.SecurityScheme, username string, password string) (*Principal, error)
+
+func (fn BasicVerifierFunc) VerifyBasic(ctx context.Context, name string, scheme *oas.SecurityScheme, username string, password string) (*Principal, error) {
+	return fn(ctx, name, scheme, username, password)
+}
+
+type BearerVerifierFunc func(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error)
+
+func (fn BearerVerifierFunc) VerifyBearer(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error) {
+	return fn(ctx, name, scheme, token)
+}
+
+type OAuth2VerifierFunc func(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error)
+
+func (fn OAuth2VerifierFunc) VerifyOAuth2(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error) {
+	return fn(ctx, name, scheme, token)
+}
+
+type OpenIDConnectVerifierFunc func(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error)
+
+func (fn OpenIDConnectVerifierFunc) VerifyOpenIDConnect(ctx context.Context, name string, scheme *oas.SecurityScheme, token string) (*Principal, error) {
+	return fn(ctx, name, scheme, token)
+}
@@ -15,7 +15,8 @@ func (o *WithSecuritySchemes) AddSecurityScheme(name string, ss *SecurityScheme)
 }
 
 type WithSecurityRequirement struct {
-	Security []*SecurityRequirement `json:"security,omitempty"`
+	// nil means not declared, and empty means no security required
+	Security []*SecurityRequirement `json:"security,omitzero"`
 }
 
 func (o *WithSecurityRequirement) AddSecurityRequirement(sr *SecurityRequirement) {
@@ -25,6 +26,61 @@ func (o *WithSecurityRequirement) AddSecurityRequirement(sr *SecurityRequirement
 	o.Security = append(o.Security, sr)
 }
 
+// AddOptionalSecurity adds an empty requirement, which makes the declared security optional
+func (o *WithSecurityRequirement) AddOptionalSecurity() {
+	o.Security = append(o.Security, &SecurityRequirement{})
+}
+
+// DisableSecurity declares `security: []`, which removes the top-level security declaration for an operation
+func (o *WithSecurityRequirement) DisableSecurity() {
+	o.Security = []*SecurityRequirement{}
+}
+
+func (o *WithSecurityRequirement) SecurityDeclared() bool {
+	return o.Security != nil
+}
+
+// EffectiveSecurity returns the security requirements apply to the operation,
+// the operation level declaration overrides the top-level one.
+// Any of the requirements could be satisfied, no requirements or an empty requirement means anonymous access allowed.
+func (o *OpenAPIObject) EffectiveSecurity(op *Operation) []*SecurityRequirement {
+	if op != nil && op.SecurityDeclared() {
+		return op.Security
+	}
+	return o.Security
+}
+
+// SecurityRequired returns false when no requirements or any requirement is empty.
+func SecurityRequired(requirements []*SecurityRequirement) bool {
+	if len(requirements) == 0 {
+		return false
+	}
+	for _, sr := range requirements {
+		if sr == nil || len(*sr) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeSecurityRequirements merges requirements into one, all schemes of which must be satisfied.
+// returns nil when any of requirements is nil.
+func MergeSecurityRequirements(requirements ...SecurityRequirement) SecurityRequirement {
+	merged := SecurityRequirement{}
+	for _, sr := range requirements {
+		if sr == nil {
+			return nil
+		}
+		for id := range sr {
+			if merged[id] == nil {
+				merged[id] = []string{}
+			}
+			merged[id] = append(merged[id], sr[id]...)
+		}
+	}
+	return merged
+}
+
 type SecurityRequirement map[string][]string
 
 func NewAPIKeySecurityScheme(name string, in Position) *SecurityScheme {
@@ -75,6 +131,12 @@ type SecurityScheme struct {
 	SpecExtensions
 }
 
+func (i SecurityScheme) WithDesc(desc string) *SecurityScheme {
+	c := i.Clone()
+	c.Description = desc
+	return c
+}
+
 func (i SecurityScheme) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.SecuritySchemeObject, i.SpecExtensions)
 }
@@ -139,6 +201,21 @@ type OAuthFlow struct {
 	SpecExtensions
 }
 
+func (i OAuthFlow) WithScope(scope string, desc string) *OAuthFlow {
+	c := i.Clone()
+	if c.Scopes == nil {
+		c.Scopes = map[string]string{}
+	}
+	c.Scopes[scope] = desc
+	return c
+}
+
+func (i OAuthFlow) WithRefreshURL(url string) *OAuthFlow {
+	c := i.Clone()
+	c.RefreshURL = url
+	return c
+}
+
 func (i OAuthFlow) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.OAuthFlowObject, i.SpecExtensions)
 }
@@ -2,6 +2,8 @@ package oas
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSecurityScheme(t *testing.T) {
@@ -44,6 +46,14 @@ func TestSecurityScheme(t *testing.T) {
 		return server
 	}())
 
+	g.It("with builders", `{"type":"oauth2","description":"desc","flows":{"clientCredentials":{"authorizationUrl":"","tokenUrl":"https://example.com/api/oauth/token","refreshUrl":"https://example.com/api/oauth/refresh","scopes":{"read:pets":"read your pets"}}}}`,
+		NewOAuth2SecurityScheme(OAuthFlowsObject{
+			ClientCredentials: NewOAuthFlow("", "https://example.com/api/oauth/token", "", nil).
+				WithRefreshURL("https://example.com/api/oauth/refresh").
+				WithScope("read:pets", "read your pets"),
+		}).WithDesc("desc"),
+	)
+
 	g.Run(t)
 }
 
@@ -63,3 +73,44 @@ func TestSecurityRequirement(t *testing.T) {
 
 	g.Run(t)
 }
+
+func TestEffectiveSecurity(t *testing.T) {
+	openapi := NewOpenAPI()
+	openapi.AddSecurityScheme("token", NewHTTPSecurityScheme("bearer", "JWT"))
+	openapi.AddSecurityScheme("oauth", NewOAuth2SecurityScheme(OAuthFlowsObject{}))
+
+	token := openapi.RequireSecurity("token")
+	openapi.AddSecurityRequirement(&token)
+
+	g := NewCaseGroup("EffectiveSecurity")
+
+	inherited := NewOperation("inherited")
+	g.It("inherited", `{"operationId":"inherited","responses":{}}`, inherited)
+
+	disabled := NewOperation("disabled")
+	disabled.DisableSecurity()
+	g.It("disabled", `{"operationId":"disabled","responses":{},"security":[]}`, disabled)
+
+	optional := NewOperation("optional")
+	optional.AddSecurityRequirement(&token)
+	optional.AddOptionalSecurity()
+	g.It("optional", `{"operationId":"optional","responses":{},"security":[{"token":[]},{}]}`, optional)
+
+	both := NewOperation("both")
+	sr := MergeSecurityRequirements(openapi.RequireSecurity("token"), openapi.RequireSecurity("oauth", "read"))
+	both.AddSecurityRequirement(&sr)
+	g.It("both", `{"operationId":"both","responses":{},"security":[{"oauth":["read"],"token":[]}]}`, both)
+
+	g.Run(t)
+
+	assert.Equal(t, openapi.Security, openapi.EffectiveSecurity(inherited))
+	assert.True(t, SecurityRequired(openapi.EffectiveSecurity(inherited)))
+
+	assert.Equal(t, []*SecurityRequirement{}, openapi.EffectiveSecurity(disabled))
+	assert.False(t, SecurityRequired(openapi.EffectiveSecurity(disabled)))
+
+	assert.Len(t, openapi.EffectiveSecurity(optional), 2)
+	assert.False(t, SecurityRequired(openapi.EffectiveSecurity(optional)))
+
+	assert.Nil(t, MergeSecurityRequirements(openapi.RequireSecurity("token"), openapi.RequireSecurity("not_found")))
+}
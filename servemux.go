@@ -0,0 +1,203 @@
+package oas
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ServeMuxPattern is parsed pattern of http.ServeMux since go 1.22, like "GET example.com/pets/{id}"
+type ServeMuxPattern struct {
+	// Method is empty when the pattern matches all methods
+	Method HttpMethod
+	Host   string
+	// Path is the path template, {path...} converted to {path}, and {$} dropped
+	Path string
+	// Params are names of wildcards by order
+	Params []string
+	// Remainder is the name of the last wildcard matches the rest of path, like {path...}
+	Remainder string
+}
+
+// ParseServeMuxPattern parses pattern like "[METHOD ][HOST]/[PATH]"
+func ParseServeMuxPattern(pattern string) (*ServeMuxPattern, error) {
+	p := &ServeMuxPattern{}
+
+	rest := strings.TrimSpace(pattern)
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		method := HttpMethod(strings.ToLower(rest[:i]))
+		if !httpMethods[method] {
+			return nil, fmt.Errorf("pattern %q: invalid method %q", pattern, rest[:i])
+		}
+		p.Method = method
+		rest = strings.TrimLeft(rest[i:], " \t")
+	}
+
+	i := strings.Index(rest, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("pattern %q: host/path missing /", pattern)
+	}
+	p.Host, rest = rest[:i], rest[i:]
+
+	segments := strings.Split(rest[1:], "/")
+	seen := map[string]bool{}
+	b := strings.Builder{}
+
+	for i, seg := range segments {
+		if !strings.Contains(seg, "{") {
+			b.WriteString("/" + seg)
+			continue
+		}
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			return nil, fmt.Errorf("pattern %q: wildcard should be full path segment", pattern)
+		}
+
+		name := seg[1 : len(seg)-1]
+		if name == "$" {
+			if i != len(segments)-1 {
+				return nil, fmt.Errorf("pattern %q: {$} should be at end", pattern)
+			}
+			b.WriteString("/")
+			continue
+		}
+		if strings.HasSuffix(name, "...") {
+			if i != len(segments)-1 {
+				return nil, fmt.Errorf("pattern %q: %s should be at end", pattern, seg)
+			}
+			name = strings.TrimSuffix(name, "...")
+			p.Remainder = name
+		}
+		if !isGoIdentifier(name) {
+			return nil, fmt.Errorf("pattern %q: invalid wildcard name %q", pattern, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("pattern %q: duplicate wildcard name %q", pattern, name)
+		}
+		seen[name] = true
+		p.Params = append(p.Params, name)
+		b.WriteString("/{" + name + "}")
+	}
+
+	p.Path = b.String()
+	return p, nil
+}
+
+func isGoIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ServeMuxRoute is the route registered to http.ServeMux, with optional metadata of the handler
+type ServeMuxRoute struct {
+	Pattern     string
+	OperationId string
+	Summary     string
+	Description string
+	Tags        []string
+}
+
+// ServeMuxSkeleton builds openapi of the routes, with path parameters of wildcards,
+// and operationId generated from method and path when not provided.
+// Routes without method are documented as GET.
+func ServeMuxSkeleton(routes ...*ServeMuxRoute) (*OpenAPI, error) {
+	openapi := NewOpenAPI()
+
+	for _, r := range routes {
+		p, err := ParseServeMuxPattern(r.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		method := p.Method
+		if method == "" {
+			method = GET
+		}
+		if item := openapi.Paths.Paths[p.Path]; item != nil && item.Operations.Operations[method] != nil {
+			return nil, fmt.Errorf("pattern %q: conflicts with %s %s", r.Pattern, strings.ToUpper(string(method)), p.Path)
+		}
+
+		op := NewOperation(r.OperationId)
+		if op.OperationId == "" {
+			op.OperationId = serveMuxOperationId(method, p)
+		}
+		op.Summary = r.Summary
+		op.Description = r.Description
+		op.Tags = append(op.Tags, r.Tags...)
+
+		for _, name := range p.Params {
+			param := PathParameter(name, String())
+			if name == p.Remainder {
+				param = param.WithDesc("rest of the path, slashes included")
+			}
+			op.AddParameter(param)
+		}
+		if p.Host != "" {
+			op.AddServer(NewServer("//" + p.Host))
+		}
+		op.SetDefaultResponse(NewResponse("default response"))
+
+		openapi.AddOperation(method, p.Path, op)
+	}
+
+	return openapi, nil
+}
+
+// serveMuxOperationId generates operationId like getPetsById from "GET /pets/{id}"
+func serveMuxOperationId(method HttpMethod, p *ServeMuxPattern) string {
+	b := strings.Builder{}
+	b.WriteString(string(method))
+	for _, seg := range strings.Split(p.Path, "/") {
+		if strings.HasPrefix(seg, "{") {
+			b.WriteString("By")
+			seg = strings.Trim(seg, "{}")
+		}
+		b.WriteString(pascalCase(seg))
+	}
+	return b.String()
+}
+
+// UndocumentedRoutes returns patterns of routes not documented in openapi.
+// Paths matched ignoring names of parameters, and routes without method are documented by any operation of the path.
+func (o *OpenAPI) UndocumentedRoutes(patterns ...string) ([]string, error) {
+	documented := map[string]map[HttpMethod]bool{}
+	for path, item := range o.Paths.Paths {
+		if item == nil {
+			continue
+		}
+		key := pathShape(path)
+		if documented[key] == nil {
+			documented[key] = map[HttpMethod]bool{}
+		}
+		for method := range item.Operations.Operations {
+			documented[key][method] = true
+		}
+	}
+
+	undocumented := make([]string, 0)
+	for _, pattern := range patterns {
+		p, err := ParseServeMuxPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		methods := documented[pathShape(p.Path)]
+		if p.Method == "" && len(methods) > 0 {
+			continue
+		}
+		if methods[p.Method] || (p.Method == HEAD && methods[GET]) {
+			continue
+		}
+		undocumented = append(undocumented, pattern)
+	}
+	return undocumented, nil
+}
+
+// pathShape replaces parameters of path template with {}
+func pathShape(path string) string {
+	return reParamInPath.ReplaceAllString(path, "{}")
+}
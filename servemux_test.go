@@ -0,0 +1,109 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseServeMuxPattern(t *testing.T) {
+	t.Run("full", func(t *testing.T) {
+		p, err := ParseServeMuxPattern("GET example.com/pets/{id}/files/{path...}")
+		assert.NoError(t, err)
+		assert.Equal(t, &ServeMuxPattern{
+			Method:    GET,
+			Host:      "example.com",
+			Path:      "/pets/{id}/files/{path}",
+			Params:    []string{"id", "path"},
+			Remainder: "path",
+		}, p)
+	})
+
+	t.Run("without method", func(t *testing.T) {
+		p, err := ParseServeMuxPattern("/pets/")
+		assert.NoError(t, err)
+		assert.Equal(t, HttpMethod(""), p.Method)
+		assert.Equal(t, "/pets/", p.Path)
+	})
+
+	t.Run("exact match", func(t *testing.T) {
+		p, err := ParseServeMuxPattern("POST /pets/{$}")
+		assert.NoError(t, err)
+		assert.Equal(t, POST, p.Method)
+		assert.Equal(t, "/pets/", p.Path)
+		assert.Empty(t, p.Params)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, pattern := range []string{
+			"FETCH /pets",
+			"GET pets",
+			"GET /pets/id-{id}",
+			"GET /pets/{$}/x",
+			"GET /files/{path...}/x",
+			"GET /pets/{1id}",
+			"GET /pets/{id}/{id}",
+		} {
+			_, err := ParseServeMuxPattern(pattern)
+			assert.Error(t, err, pattern)
+		}
+	})
+}
+
+func TestServeMuxSkeleton(t *testing.T) {
+	t.Run("skeleton", func(t *testing.T) {
+		openapi, err := ServeMuxSkeleton(
+			&ServeMuxRoute{Pattern: "GET /pets/{id}"},
+			&ServeMuxRoute{Pattern: "post /pets/{$}", OperationId: "createPet", Summary: "create pet", Tags: []string{"pets"}},
+			&ServeMuxRoute{Pattern: "/static/{path...}"},
+			&ServeMuxRoute{Pattern: "PUT api.example.com/pets/{id}"},
+		)
+		assert.NoError(t, err)
+
+		op := openapi.Paths.Paths["/pets/{id}"].Operations.Operations[GET]
+		assert.Equal(t, "getPetsById", op.OperationId)
+		assert.Equal(t, []*Parameter{PathParameter("id", String())}, op.Parameters)
+		assert.NotNil(t, op.Responses.Default)
+
+		op = openapi.Paths.Paths["/pets/"].Operations.Operations[POST]
+		assert.Equal(t, "createPet", op.OperationId)
+		assert.Equal(t, "create pet", op.Summary)
+		assert.Equal(t, []string{"pets"}, op.Tags)
+
+		op = openapi.Paths.Paths["/static/{path}"].Operations.Operations[GET]
+		assert.Equal(t, "getStaticByPath", op.OperationId)
+		assert.Equal(t, "rest of the path, slashes included", op.Parameters[0].Description)
+
+		op = openapi.Paths.Paths["/pets/{id}"].Operations.Operations[PUT]
+		assert.Equal(t, "putPetsById", op.OperationId)
+		assert.Equal(t, "//api.example.com", op.Servers[0].URL)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		_, err := ServeMuxSkeleton(
+			&ServeMuxRoute{Pattern: "GET /pets"},
+			&ServeMuxRoute{Pattern: "/pets"},
+		)
+		assert.Error(t, err)
+	})
+}
+
+func TestUndocumentedRoutes(t *testing.T) {
+	openapi := NewOpenAPI()
+	openapi.AddOperation(GET, "/pets/{petId}", NewOperation("getPet"))
+	openapi.AddOperation(POST, "/pets", NewOperation("createPet"))
+
+	undocumented, err := openapi.UndocumentedRoutes(
+		"GET /pets/{id}",
+		"HEAD /pets/{id}",
+		"DELETE /pets/{id}",
+		"/pets",
+		"/users",
+		"GET /pets/{id}/photos",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DELETE /pets/{id}", "/users", "GET /pets/{id}/photos"}, undocumented)
+
+	_, err = openapi.UndocumentedRoutes("GET pets")
+	assert.Error(t, err)
+}
@@ -1,5 +1,13 @@
 package oas
 
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+)
+
 func NewServer(url string) *Server {
 	s := &Server{}
 	s.URL = url
@@ -22,6 +30,18 @@ type Server struct {
 	SpecExtensions
 }
 
+func (i Server) WithDesc(desc string) *Server {
+	c := i.Clone()
+	c.Description = desc
+	return c
+}
+
+func (i Server) WithVariable(key string, v *ServerVariable) *Server {
+	c := i.Clone()
+	c.AddVariable(key, v.Clone())
+	return c
+}
+
 func (i Server) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.ServerObject, i.SpecExtensions)
 }
@@ -59,6 +79,18 @@ type ServerVariable struct {
 	SpecExtensions
 }
 
+func (i ServerVariable) WithDesc(desc string) *ServerVariable {
+	c := i.Clone()
+	c.Description = desc
+	return c
+}
+
+func (i ServerVariable) WithEnum(values ...string) *ServerVariable {
+	c := i.Clone()
+	c.Enum = append([]string{}, values...)
+	return c
+}
+
 func (i ServerVariable) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.ServerVariableObject, i.SpecExtensions)
 }
@@ -72,3 +104,102 @@ type ServerVariableObject struct {
 	Enum        []string `json:"enum,omitempty"`
 	Description string   `json:"description,omitempty"`
 }
+
+// VariableNames returns names of variables in url template by order
+func (o *ServerObject) VariableNames() []string {
+	return PathParamNames(o.URL)
+}
+
+// Validate checks the variables used in url template are all defined,
+// the defined variables are all used, and defaults are in enum.
+func (o *ServerObject) Validate() error {
+	errs := make([]error, 0)
+
+	used := map[string]bool{}
+	for _, name := range o.VariableNames() {
+		used[name] = true
+		if o.Variables[name] == nil {
+			errs = append(errs, fmt.Errorf("server variable {%s} is not defined", name))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(o.Variables)) {
+		if !used[name] {
+			errs = append(errs, fmt.Errorf("server variable %q is never used", name))
+		}
+		if v := o.Variables[name]; v != nil {
+			if err := v.Check(v.Default); err != nil {
+				errs = append(errs, fmt.Errorf("default of server variable %q: %w", name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Expand expands url template by overrides or defaults of variables.
+func (o *ServerObject) Expand(overrides map[string]string) (*url.URL, error) {
+	errs := make([]error, 0)
+
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		if o.Variables[name] == nil {
+			errs = append(errs, fmt.Errorf("server variable %q is not defined", name))
+		}
+	}
+
+	expanded := reParamInPath.ReplaceAllStringFunc(o.URL, func(s string) string {
+		name := s[1 : len(s)-1]
+
+		v := o.Variables[name]
+		if v == nil {
+			errs = append(errs, fmt.Errorf("server variable {%s} is not defined", name))
+			return s
+		}
+
+		value, ok := overrides[name]
+		if !ok {
+			value = v.Default
+		}
+		if err := v.Check(value); err != nil {
+			errs = append(errs, fmt.Errorf("server variable %q: %w", name, err))
+		}
+		return value
+	})
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return url.Parse(expanded)
+}
+
+// Check checks value in enum when enum defined
+func (o *ServerVariableObject) Check(value string) error {
+	if len(o.Enum) == 0 {
+		return nil
+	}
+	for _, e := range o.Enum {
+		if e == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("value %q should be one of %q", value, o.Enum)
+}
+
+// EffectiveServers returns servers of the operation,
+// which are declared by operation, or path item, or the root,
+// and defaults to a server with url "/"
+func (o *OpenAPIObject) EffectiveServers(method HttpMethod, path string) []*Server {
+	if item := o.Paths.Paths[path]; item != nil {
+		if op := item.Operations.Operations[method]; op != nil && len(op.Servers) > 0 {
+			return op.Servers
+		}
+		if len(item.Servers) > 0 {
+			return item.Servers
+		}
+	}
+	if len(o.Servers) > 0 {
+		return o.Servers
+	}
+	return []*Server{NewServer("/")}
+}
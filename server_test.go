@@ -2,6 +2,8 @@ package oas
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestServer(t *testing.T) {
@@ -16,5 +18,73 @@ func TestServer(t *testing.T) {
 		return server
 	}())
 
+	g.It("with builders", `{"url":"https://{env}.example.com","description":"desc","variables":{"env":{"default":"api","enum":["api","sandbox"],"description":"environment"}}}`,
+		NewServer("https://{env}.example.com").
+			WithDesc("desc").
+			WithVariable("env", NewServerVariable("api").WithEnum("api", "sandbox").WithDesc("environment")),
+	)
+
 	g.Run(t)
 }
+
+func TestServerExpand(t *testing.T) {
+	server := NewServer("{scheme}://{host}:{port}/v1")
+	server.AddVariable("scheme", &ServerVariable{ServerVariableObject: ServerVariableObject{Default: "https", Enum: []string{"http", "https"}}})
+	server.AddVariable("host", NewServerVariable("example.com"))
+	server.AddVariable("port", NewServerVariable("443"))
+
+	t.Run("defaults", func(t *testing.T) {
+		u, err := server.Expand(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com:443/v1", u.String())
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		u, err := server.Expand(map[string]string{"scheme": "http", "port": "8080"})
+		assert.NoError(t, err)
+		assert.Equal(t, "http://example.com:8080/v1", u.String())
+	})
+
+	t.Run("value out of enum", func(t *testing.T) {
+		_, err := server.Expand(map[string]string{"scheme": "ftp", "unknown": "x"})
+		assert.Contains(t, err.Error(), `server variable "scheme": value "ftp" should be one of ["http" "https"]`)
+		assert.Contains(t, err.Error(), `server variable "unknown" is not defined`)
+	})
+
+	t.Run("undefined overrides in order", func(t *testing.T) {
+		_, err := server.Expand(map[string]string{"d": "x", "b": "x", "c": "x", "a": "x"})
+		assert.Equal(t, "server variable \"a\" is not defined\nserver variable \"b\" is not defined\nserver variable \"c\" is not defined\nserver variable \"d\" is not defined", err.Error())
+	})
+
+	assert.NoError(t, server.Validate())
+
+	t.Run("validate", func(t *testing.T) {
+		s := NewServer("https://{host}/{basePath}")
+		s.AddVariable("host", &ServerVariable{ServerVariableObject: ServerVariableObject{Default: "a", Enum: []string{"b"}}})
+		s.AddVariable("version", NewServerVariable("v1"))
+
+		err := s.Validate()
+		assert.Contains(t, err.Error(), `server variable {basePath} is not defined`)
+		assert.Contains(t, err.Error(), `server variable "version" is never used`)
+		assert.Contains(t, err.Error(), `default of server variable "host": value "a" should be one of ["b"]`)
+	})
+}
+
+func TestEffectiveServers(t *testing.T) {
+	openapi := NewOpenAPI()
+
+	assert.Equal(t, "/", openapi.EffectiveServers(GET, "/pets")[0].URL)
+
+	openapi.AddServer(NewServer("https://example.com"))
+	openapi.AddOperation(GET, "/pets", NewOperation("listPets"))
+	openapi.AddOperation(GET, "/users", NewOperation("listUsers"))
+	openapi.Paths.Paths["/users"].Servers = []*Server{NewServer("https://users.example.com")}
+
+	op := NewOperation("createPet")
+	op.AddServer(NewServer("https://write.example.com"))
+	openapi.AddOperation(POST, "/pets", op)
+
+	assert.Equal(t, "https://example.com", openapi.EffectiveServers(GET, "/pets")[0].URL)
+	assert.Equal(t, "https://users.example.com", openapi.EffectiveServers(GET, "/users")[0].URL)
+	assert.Equal(t, "https://write.example.com", openapi.EffectiveServers(POST, "/pets")[0].URL)
+}
@@ -2,14 +2,110 @@ package oas
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 )
 
+const (
+	// ExtensionGoType declares go type of the schema
+	ExtensionGoType = "x-go-type"
+	// ExtensionEnumLabels declares labels of enum values by order
+	ExtensionEnumLabels = "x-enum-labels"
+	// ExtensionInternal marks the object for internal use only
+	ExtensionInternal = "x-internal"
+)
+
+func init() {
+	RegisterExtension[string](ExtensionGoType, "Schema")
+	RegisterExtension[[]string](ExtensionEnumLabels, "Schema")
+	RegisterExtension[bool](ExtensionInternal)
+}
+
+// ExtensionType is the registered go type of the extension
+type ExtensionType struct {
+	Key  string
+	Type reflect.Type
+	// Locations are names of objects the extension could appear in, like "Schema" or "Operation", any when empty
+	Locations []string
+}
+
+// AllowedIn returns true when the extension could appear in the object
+func (e *ExtensionType) AllowedIn(location string) bool {
+	return len(e.Locations) == 0 || slices.Contains(e.Locations, location)
+}
+
+var extensionTypes = struct {
+	sync.RWMutex
+	m map[string]*ExtensionType
+}{m: map[string]*ExtensionType{}}
+
+// RegisterExtension registers go type T of the extension key, and objects the extension could appear in.
+// Values of the key are decoded into T, and checked by UnmarshalStrict.
+// It panics when the key not prefixed with x- or registered already.
+func RegisterExtension[T any](key string, locations ...string) {
+	if !isExtensionKey(key) {
+		panic(fmt.Errorf("extension %q should be prefixed with x-", key))
+	}
+
+	extensionTypes.Lock()
+	defer extensionTypes.Unlock()
+
+	if _, ok := extensionTypes.m[key]; ok {
+		panic(fmt.Errorf("extension %q registered already", key))
+	}
+	extensionTypes.m[key] = &ExtensionType{
+		Key:       key,
+		Type:      reflect.TypeFor[T](),
+		Locations: locations,
+	}
+}
+
+// LookupExtension returns the registered type of the extension key, nil when not registered
+func LookupExtension(key string) *ExtensionType {
+	extensionTypes.RLock()
+	defer extensionTypes.RUnlock()
+	return extensionTypes.m[key]
+}
+
+// Extension returns value of the extension as T,
+// values not in T, like generic json values of unregistered extensions, are converted by json.
+func Extension[T any](v SpecExtensions, key string) (T, bool) {
+	var t T
+	value, ok := v.Extensions[key]
+	if !ok {
+		return t, false
+	}
+	if x, ok := value.(T); ok {
+		return x, true
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return t, false
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, false
+	}
+	return t, true
+}
+
+// isExtensionKey returns true when the key matches ^x- of the spec, case sensitive
+func isExtensionKey(key string) bool {
+	return strings.HasPrefix(key, "x-")
+}
+
 type SpecExtensions struct {
 	Extensions map[string]interface{}
 }
 
+// AddExtension adds the extension, nil value ignored.
+// It panics when the key not prefixed with x-, use SetExtension to get the error instead.
 func (v *SpecExtensions) AddExtension(key string, value interface{}) {
+	if !isExtensionKey(key) {
+		panic(fmt.Errorf("extension %q should be prefixed with x-", key))
+	}
 	if value == nil {
 		return
 	}
@@ -19,10 +115,23 @@ func (v *SpecExtensions) AddExtension(key string, value interface{}) {
 	v.Extensions[key] = value
 }
 
+// SetExtension adds the extension like AddExtension, but checks the key and the value first.
+// Keys should be prefixed with x-, and values of registered extensions should be in the registered type.
+func (v *SpecExtensions) SetExtension(key string, value interface{}) error {
+	if !isExtensionKey(key) {
+		return fmt.Errorf("extension %q should be prefixed with x-", key)
+	}
+	if et := LookupExtension(key); et != nil && value != nil && !reflect.TypeOf(value).AssignableTo(et.Type) {
+		return fmt.Errorf("extension %q should be %s, but got %T", key, et.Type, value)
+	}
+	v.AddExtension(key, value)
+	return nil
+}
+
 func (v SpecExtensions) MarshalJSON() ([]byte, error) {
 	values := make(map[string]interface{})
 	for k := range v.Extensions {
-		if strings.HasPrefix(strings.ToLower(k), "x-") {
+		if isExtensionKey(k) {
 			values[k] = v.Extensions[k]
 		}
 	}
@@ -30,17 +139,38 @@ func (v SpecExtensions) MarshalJSON() ([]byte, error) {
 }
 
 func (v *SpecExtensions) UnmarshalJSON(data []byte) error {
-	var d map[string]interface{}
+	var d map[string]json.RawMessage
 	if err := json.Unmarshal(data, &d); err != nil {
 		return err
 	}
 	for k := range d {
-		if strings.HasPrefix(strings.ToLower(k), "x-") {
-			if v.Extensions == nil {
-				v.Extensions = map[string]interface{}{}
-			}
-			v.Extensions[k] = d[k]
+		if !isExtensionKey(k) {
+			continue
+		}
+		value, err := decodeExtension(k, d[k])
+		if err != nil {
+			return err
 		}
+		if v.Extensions == nil {
+			v.Extensions = map[string]interface{}{}
+		}
+		v.Extensions[k] = value
 	}
 	return nil
 }
+
+// decodeExtension decodes value into the registered type,
+// generic json value kept when not registered or not in the registered type, which UnmarshalStrict reports
+func decodeExtension(key string, data []byte) (interface{}, error) {
+	if et := LookupExtension(key); et != nil {
+		rv := reflect.New(et.Type)
+		if err := json.Unmarshal(data, rv.Interface()); err == nil {
+			return rv.Elem().Interface(), nil
+		}
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -1,7 +1,11 @@
 package oas
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSpecExtensions(t *testing.T) {
@@ -16,5 +20,108 @@ func TestSpecExtensions(t *testing.T) {
 		return e
 	}())
 
+	g.It("with registered extensions", `{"x-enum-labels":["Cat","Dog"],"x-go-type":"Kind"}`, func() *SpecExtensions {
+		e := &SpecExtensions{}
+		e.AddExtension(ExtensionGoType, "Kind")
+		e.AddExtension(ExtensionEnumLabels, []string{"Cat", "Dog"})
+		return e
+	}())
+
 	g.Run(t)
 }
+
+type testExtensionRateLimit struct {
+	Requests int    `json:"requests"`
+	Per      string `json:"per"`
+}
+
+// testExtensionLevel accepts only low or high
+type testExtensionLevel string
+
+func (l *testExtensionLevel) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s != "low" && s != "high" {
+		return fmt.Errorf("invalid level %q", s)
+	}
+	*l = testExtensionLevel(s)
+	return nil
+}
+
+func init() {
+	RegisterExtension[testExtensionRateLimit]("x-test-rate-limit", "Operation")
+	RegisterExtension[testExtensionLevel]("x-test-level")
+}
+
+func TestExtensionRegistry(t *testing.T) {
+	t.Run("register", func(t *testing.T) {
+		assert.Panics(t, func() { RegisterExtension[string]("go-type") })
+		assert.Panics(t, func() { RegisterExtension[string](ExtensionGoType) })
+		assert.Nil(t, LookupExtension("x-unknown"))
+
+		et := LookupExtension("x-test-rate-limit")
+		assert.True(t, et.AllowedIn("Operation"))
+		assert.False(t, et.AllowedIn("Schema"))
+		assert.True(t, LookupExtension(ExtensionInternal).AllowedIn("Schema"))
+	})
+
+	t.Run("add", func(t *testing.T) {
+		e := &SpecExtensions{}
+		assert.EqualError(t, e.SetExtension("internal", true), `extension "internal" should be prefixed with x-`)
+		assert.EqualError(t, e.SetExtension(ExtensionInternal, "true"), `extension "x-internal" should be bool, but got string`)
+		assert.EqualError(t, e.SetExtension("X-Custom", 1), `extension "X-Custom" should be prefixed with x-`)
+		assert.NoError(t, e.SetExtension("x-custom", 1))
+		assert.Panics(t, func() { e.AddExtension("custom", 1) })
+		assert.Panics(t, func() { e.AddExtension("X-Custom", nil) })
+		assert.NoError(t, e.SetExtension(ExtensionInternal, true))
+		assert.NoError(t, e.SetExtension(ExtensionGoType, nil))
+		assert.Equal(t, map[string]interface{}{"x-custom": 1, "x-internal": true}, e.Extensions)
+
+		decoded := &SpecExtensions{}
+		assert.NoError(t, json.Unmarshal([]byte(`{"X-Custom":1,"x-custom":2}`), decoded))
+		assert.Equal(t, map[string]interface{}{"x-custom": float64(2)}, decoded.Extensions)
+	})
+
+	t.Run("decode typed", func(t *testing.T) {
+		op := &Operation{}
+		assert.NoError(t, json.Unmarshal([]byte(`{"operationId":"a","responses":{},"x-test-rate-limit":{"requests":10,"per":"minute"},"x-other":{"a":1},"x-internal":"yes"}`), op))
+
+		assert.Equal(t, testExtensionRateLimit{Requests: 10, Per: "minute"}, op.Extensions["x-test-rate-limit"])
+		// kept generic when not in the registered type
+		assert.Equal(t, "yes", op.Extensions[ExtensionInternal])
+
+		limit, ok := Extension[testExtensionRateLimit](op.SpecExtensions, "x-test-rate-limit")
+		assert.True(t, ok)
+		assert.Equal(t, 10, limit.Requests)
+
+		other, ok := Extension[map[string]int](op.SpecExtensions, "x-other")
+		assert.True(t, ok)
+		assert.Equal(t, map[string]int{"a": 1}, other)
+
+		_, ok = Extension[bool](op.SpecExtensions, ExtensionInternal)
+		assert.False(t, ok)
+		_, ok = Extension[bool](op.SpecExtensions, "x-missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		data := []byte(`{"openapi":"3.0.3","info":{"title":"t","version":"1","x-go-type":"Info"},"paths":{"/pets":{"get":{"operationId":"listPets","responses":{},"x-test-rate-limit":{"requests":"10"},"x-internal":true,"x-test-level":"medium"}}},"components":{"schemas":{"Pet":{"type":"string","x-enum-labels":["Cat"],"x-test-rate-limit":{"requests":1}}}}}`)
+
+		err := UnmarshalStrict(data, &OpenAPI{})
+		errs, ok := err.(DecodeErrors)
+		assert.True(t, ok)
+
+		messages := make([]string, len(errs))
+		for i := range errs {
+			messages[i] = errs[i].Pointer + ": " + errs[i].Message
+		}
+		assert.Equal(t, []string{
+			`/info/x-go-type: extension "x-go-type" not allowed in Info`,
+			`/paths/~1pets/get/x-test-rate-limit/requests: cannot unmarshal string into int`,
+			`/paths/~1pets/get/x-test-level: extension "x-test-level" should be oas.testExtensionLevel: invalid level "medium"`,
+			`/components/schemas/Pet/x-test-rate-limit: extension "x-test-rate-limit" not allowed in Schema`,
+		}, messages)
+	})
+}
@@ -0,0 +1,333 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// UnmarshalStrict decodes data into v like json.Unmarshal,
+// but reports every unknown field, wrong json type and invalid response code at once.
+func UnmarshalStrict(data []byte, v interface{}) error {
+	root, err := parseJSONNode(data)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+
+	c := &strictChecker{data: data}
+	c.check(root, rv.Type().Elem(), "")
+
+	if len(c.errors) > 0 {
+		return c.errors
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+type DecodeError struct {
+	Pointer string
+	Offset  int
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *DecodeError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%d:%d %s: %s", e.Line, e.Column, pointer, e.Message)
+}
+
+type DecodeErrors []*DecodeError
+
+func (errs DecodeErrors) Error() string {
+	buf := bytes.NewBuffer(nil)
+	for i, e := range errs {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(e.Error())
+	}
+	return buf.String()
+}
+
+var (
+	typeSpecExtensions  = reflect.TypeOf(SpecExtensions{})
+	typeReference       = reflect.TypeOf(Reference{})
+	typePaths           = reflect.TypeOf(Paths{})
+	typeOperations      = reflect.TypeOf(Operations{})
+	typeResponsesObject = reflect.TypeOf(ResponsesObject{})
+	typeCallbackObject  = reflect.TypeOf(CallbackObject{})
+	typeSchemaOrBool    = reflect.TypeOf(SchemaOrBool{})
+	typeAny             = reflect.TypeOf(Any{})
+	typePathItemPtr     = reflect.TypeOf(&PathItem{})
+	typeOperationPtr    = reflect.TypeOf(&Operation{})
+	typeResponsePtr     = reflect.TypeOf(&Response{})
+	typeSchemaPtr       = reflect.TypeOf(&Schema{})
+)
+
+type strictChecker struct {
+	data   []byte
+	errors DecodeErrors
+}
+
+func (c *strictChecker) report(offset int, pointer string, format string, args ...interface{}) {
+	line, column := lineAndColumn(c.data, offset)
+	c.errors = append(c.errors, &DecodeError{
+		Pointer: pointer,
+		Offset:  offset,
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *strictChecker) expect(n *jsonNode, kind jsonKind, t reflect.Type, pointer string) bool {
+	if n.kind == kind {
+		return true
+	}
+	c.report(n.offset, pointer, "cannot unmarshal %s into %s", n.kind, t)
+	return false
+}
+
+func (c *strictChecker) check(n *jsonNode, t reflect.Type, pointer string) {
+	if n.kind == jsonNull {
+		return
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == typeAny {
+		return
+	}
+
+	if t == typeSchemaOrBool {
+		if n.kind == jsonBool {
+			return
+		}
+		if c.expect(n, jsonObject, t, pointer) {
+			c.check(n, typeSchemaPtr, pointer)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.Struct:
+		if c.expect(n, jsonObject, t, pointer) {
+			c.checkObject(n, shapeOf(t), pointer)
+		}
+	case reflect.Map:
+		if c.expect(n, jsonObject, t, pointer) {
+			for _, m := range n.members {
+				c.check(m.value, t.Elem(), pointer+"/"+escapeJSONPointer(m.key))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if c.expect(n, jsonArray, t, pointer) {
+			for i, item := range n.items {
+				c.check(item, t.Elem(), pointer+"/"+strconv.Itoa(i))
+			}
+		}
+	case reflect.String:
+		c.expect(n, jsonString, t, pointer)
+	case reflect.Bool:
+		c.expect(n, jsonBool, t, pointer)
+	case reflect.Float32, reflect.Float64:
+		c.expect(n, jsonNumber, t, pointer)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if c.expect(n, jsonNumber, t, pointer) {
+			if _, err := strconv.ParseInt(n.raw, 10, t.Bits()); err != nil {
+				c.report(n.offset, pointer, "cannot unmarshal number %s into %s", n.raw, t)
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if c.expect(n, jsonNumber, t, pointer) {
+			if _, err := strconv.ParseUint(n.raw, 10, t.Bits()); err != nil {
+				c.report(n.offset, pointer, "cannot unmarshal number %s into %s", n.raw, t)
+			}
+		}
+	}
+}
+
+// checkExtension checks value of the registered extension,
+// and decodes it too since values failed to decode are kept generic silently by SpecExtensions
+func (c *strictChecker) checkExtension(n *jsonNode, et *ExtensionType, pointer string) {
+	count := len(c.errors)
+	c.check(n, et.Type, pointer)
+	if len(c.errors) > count {
+		return
+	}
+	rv := reflect.New(et.Type)
+	if err := json.NewDecoder(bytes.NewReader(c.data[n.offset:])).Decode(rv.Interface()); err != nil {
+		c.report(n.offset, pointer, "extension %q should be %s: %s", et.Key, et.Type, err)
+	}
+}
+
+func (c *strictChecker) checkObject(n *jsonNode, s *objectShape, pointer string) {
+	if s.ref {
+		if ref := n.member("$ref"); ref != nil {
+			if c.expect(ref.value, jsonString, reflect.TypeOf(""), pointer+"/$ref") {
+				return
+			}
+		}
+	}
+
+	for _, m := range n.members {
+		p := pointer + "/" + escapeJSONPointer(m.key)
+
+		if t, ok := s.fields[m.key]; ok {
+			c.check(m.value, t, p)
+			continue
+		}
+
+		if s.extensions && isExtensionKey(m.key) {
+			if et := LookupExtension(m.key); et != nil {
+				if et.AllowedIn(s.location) {
+					c.checkExtension(m.value, et, p)
+				} else {
+					c.report(m.offset, p, "extension %q not allowed in %s", m.key, s.location)
+				}
+			}
+			continue
+		}
+
+		matched := false
+		for _, pattern := range s.patterns {
+			if t := pattern(m.key); t != nil {
+				c.check(m.value, t, p)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		if s.responses {
+			c.report(m.offset, p, "invalid response code %q", m.key)
+			continue
+		}
+
+		c.report(m.offset, p, "unknown field %q", m.key)
+	}
+}
+
+type objectShape struct {
+	// location is name of the object type, to check extensions allowed in
+	location   string
+	fields     map[string]reflect.Type
+	patterns   []func(key string) reflect.Type
+	extensions bool
+	ref        bool
+	responses  bool
+}
+
+func shapeOf(t reflect.Type) *objectShape {
+	s := &objectShape{location: t.Name(), fields: map[string]reflect.Type{}}
+	s.collect(t)
+	return s
+}
+
+func (s *objectShape) collect(t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case typeSpecExtensions:
+		s.extensions = true
+		return
+	case typeReference:
+		s.ref = true
+		return
+	case typePaths:
+		s.extensions = true
+		s.patterns = append(s.patterns, func(key string) reflect.Type {
+			if strings.HasPrefix(key, "/") {
+				return typePathItemPtr
+			}
+			return nil
+		})
+		return
+	case typeOperations:
+		s.patterns = append(s.patterns, func(key string) reflect.Type {
+			if httpMethods[HttpMethod(key)] {
+				return typeOperationPtr
+			}
+			return nil
+		})
+		return
+	case typeResponsesObject:
+		s.responses = true
+		s.patterns = append(s.patterns, func(key string) reflect.Type {
+			if parseResponseKey(key) != nil {
+				return typeResponsePtr
+			}
+			return nil
+		})
+		return
+	case typeCallbackObject:
+		s.patterns = append(s.patterns, func(key string) reflect.Type {
+			return typePathItemPtr
+		})
+		return
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("json")
+		name := strings.Split(tag, ",")[0]
+
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			s.collect(f.Type)
+			continue
+		}
+
+		if !hasTag || f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		s.fields[name] = f.Type
+	}
+}
+
+func escapeJSONPointer(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
+
+func lineAndColumn(data []byte, offset int) (int, int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return line, utf8.RuneCount(before[lineStart:]) + 1
+}
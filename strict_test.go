@@ -0,0 +1,66 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalStrict(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		data := []byte(`{"openapi":"3.0.3","info":{"title":"t","version":"1","x-logo":"logo"},"paths":{"/pets":{"get":{"operationId":"listPets","responses":{"200":{"description":"ok"},"default":{"$ref":"#/components/responses/Error"}}},"parameters":[]}},"components":{"schemas":{"Pet":{"type":"object","additionalProperties":false}}}}`)
+
+		openapi := &OpenAPI{}
+		assert.NoError(t, UnmarshalStrict(data, openapi))
+		assert.Equal(t, "listPets", openapi.Paths.Paths["/pets"].Operations.Operations[GET].OperationId)
+	})
+
+	t.Run("reports all errors with positions", func(t *testing.T) {
+		data := []byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "t", "version": 1},
+  "paths": {
+    "/pets": {
+      "get": {
+        "opertionId": "listPets",
+        "responses": {
+          "ok": {"description": "ok"}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {"type": "object", "requried": ["id"], "maxLength": -1}
+    }
+  }
+}`)
+
+		err := UnmarshalStrict(data, &OpenAPI{})
+
+		errs, ok := err.(DecodeErrors)
+		assert.True(t, ok)
+		assert.Len(t, errs, 5)
+
+		assert.Equal(t, "/info/version", errs[0].Pointer)
+		assert.Equal(t, 3, errs[0].Line)
+		assert.Equal(t, 37, errs[0].Column)
+
+		assert.Equal(t, "/paths/~1pets/get/opertionId", errs[1].Pointer)
+		assert.Equal(t, `unknown field "opertionId"`, errs[1].Message)
+		assert.Equal(t, 7, errs[1].Line)
+		assert.Equal(t, 9, errs[1].Column)
+
+		assert.Equal(t, "/paths/~1pets/get/responses/ok", errs[2].Pointer)
+		assert.Equal(t, `invalid response code "ok"`, errs[2].Message)
+
+		assert.Equal(t, "/components/schemas/Pet/requried", errs[3].Pointer)
+		assert.Equal(t, "/components/schemas/Pet/maxLength", errs[4].Pointer)
+
+		assert.Contains(t, err.Error(), `7:9 /paths/~1pets/get/opertionId: unknown field "opertionId"`)
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		assert.Error(t, UnmarshalStrict([]byte(`{"openapi":`), &OpenAPI{}))
+	})
+}
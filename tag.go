@@ -24,6 +24,18 @@ type Tag struct {
 	SpecExtensions
 }
 
+func (i Tag) WithDesc(desc string) *Tag {
+	c := i.Clone()
+	c.Description = desc
+	return c
+}
+
+func (i Tag) WithExternalDocs(doc *ExternalDoc) *Tag {
+	c := i.Clone()
+	c.ExternalDocs = doc.Clone()
+	return c
+}
+
 func (i Tag) MarshalJSON() ([]byte, error) {
 	return flattenMarshalJSON(i.TagObject, i.SpecExtensions)
 }
@@ -16,5 +16,9 @@ func TestTag(t *testing.T) {
 		return t
 	}())
 
+	g.It("with builders", `{"name":"tag","description":"desc","externalDocs":{"url":"https://example.com"}}`,
+		NewTag("tag").WithDesc("desc").WithExternalDocs(NewExternalDoc("https://example.com", "")),
+	)
+
 	g.Run(t)
 }
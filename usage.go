@@ -0,0 +1,176 @@
+package oas
+
+import (
+	"maps"
+	"slices"
+)
+
+// ComponentUsage describes how a component is referred
+type ComponentUsage struct {
+	Group string
+	Name  string
+	// Count is the count of refs to the component, security requirements included
+	Count int
+	// UsedBy lists json pointers of operations, path items or components refer the component directly,
+	// /security for top-level security requirements
+	UsedBy []string
+	// Unused is true when not referred from paths or top-level security, directly or transitively
+	Unused bool
+}
+
+// Pointer returns json pointer of the component
+func (u *ComponentUsage) Pointer() string {
+	return "/components/" + u.Group + "/" + escapeJSONPointer(u.Name)
+}
+
+// ComponentUsages builds the reference graph of the document, returns usages of all components ordered by group and name
+func (o *OpenAPI) ComponentUsages() []*ComponentUsage {
+	usages := map[string]*ComponentUsage{}
+	for _, group := range mergeGroups {
+		for _, name := range o.componentNames(group) {
+			usages[group+"/"+name] = &ComponentUsage{Group: group, Name: name, Unused: true}
+		}
+	}
+
+	// refers maps owner pointer to components referred directly
+	refers := map[string][]*ComponentUsage{}
+	refer := func(owner string, group string, name string) {
+		u, ok := usages[group+"/"+name]
+		if !ok {
+			return
+		}
+		u.Count++
+		for _, p := range u.UsedBy {
+			if p == owner {
+				return
+			}
+		}
+		u.UsedBy = append(u.UsedBy, owner)
+		refers[owner] = append(refers[owner], u)
+	}
+	visitorOf := func(owner string) refVisitor {
+		return func(group string, ref *Reference) {
+			if r := ParseComponentRefer(ref.Refer.RefString()); r != nil {
+				refer(owner, r.Group, r.ID)
+			}
+		}
+	}
+	referSecurity := func(owner string, requirements []*SecurityRequirement) {
+		for _, sr := range requirements {
+			if sr != nil {
+				for _, name := range slices.Sorted(maps.Keys(*sr)) {
+					refer(owner, "securitySchemes", name)
+				}
+			}
+		}
+	}
+
+	roots := []string{"/security"}
+	referSecurity("/security", o.Security)
+
+	for _, path := range slices.Sorted(maps.Keys(o.Paths.Paths)) {
+		item := o.Paths.Paths[path]
+		if item == nil {
+			continue
+		}
+		pointer := "/paths/" + escapeJSONPointer(path)
+		roots = append(roots, pointer)
+		for _, p := range item.Parameters {
+			visitorOf(pointer).parameter(p)
+		}
+		for _, method := range slices.Sorted(maps.Keys(item.Operations.Operations)) {
+			op := item.Operations.Operations[method]
+			if op == nil {
+				continue
+			}
+			opPointer := pointer + "/" + string(method)
+			roots = append(roots, opPointer)
+			visitorOf(opPointer).operation(op)
+			eachOperationSecurity(op, func(requirements []*SecurityRequirement) {
+				referSecurity(opPointer, requirements)
+			})
+		}
+	}
+
+	for _, group := range componentGroups {
+		for _, name := range o.componentNames(group) {
+			pointer := (&ComponentUsage{Group: group, Name: name}).Pointer()
+			visitorOf(pointer).component(&o.ComponentsObject, group, name)
+			if c := o.Callbacks[name]; group == "callbacks" && c != nil {
+				eachCallbackOperation(c, func(op *Operation) {
+					eachOperationSecurity(op, func(requirements []*SecurityRequirement) {
+						referSecurity(pointer, requirements)
+					})
+				})
+			}
+		}
+	}
+
+	var markUsed func(owner string)
+	markUsed = func(owner string) {
+		for _, u := range refers[owner] {
+			if u.Unused {
+				u.Unused = false
+				markUsed(u.Pointer())
+			}
+		}
+	}
+	for _, root := range roots {
+		markUsed(root)
+	}
+
+	list := make([]*ComponentUsage, 0, len(usages))
+	for _, group := range mergeGroups {
+		for _, name := range o.componentNames(group) {
+			list = append(list, usages[group+"/"+name])
+		}
+	}
+	return list
+}
+
+// UnusedComponents returns usages of components not referred from paths or top-level security
+func (o *OpenAPI) UnusedComponents() []*ComponentUsage {
+	unused := make([]*ComponentUsage, 0)
+	for _, u := range o.ComponentUsages() {
+		if u.Unused {
+			unused = append(unused, u)
+		}
+	}
+	return unused
+}
+
+// PruneComponents removes unused components, returns usages of the removed ones
+func (o *OpenAPI) PruneComponents() []*ComponentUsage {
+	unused := o.UnusedComponents()
+	for _, u := range unused {
+		o.deleteComponent(u.Group, u.Name)
+	}
+	return unused
+}
+
+// eachOperationSecurity calls fn with security requirements of the operation and operations of its inline callbacks
+func eachOperationSecurity(op *Operation, fn func(requirements []*SecurityRequirement)) {
+	fn(op.Security)
+	for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
+		eachCallbackOperation(op.Callbacks[name], func(cbOp *Operation) {
+			eachOperationSecurity(cbOp, fn)
+		})
+	}
+}
+
+func eachCallbackOperation(c *Callback, fn func(op *Operation)) {
+	if c == nil {
+		return
+	}
+	for _, expr := range slices.Sorted(maps.Keys(c.CallbackObject)) {
+		item := c.CallbackObject[expr]
+		if item == nil {
+			continue
+		}
+		for _, method := range slices.Sorted(maps.Keys(item.Operations.Operations)) {
+			if op := item.Operations.Operations[method]; op != nil {
+				fn(op)
+			}
+		}
+	}
+}
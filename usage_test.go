@@ -0,0 +1,69 @@
+package oas
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponentUsages(t *testing.T) {
+	openapi := newFilterDoc()
+	openapi.AddSchema("Node", ObjectOf(Props{}))
+	openapi.Schemas["Node"].SetProperty("children", ItemsOf(openapi.RefSchema("Node")), false)
+	openapi.AddSchema("Orphan", ObjectOf(Props{"node": openapi.RefSchema("Node")}))
+	openapi.AddSecurityScheme("basic", NewHTTPSecurityScheme("basic", ""))
+	openapi.AddParameter("PetId", PathParameter("petId", Long()))
+
+	op := NewOperation("showPet")
+	ok := NewResponse("ok")
+	ok.AddContent("application/json", NewMediaTypeWithSchema(openapi.RefSchema("Pet")))
+	op.AddResponse(http.StatusOK, ok)
+	op.AddResponse(http.StatusNotFound, openapi.RefResponse("NotFound"))
+	openapi.AddOperation(GET, "/pets/{petId}", op)
+	item := openapi.Paths.Paths["/pets/{petId}"]
+	item.Parameters = append(item.Parameters, openapi.RefParameter("PetId"))
+
+	usages := map[string]*ComponentUsage{}
+	for _, u := range openapi.ComponentUsages() {
+		usages[u.Group+"/"+u.Name] = u
+	}
+
+	t.Run("ref counts and used by", func(t *testing.T) {
+		assert.Equal(t, 2, usages["responses/NotFound"].Count)
+		assert.Equal(t, []string{"/paths/~1pets/get", "/paths/~1pets~1{petId}/get"}, usages["responses/NotFound"].UsedBy)
+
+		assert.Equal(t, 2, usages["schemas/User"].Count)
+		assert.Equal(t, []string{"/components/schemas/Audit", "/components/schemas/Pet"}, usages["schemas/User"].UsedBy)
+
+		assert.Equal(t, []string{"/paths/~1pets~1{petId}"}, usages["parameters/PetId"].UsedBy)
+		assert.Equal(t, []string{"/components/headers/RateLimit"}, usages["examples/RateLimit"].UsedBy)
+		assert.Equal(t, []string{"/paths/~1pets/get"}, usages["securitySchemes/token"].UsedBy)
+	})
+
+	t.Run("unused", func(t *testing.T) {
+		unused := make([]string, 0)
+		for _, u := range openapi.UnusedComponents() {
+			unused = append(unused, u.Pointer())
+		}
+		// Node referred by itself and Orphan only
+		assert.Equal(t, []string{
+			"/components/schemas/Node",
+			"/components/schemas/Orphan",
+			"/components/securitySchemes/basic",
+		}, unused)
+
+		assert.Equal(t, 2, usages["schemas/Node"].Count)
+		assert.True(t, usages["schemas/Node"].Unused)
+		assert.False(t, usages["schemas/Audit"].Unused)
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		pruned := openapi.PruneComponents()
+		assert.Len(t, pruned, 3)
+		assert.Nil(t, openapi.Schemas["Node"])
+		assert.Nil(t, openapi.SecuritySchemes["basic"])
+		assert.NotNil(t, openapi.Schemas["Audit"])
+		assert.Empty(t, openapi.UnusedComponents())
+	})
+}
@@ -0,0 +1,289 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-courier/oas"
+)
+
+// Dispatcher delivers callbacks of an operation, after the operation is handled
+type Dispatcher struct {
+	OpenAPI *oas.OpenAPI
+	// Transport sends requests, http.DefaultClient used when nil
+	Transport Transport
+	Signers   []Signer
+	Retry     *RetryPolicy
+	// Sleep waits between retries, could be replaced in tests
+	Sleep func(ctx context.Context, d time.Duration) error
+	// OnDelivery is called when each delivery finished, for recording results
+	OnDelivery func(d *Delivery)
+}
+
+// Delivery is the result of a callback request
+type Delivery struct {
+	Callback    string
+	Expression  oas.RuntimeExpression
+	Method      oas.HttpMethod
+	OperationId string
+	URL         string
+	Attempts    []*Attempt
+	// Err is the error of building the request, or of the last attempt
+	Err error
+}
+
+// Delivered returns true when the last attempt got a 2xx response
+func (d *Delivery) Delivered() bool {
+	if d.Err != nil || len(d.Attempts) == 0 {
+		return false
+	}
+	last := d.Attempts[len(d.Attempts)-1]
+	return last.StatusCode >= 200 && last.StatusCode < 300
+}
+
+type Attempt struct {
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+}
+
+var ErrUnexpectedStatus = errors.New("unexpected status")
+
+// ErrSign wraps errors of signers, deliveries failed by which are not retried
+var ErrSign = errors.New("sign request failed")
+
+// Dispatch evaluates urls of callbacks of the operation by the exchange of the triggering request and response,
+// and sends payloads keyed by callback name as request bodies.
+// payloads are validated by json schemas of request bodies of callback operations before sent.
+func (d *Dispatcher) Dispatch(ctx context.Context, op *oas.Operation, x *oas.Exchange, payloads map[string]interface{}) []*Delivery {
+	deliveries := make([]*Delivery, 0)
+
+	for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
+		c := d.OpenAPI.ResolveCallback(op.Callbacks[name])
+		if c == nil {
+			deliveries = append(deliveries, d.finish(&Delivery{Callback: name, Err: fmt.Errorf("callback %s not found", name)}))
+			continue
+		}
+
+		for _, expr := range slices.Sorted(maps.Keys(c.CallbackObject)) {
+			pathItem := c.CallbackObject[expr]
+			if pathItem == nil {
+				continue
+			}
+
+			for _, method := range slices.Sorted(maps.Keys(pathItem.Operations.Operations)) {
+				delivery := &Delivery{
+					Callback:    name,
+					Expression:  expr,
+					Method:      method,
+					OperationId: pathItem.Operations.Operations[method].OperationId,
+				}
+				d.deliver(ctx, delivery, pathItem.Operations.Operations[method], x, payloads[name])
+				deliveries = append(deliveries, d.finish(delivery))
+			}
+		}
+	}
+
+	return deliveries
+}
+
+func (d *Dispatcher) finish(delivery *Delivery) *Delivery {
+	if d.OnDelivery != nil {
+		d.OnDelivery(delivery)
+	}
+	return delivery
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *Delivery, op *oas.Operation, x *oas.Exchange, payload interface{}) {
+	u, err := evaluateURL(delivery.Expression, x)
+	if err != nil {
+		delivery.Err = err
+		return
+	}
+	delivery.URL = u
+
+	body, contentType, err := d.encodePayload(op, payload)
+	if err != nil {
+		delivery.Err = err
+		return
+	}
+
+	retry := d.Retry
+
+	for attempt := 0; attempt < retry.maxAttempts(); attempt++ {
+		if attempt > 0 {
+			if err := d.sleep(ctx, retry.backoff(attempt)); err != nil {
+				delivery.Err = err
+				return
+			}
+		}
+
+		resp, err := d.send(ctx, delivery, body, contentType)
+		if err != nil {
+			delivery.Err = err
+		} else if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			delivery.Err = nil
+		} else {
+			delivery.Err = fmt.Errorf("%w %d", ErrUnexpectedStatus, resp.StatusCode)
+		}
+
+		if delivery.Err == nil || errors.Is(err, ErrSign) || !retry.shouldRetry(resp, err) {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *Delivery, body []byte, contentType string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(string(delivery.Method)), delivery.URL, reader)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	for _, s := range d.Signers {
+		if err := s.Sign(req, body); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSign, err)
+		}
+	}
+
+	transport := d.Transport
+	if transport == nil {
+		transport = http.DefaultClient
+	}
+
+	started := time.Now()
+	resp, err := transport.Do(req)
+
+	attempt := &Attempt{Duration: time.Since(started), Err: err}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// drain for reusing of connections
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	attempt.StatusCode = resp.StatusCode
+	return resp, nil
+}
+
+func (d *Dispatcher) sleep(ctx context.Context, duration time.Duration) error {
+	if d.Sleep != nil {
+		return d.Sleep(ctx, duration)
+	}
+	t := time.NewTimer(duration)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (d *Dispatcher) encodePayload(op *oas.Operation, payload interface{}) ([]byte, string, error) {
+	rb := d.OpenAPI.ResolveRequestBody(op.RequestBody)
+
+	if payload == nil {
+		if rb != nil && rb.Required {
+			return nil, "", fmt.Errorf("request body of %s is required", op.OperationId)
+		}
+		return nil, "", nil
+	}
+
+	contentType := "application/json"
+	var mt *oas.MediaType
+
+	if rb != nil && len(rb.Content) > 0 {
+		contentType = ""
+		for _, ct := range slices.Sorted(maps.Keys(rb.Content)) {
+			if isJSON(ct) {
+				contentType = ct
+				break
+			}
+		}
+		if contentType == "" {
+			contentType = slices.Sorted(maps.Keys(rb.Content))[0]
+		}
+		mt = rb.Content[contentType]
+	}
+
+	var data []byte
+
+	switch b := payload.(type) {
+	case []byte:
+		data = b
+	case string:
+		data = []byte(b)
+	default:
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, "", err
+		}
+		data = encoded
+	}
+
+	if !isJSON(contentType) {
+		return data, contentType, nil
+	}
+
+	raw := json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, "", fmt.Errorf("invalid request body of %s: %w", op.OperationId, err)
+	}
+
+	if mt != nil && mt.Schema != nil {
+		if err := d.OpenAPI.ValidateValue(mt.Schema, raw); err != nil {
+			return nil, "", fmt.Errorf("invalid request body of %s: %w", op.OperationId, err)
+		}
+	}
+
+	return data, contentType, nil
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func evaluateURL(expr oas.RuntimeExpression, x *oas.Exchange) (string, error) {
+	v, err := expr.Evaluate(x)
+	if err != nil {
+		return "", fmt.Errorf("evaluate url %s: %w", expr, err)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("evaluate url %s: should be string, but got %T", expr, v)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() {
+		return "", fmt.Errorf("evaluate url %s: should be absolute, but got %q", expr, s)
+	}
+	return u.String(), nil
+}
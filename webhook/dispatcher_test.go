@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOpenAPI() (*oas.OpenAPI, *oas.Operation) {
+	openapi := oas.NewOpenAPI()
+
+	event := oas.NewOperation("onEvent")
+	rb := oas.NewRequestBody("", true)
+	rb.AddContent("application/json", oas.NewMediaTypeWithSchema(oas.ObjectOf(oas.Props{
+		"id":    oas.String(),
+		"event": oas.String(),
+	}, "id", "event")))
+	event.SetRequestBody(rb)
+	event.AddResponse(http.StatusOK, oas.NewResponse("ok"))
+
+	openapi.AddCallback("Event", oas.NewCallback(oas.POST, "{$request.body#/callbackUrl}?event={$response.body#/id}", event))
+
+	op := oas.NewOperation("subscribe")
+	op.AddCallback("onEvent", openapi.RefCallback("Event"))
+	openapi.AddOperation(oas.POST, "/subscriptions", op)
+
+	return openapi, op
+}
+
+func newExchange(callbackURL string) *oas.Exchange {
+	req := httptest.NewRequest(http.MethodPost, "/subscriptions", nil)
+	return &oas.Exchange{
+		Request:      req,
+		RequestBody:  []byte(`{"callbackUrl":"` + callbackURL + `"}`),
+		Response:     &http.Response{StatusCode: http.StatusCreated, Header: http.Header{}},
+		ResponseBody: []byte(`{"id":"s1"}`),
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	payloads := map[string]interface{}{"onEvent": map[string]string{"id": "s1", "event": "created"}}
+
+	t.Run("deliver with signature", func(t *testing.T) {
+		secret := []byte("secret")
+		now := func() time.Time { return time.Unix(1600000000, 0) }
+
+		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "/hooks", r.URL.Path)
+			assert.Equal(t, "s1", r.URL.Query().Get("event"))
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "t=1600000000,sha256="+SignHMAC(secret, "1600000000", body), r.Header.Get("X-Signature"))
+			assert.JSONEq(t, `{"id":"s1","event":"created"}`, string(body))
+		}))
+		defer s.Close()
+
+		openapi, op := newOpenAPI()
+
+		recorded := make([]*Delivery, 0)
+
+		d := &Dispatcher{
+			OpenAPI:    openapi,
+			Transport:  s.Client(),
+			Signers:    []Signer{HMACSigner("X-Signature", secret, now)},
+			OnDelivery: func(d *Delivery) { recorded = append(recorded, d) },
+		}
+
+		deliveries := d.Dispatch(context.Background(), op, newExchange(s.URL+"/hooks"), payloads)
+
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, deliveries, recorded)
+		assert.NoError(t, deliveries[0].Err)
+		assert.True(t, deliveries[0].Delivered())
+		assert.Equal(t, "onEvent", deliveries[0].OperationId)
+		assert.Equal(t, s.URL+"/hooks?event=s1", deliveries[0].URL)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		openapi, op := newOpenAPI()
+
+		statuses := []int{http.StatusServiceUnavailable, 0, http.StatusNoContent}
+		calls := 0
+		delays := make([]time.Duration, 0)
+
+		d := &Dispatcher{
+			OpenAPI: openapi,
+			Transport: TransportFunc(func(req *http.Request) (*http.Response, error) {
+				status := statuses[calls]
+				calls++
+				if status == 0 {
+					return nil, errors.New("connection refused")
+				}
+				return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+			}),
+			Retry: &RetryPolicy{MaxAttempts: 5, Backoff: ExponentialBackoff(time.Second, 10*time.Second)},
+			Sleep: func(ctx context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			},
+		}
+
+		deliveries := d.Dispatch(context.Background(), op, newExchange("https://example.com/hooks"), payloads)
+
+		assert.True(t, deliveries[0].Delivered())
+		assert.Len(t, deliveries[0].Attempts, 3)
+		assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].Attempts[0].StatusCode)
+		assert.EqualError(t, deliveries[0].Attempts[1].Err, "connection refused")
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, delays)
+	})
+
+	t.Run("no retry on client error", func(t *testing.T) {
+		openapi, op := newOpenAPI()
+
+		d := &Dispatcher{
+			OpenAPI: openapi,
+			Transport: TransportFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader(""))}, nil
+			}),
+			Retry: &RetryPolicy{MaxAttempts: 3},
+		}
+
+		deliveries := d.Dispatch(context.Background(), op, newExchange("https://example.com/hooks"), payloads)
+
+		assert.Len(t, deliveries[0].Attempts, 1)
+		assert.False(t, deliveries[0].Delivered())
+		assert.True(t, errors.Is(deliveries[0].Err, ErrUnexpectedStatus))
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		openapi, op := newOpenAPI()
+
+		d := &Dispatcher{
+			OpenAPI: openapi,
+			Transport: TransportFunc(func(req *http.Request) (*http.Response, error) {
+				t.Fatal("should not send")
+				return nil, nil
+			}),
+		}
+
+		deliveries := d.Dispatch(context.Background(), op, newExchange("https://example.com/hooks"), map[string]interface{}{
+			"onEvent": map[string]string{"id": "s1"},
+		})
+		assert.EqualError(t, deliveries[0].Err, "invalid request body of onEvent: /event: required")
+
+		deliveries = d.Dispatch(context.Background(), op, newExchange("https://example.com/hooks"), nil)
+		assert.EqualError(t, deliveries[0].Err, "request body of onEvent is required")
+
+		deliveries = d.Dispatch(context.Background(), op, newExchange("https://example.com/hooks"), map[string]interface{}{
+			"onEvent": []byte(`{"id":"s1"}`),
+		})
+		assert.EqualError(t, deliveries[0].Err, "invalid request body of onEvent: /event: required")
+
+		deliveries = d.Dispatch(context.Background(), op, newExchange("https://example.com/hooks"), map[string]interface{}{
+			"onEvent": `{"id":`,
+		})
+		assert.Contains(t, deliveries[0].Err.Error(), "invalid request body of onEvent")
+	})
+
+	t.Run("no retry on sign error", func(t *testing.T) {
+		openapi, op := newOpenAPI()
+
+		signed := 0
+		d := &Dispatcher{
+			OpenAPI: openapi,
+			Transport: TransportFunc(func(req *http.Request) (*http.Response, error) {
+				t.Fatal("should not send")
+				return nil, nil
+			}),
+			Signers: []Signer{SignerFunc(func(req *http.Request, body []byte) error {
+				signed++
+				return errors.New("key expired")
+			})},
+			Retry: &RetryPolicy{MaxAttempts: 3},
+		}
+
+		deliveries := d.Dispatch(context.Background(), op, newExchange("https://example.com/hooks"), payloads)
+
+		assert.Equal(t, 1, signed)
+		assert.True(t, errors.Is(deliveries[0].Err, ErrSign))
+		assert.False(t, deliveries[0].Delivered())
+	})
+
+	t.Run("unresolved url", func(t *testing.T) {
+		openapi, op := newOpenAPI()
+
+		x := newExchange("")
+		x.RequestBody = []byte(`{}`)
+
+		deliveries := (&Dispatcher{OpenAPI: openapi}).Dispatch(context.Background(), op, x, payloads)
+		assert.True(t, errors.Is(deliveries[0].Err, oas.ErrExpressionValueNotFound))
+	})
+}
+
+func TestIsJSON(t *testing.T) {
+	for _, ct := range []string{"application/json", "application/json; charset=utf-8", "Application/JSON", "application/merge-patch+json", "application/vnd.api+json; charset=utf-8"} {
+		assert.True(t, isJSON(ct), ct)
+	}
+	for _, ct := range []string{"text/plain", "application/jsonl", "application/json+xml", "", ";"} {
+		assert.False(t, isJSON(ct), ct)
+	}
+}
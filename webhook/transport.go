@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Transport sends requests of callbacks, *http.Client is a Transport
+type Transport interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type TransportFunc func(req *http.Request) (*http.Response, error)
+
+func (fn TransportFunc) Do(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// Signer signs the request before each attempt, body is the payload sent
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+type SignerFunc func(req *http.Request, body []byte) error
+
+func (fn SignerFunc) Sign(req *http.Request, body []byte) error {
+	return fn(req, body)
+}
+
+// HMACSigner sets header as `t=<unix timestamp>,sha256=<hex of hmac>`,
+// the hmac is signed of `<unix timestamp>.<body>` with the secret
+func HMACSigner(header string, secret []byte, now func() time.Time) Signer {
+	if now == nil {
+		now = time.Now
+	}
+	return SignerFunc(func(req *http.Request, body []byte) error {
+		ts := strconv.FormatInt(now().Unix(), 10)
+		req.Header.Set(header, "t="+ts+",sha256="+SignHMAC(secret, ts, body))
+		return nil
+	})
+}
+
+func SignHMAC(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RetryPolicy retries deliveries failed by transport errors, or responses of 429 or 5xx
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt, no retry when less than 2
+	MaxAttempts int
+	// Backoff returns the delay before the attempt, attempt starts from 1 for the first retry
+	Backoff func(attempt int) time.Duration
+	// ShouldRetry overrides the default rule when set
+	ShouldRetry func(resp *http.Response, err error) bool
+}
+
+func ExponentialBackoff(base time.Duration, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base << uint(attempt-1)
+		if d <= 0 || d > max {
+			return max
+		}
+		return d
+	}
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	if p == nil || p.Backoff == nil {
+		return 0
+	}
+	return p.Backoff(attempt)
+}
+
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if p != nil && p.ShouldRetry != nil {
+		return p.ShouldRetry(resp, err)
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
@@ -0,0 +1,277 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/oas"
+)
+
+// Target is the operation to request
+type Target struct {
+	Method    oas.HttpMethod
+	Path      string
+	PathItem  *oas.PathItem
+	Operation *oas.Operation
+}
+
+func FindTarget(openapi *oas.OpenAPI, operationId string) (*Target, error) {
+	method, path, op := openapi.Paths.FindOperationByID(operationId)
+	if op == nil {
+		return nil, fmt.Errorf("operation %q not found", operationId)
+	}
+	return &Target{Method: method, Path: path, PathItem: openapi.Paths.Paths[path], Operation: op}, nil
+}
+
+// Parameters returns parameters of the operation and the path item, the operation level ones override path item level ones
+func (t *Target) Parameters(components *oas.ComponentsObject) []*oas.Parameter {
+	params := make([]*oas.Parameter, 0)
+	seen := map[string]bool{}
+
+	add := func(list []*oas.Parameter) {
+		for _, p := range list {
+			p = components.ResolveParameter(p)
+			if p == nil {
+				continue
+			}
+			key := string(p.In) + "." + p.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			params = append(params, p)
+		}
+	}
+
+	add(t.Operation.Parameters)
+	if t.PathItem != nil {
+		add(t.PathItem.Parameters)
+	}
+
+	return params
+}
+
+// NewRequest creates request of the target operation,
+// values are keyed by parameter name, or qualified with the location like `path.id`.
+// body will be marshaled as json unless it is []byte or string.
+func NewRequest(openapi *oas.OpenAPI, baseURL string, t *Target, values map[string]interface{}, body interface{}) (*http.Request, error) {
+	path := t.Path
+	query := make([]string, 0)
+	header := http.Header{}
+	cookies := make([]*http.Cookie, 0)
+
+	used := map[string]bool{}
+
+	for _, p := range t.Parameters(&openapi.ComponentsObject) {
+		qualified := string(p.In) + "." + p.Name
+
+		value, ok := values[qualified]
+		if ok {
+			used[qualified] = true
+		} else if value, ok = values[p.Name]; ok {
+			used[p.Name] = true
+		}
+
+		if !ok {
+			if p.Required {
+				return nil, fmt.Errorf("%s: missing required parameter %s", t.Operation.OperationId, qualified)
+			}
+			continue
+		}
+
+		serialized := p.Serialize(value)
+
+		switch p.In {
+		case oas.PositionPath:
+			path = strings.Replace(path, "{"+p.Name+"}", serialized, -1)
+		case oas.PositionQuery:
+			query = append(query, serialized)
+		case oas.PositionHeader:
+			header.Set(p.Name, serialized)
+		case oas.PositionCookie:
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: serialized})
+		}
+	}
+
+	unused := make([]string, 0)
+	for name := range values {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return nil, fmt.Errorf("%s: unknown parameters %s", t.Operation.OperationId, strings.Join(unused, ", "))
+	}
+
+	rawURL := strings.TrimSuffix(baseURL, "/") + path
+	if len(query) > 0 {
+		rawURL += "?" + strings.Join(query, "&")
+	}
+
+	var reader io.Reader
+	contentType := ""
+
+	if body != nil {
+		data, err := marshalBody(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+		contentType = requestContentType(openapi, t.Operation)
+	}
+
+	req, err := http.NewRequest(strings.ToUpper(string(t.Method)), rawURL, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for k := range header {
+		req.Header[k] = header[k]
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return req, nil
+}
+
+func marshalBody(body interface{}) ([]byte, error) {
+	switch b := body.(type) {
+	case []byte:
+		return b, nil
+	case string:
+		return []byte(b), nil
+	}
+	return json.Marshal(body)
+}
+
+func requestContentType(openapi *oas.OpenAPI, op *oas.Operation) string {
+	rb := openapi.ResolveRequestBody(op.RequestBody)
+	if rb == nil || len(rb.Content) == 0 {
+		return "application/json"
+	}
+	if _, ok := rb.Content["application/json"]; ok {
+		return "application/json"
+	}
+	contentTypes := make([]string, 0, len(rb.Content))
+	for ct := range rb.Content {
+		contentTypes = append(contentTypes, ct)
+	}
+	sort.Strings(contentTypes)
+	return contentTypes[0]
+}
+
+// BaseURL returns the expanded url of the first effective server of the target
+func BaseURL(openapi *oas.OpenAPI, t *Target) (string, error) {
+	servers := openapi.EffectiveServers(t.Method, t.Path)
+	u, err := servers[0].Expand(nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// NewLinkRequest creates request to follow the link, values of parameters and request body evaluated by the exchange.
+func NewLinkRequest(openapi *oas.OpenAPI, baseURL string, link *oas.Link, x *oas.Exchange) (*http.Request, *Target, error) {
+	link = openapi.ResolveLink(link)
+	if link == nil {
+		return nil, nil, fmt.Errorf("link not found")
+	}
+
+	t, err := linkTarget(openapi, link)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := map[string]interface{}{}
+	for name, expr := range link.Parameters {
+		v, err := expr.Evaluate(x)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+		values[name] = v
+	}
+
+	var body interface{}
+	if link.RequestBody != "" {
+		body, err = evaluateBody(link.RequestBody, x)
+		if err != nil {
+			return nil, nil, fmt.Errorf("request body: %w", err)
+		}
+	}
+
+	if link.Server != nil {
+		u, err := link.Server.Expand(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		baseURL = u.String()
+	}
+
+	if baseURL == "" {
+		if baseURL, err = BaseURL(openapi, t); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	req, err := NewRequest(openapi, baseURL, t, values, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, t, nil
+}
+
+func linkTarget(openapi *oas.OpenAPI, link *oas.Link) (*Target, error) {
+	if link.OperationRef != "" {
+		method, path, op, err := openapi.Paths.ResolveOperationRef(link.OperationRef)
+		if err != nil {
+			return nil, err
+		}
+		return &Target{Method: method, Path: path, PathItem: openapi.Paths.Paths[path], Operation: op}, nil
+	}
+	return FindTarget(openapi, link.OperationId)
+}
+
+// evaluateBody keeps value of a single expression, and uses literal json as is.
+func evaluateBody(expr oas.RuntimeExpression, x *oas.Exchange) (interface{}, error) {
+	tpl, err := expr.Parse()
+	if err != nil {
+		return nil, err
+	}
+	v, err := tpl.Evaluate(x)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := v.(string); ok && !tpl.IsExpression() && json.Valid([]byte(s)) {
+		return json.RawMessage(s), nil
+	}
+	return v, nil
+}
+
+// pathParams extracts raw values of path parameters of the request by the path template
+func pathParams(t *Target, req *http.Request) map[string]string {
+	paths := oas.Paths{Paths: map[string]*oas.PathItem{t.Path: t.PathItem}}
+	_, params, _ := paths.MatchPath(trimToTemplate(req.URL, t.Path))
+	return params
+}
+
+// trimToTemplate trims prefix of server base path from request path
+func trimToTemplate(u *url.URL, tpl string) string {
+	path := u.EscapedPath()
+	tplParts := strings.Count(strings.Trim(tpl, "/"), "/") + 1
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) > tplParts {
+		parts = parts[len(parts)-tplParts:]
+	}
+	return "/" + strings.Join(parts, "/")
+}
@@ -0,0 +1,158 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-courier/oas"
+)
+
+// Runner runs user journeys, which call an operation, then follow links of the responses.
+type Runner struct {
+	OpenAPI *oas.OpenAPI
+	// Handler serves requests in process when set, otherwise requests sent by Client
+	Handler http.Handler
+	Client  *http.Client
+	// BaseURL overrides servers of the document, like url of httptest.Server
+	BaseURL string
+}
+
+// Step calls the operation of OperationId with Parameters and Body,
+// or follows the Link of the response of the previous step.
+type Step struct {
+	OperationId string
+	Parameters  map[string]interface{}
+	Body        interface{}
+
+	Link string
+
+	// ExpectStatus checks status code of the response when not zero
+	ExpectStatus int
+}
+
+type Result struct {
+	Target   *Target
+	Exchange *oas.Exchange
+}
+
+func (r *Result) StatusCode() int {
+	return r.Exchange.Response.StatusCode
+}
+
+// Run runs steps by order, and returns results of the steps executed.
+func (r *Runner) Run(ctx context.Context, steps ...Step) ([]*Result, error) {
+	results := make([]*Result, 0, len(steps))
+
+	var prev *Result
+
+	for i, step := range steps {
+		var result *Result
+		var err error
+
+		if step.Link != "" {
+			if prev == nil {
+				return results, fmt.Errorf("step %d: link %q should follow a step", i, step.Link)
+			}
+			result, err = r.Follow(ctx, prev, step.Link)
+		} else {
+			result, err = r.Call(ctx, step.OperationId, step.Parameters, step.Body)
+		}
+
+		if err != nil {
+			return results, fmt.Errorf("step %d: %w", i, err)
+		}
+
+		results = append(results, result)
+
+		if step.ExpectStatus != 0 && result.StatusCode() != step.ExpectStatus {
+			return results, fmt.Errorf("step %d: %s expect status %d, but got %d", i, result.Target.Operation.OperationId, step.ExpectStatus, result.StatusCode())
+		}
+
+		prev = result
+	}
+
+	return results, nil
+}
+
+func (r *Runner) Call(ctx context.Context, operationId string, values map[string]interface{}, body interface{}) (*Result, error) {
+	t, err := FindTarget(r.OpenAPI, operationId)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := r.BaseURL
+	if baseURL == "" {
+		if baseURL, err = BaseURL(r.OpenAPI, t); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := NewRequest(r.OpenAPI, baseURL, t, values, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.do(ctx, t, req)
+}
+
+// Follow follows the link of the response of the previous result.
+func (r *Runner) Follow(ctx context.Context, prev *Result, linkName string) (*Result, error) {
+	resp := r.OpenAPI.ResolveResponse(prev.Target.Operation.Responses.ResponseFor(prev.StatusCode()))
+	if resp == nil {
+		return nil, fmt.Errorf("%s: response of status %d not documented", prev.Target.Operation.OperationId, prev.StatusCode())
+	}
+
+	link, ok := resp.Links[linkName]
+	if !ok {
+		return nil, fmt.Errorf("%s: link %q of status %d not found", prev.Target.Operation.OperationId, linkName, prev.StatusCode())
+	}
+
+	req, t, err := NewLinkRequest(r.OpenAPI, r.BaseURL, link, prev.Exchange)
+	if err != nil {
+		return nil, fmt.Errorf("%s: link %q: %w", prev.Target.Operation.OperationId, linkName, err)
+	}
+
+	return r.do(ctx, t, req)
+}
+
+func (r *Runner) do(ctx context.Context, t *Target, req *http.Request) (*Result, error) {
+	req = req.WithContext(ctx)
+
+	// capture request body before sent, to evaluate runtime expressions of the request
+	sent, err := oas.CaptureExchange(req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *http.Response
+
+	if r.Handler != nil {
+		rw := httptest.NewRecorder()
+		r.Handler.ServeHTTP(rw, req)
+		resp = rw.Result()
+		resp.Request = req
+	} else {
+		c := r.Client
+		if c == nil {
+			c = http.DefaultClient
+		}
+		if resp, err = c.Do(req); err != nil {
+			return nil, err
+		}
+	}
+
+	defer resp.Body.Close()
+
+	x, err := oas.CaptureExchange(nil, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	x.Request = req
+	x.RequestBody = sent.RequestBody
+	x.PathParams = pathParams(t, req)
+
+	return &Result{Target: t, Exchange: x}, nil
+}
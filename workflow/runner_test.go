@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-courier/oas"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOpenAPI() *oas.OpenAPI {
+	openapi := oas.NewOpenAPI()
+	openapi.AddServer(oas.NewServer("https://api.example.com"))
+
+	{
+		op := oas.NewOperation("createUser")
+		rb := oas.NewRequestBody("", true)
+		rb.AddContent("application/json", oas.NewMediaTypeWithSchema(oas.ObjectOf(oas.Props{"name": oas.String()})))
+		op.SetRequestBody(rb)
+
+		resp := oas.NewResponse("created")
+		link := oas.NewLink("getUser")
+		link.AddParameter("userId", "$response.body#/id")
+		resp.AddLink("GetUser", link)
+
+		openapi.AddLink("ListPets", func() *oas.Link {
+			l := &oas.Link{}
+			l.OperationRef = "#/paths/~1users~1{userId}~1pets/get"
+			l.AddParameter("path.userId", "$request.path.userId")
+			l.AddParameter("tags", "$response.body#/tags")
+			l.AddParameter("X-Trace", "trace-{$response.header.X-Request-Id}")
+			return l
+		}())
+
+		op.AddResponse(http.StatusCreated, resp)
+		openapi.AddOperation(oas.POST, "/users", op)
+	}
+
+	{
+		op := oas.NewOperation("getUser")
+		op.AddParameter(oas.PathParameter("userId", oas.String()))
+
+		resp := oas.NewResponse("ok")
+		resp.AddLink("ListPets", openapi.RefLink("ListPets"))
+		op.AddResponse(http.StatusOK, resp)
+
+		openapi.AddOperation(oas.GET, "/users/{userId}", op)
+	}
+
+	{
+		op := oas.NewOperation("listPets")
+		op.AddParameter(oas.QueryParameter("tags", oas.ItemsOf(oas.String()), false))
+		op.AddParameter(oas.HeaderParameter("X-Trace", oas.String(), false))
+		op.AddResponse(http.StatusOK, oas.NewResponse("ok"))
+
+		openapi.AddOperation(oas.GET, "/users/{userId}/pets", op)
+		openapi.Paths.Paths["/users/{userId}/pets"].Parameters = []*oas.Parameter{oas.PathParameter("userId", oas.String())}
+	}
+
+	return openapi
+}
+
+func newHandler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /users", func(rw http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"name":"alex"}`, string(data))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte(`{"id":"u 1"}`))
+	})
+
+	mux.HandleFunc("GET /users/{userId}", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("X-Request-Id", "r1")
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{"id": r.PathValue("userId"), "tags": []string{"a", "b"}})
+	})
+
+	mux.HandleFunc("GET /users/{userId}/pets", func(rw http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+			"userId": r.PathValue("userId"),
+			"tags":   r.URL.Query()["tags"],
+			"trace":  r.Header.Get("X-Trace"),
+		})
+	})
+
+	return mux
+}
+
+func TestRunner(t *testing.T) {
+	steps := []Step{
+		{OperationId: "createUser", Body: map[string]string{"name": "alex"}, ExpectStatus: http.StatusCreated},
+		{Link: "GetUser", ExpectStatus: http.StatusOK},
+		{Link: "ListPets", ExpectStatus: http.StatusOK},
+	}
+
+	t.Run("with handler", func(t *testing.T) {
+		r := &Runner{OpenAPI: newOpenAPI(), Handler: newHandler(t)}
+
+		results, err := r.Run(context.Background(), steps...)
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+
+		assert.Equal(t, "https://api.example.com/users/u%201", results[1].Exchange.Request.URL.String())
+		assert.Equal(t, map[string]string{"userId": "u 1"}, results[1].Exchange.PathParams)
+		assert.JSONEq(t, `{"userId":"u 1","tags":["a","b"],"trace":"trace-r1"}`, string(results[2].Exchange.ResponseBody))
+	})
+
+	t.Run("with server", func(t *testing.T) {
+		s := httptest.NewServer(newHandler(t))
+		defer s.Close()
+
+		r := &Runner{OpenAPI: newOpenAPI(), Client: s.Client(), BaseURL: s.URL}
+
+		results, err := r.Run(context.Background(), steps...)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(results[2].Exchange.Request.URL.String(), s.URL+"/users/u%201/pets?tags=a&tags=b"))
+	})
+
+	t.Run("unexpected status", func(t *testing.T) {
+		r := &Runner{OpenAPI: newOpenAPI(), Handler: newHandler(t)}
+
+		_, err := r.Run(context.Background(), Step{OperationId: "createUser", Body: map[string]string{"name": "alex"}, ExpectStatus: http.StatusOK})
+		assert.EqualError(t, err, "step 0: createUser expect status 200, but got 201")
+	})
+
+	t.Run("link not found", func(t *testing.T) {
+		r := &Runner{OpenAPI: newOpenAPI(), Handler: newHandler(t)}
+
+		_, err := r.Run(context.Background(), steps[0], Step{Link: "Unknown"})
+		assert.EqualError(t, err, `step 1: createUser: link "Unknown" of status 201 not found`)
+	})
+}
+
+func TestNewRequest(t *testing.T) {
+	openapi := newOpenAPI()
+	target, _ := FindTarget(openapi, "getUser")
+
+	_, err := NewRequest(openapi, "", target, nil, nil)
+	assert.EqualError(t, err, "getUser: missing required parameter path.userId")
+
+	_, err = NewRequest(openapi, "", target, map[string]interface{}{"userId": 1, "limit": 10}, nil)
+	assert.EqualError(t, err, "getUser: unknown parameters limit")
+}
@@ -0,0 +1,204 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSONToYAML converts json to yaml, keeps order of object keys
+func JSONToYAML(data []byte) ([]byte, error) {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, err
+	}
+	blockStyle(node)
+
+	buf := bytes.NewBuffer(nil)
+	e := yaml.NewEncoder(buf)
+	e.SetIndent(2)
+	if err := e.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		blockStyle(n)
+	}
+}
+
+// YAMLToJSON converts yaml to json, keeps order of mapping keys
+func YAMLToJSON(data []byte) ([]byte, error) {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, err
+	}
+	w := &yamlJSONWriter{maxBytes: max(len(data)*maxYAMLExpandRatio, minYAMLMaxBytes)}
+	if err := w.write(node, 0); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalYAML decodes openapi from yaml or json
+func UnmarshalYAML(data []byte, v interface{}) error {
+	data, err := YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+const (
+	// maxYAMLAliases limits expansions of aliases, to avoid billion laughs
+	maxYAMLAliases = 10000
+	// maxYAMLExpandRatio limits size of converted json by times of the yaml size
+	maxYAMLExpandRatio = 100
+	minYAMLMaxBytes    = 1 << 20
+)
+
+// yamlJSONWriter writes yaml nodes as json, with aliases expanded in limits
+type yamlJSONWriter struct {
+	buf      bytes.Buffer
+	aliases  int
+	maxBytes int
+}
+
+func (w *yamlJSONWriter) write(node *yaml.Node, depth int) error {
+	if depth > 1000 {
+		return fmt.Errorf("yaml nested too deep at line %d", node.Line)
+	}
+	if w.buf.Len() > w.maxBytes {
+		return fmt.Errorf("yaml expanded too large, over %d bytes at line %d", w.maxBytes, node.Line)
+	}
+
+	buf := &w.buf
+
+	switch node.Kind {
+	case 0:
+		buf.WriteString("null")
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return w.write(node.Content[0], depth+1)
+	case yaml.AliasNode:
+		w.aliases++
+		if w.aliases > maxYAMLAliases {
+			return fmt.Errorf("yaml aliases expanded too many, over %d at line %d", maxYAMLAliases, node.Line)
+		}
+		return w.write(node.Alias, depth+1)
+	case yaml.MappingNode:
+		members, err := w.members(node, depth)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(members); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			k, err := json.Marshal(members[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(k)
+			buf.WriteByte(':')
+			if err := w.write(members[i+1], depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, n := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := w.write(n, depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return err
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		buf.Write(data)
+	}
+
+	return nil
+}
+
+// members returns keys and values of the mapping, with merge keys like <<: *base expanded.
+// Explicit keys take precedence over merged ones, and mappings merged earlier over later ones.
+func (w *yamlJSONWriter) members(node *yaml.Node, depth int) ([]*yaml.Node, error) {
+	explicit := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].ShortTag() != "!!merge" {
+			explicit[node.Content[i].Value] = true
+		}
+	}
+
+	members := make([]*yaml.Node, 0, len(node.Content))
+	added := map[string]bool{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.ShortTag() != "!!merge" {
+			members = append(members, key, value)
+			continue
+		}
+
+		sources := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			sources = value.Content
+		}
+		for _, source := range sources {
+			mapping, err := w.merged(source)
+			if err != nil {
+				return nil, err
+			}
+			merged, err := w.members(mapping, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			for j := 0; j+1 < len(merged); j += 2 {
+				if k := merged[j].Value; !explicit[k] && !added[k] {
+					added[k] = true
+					members = append(members, merged[j], merged[j+1])
+				}
+			}
+		}
+	}
+	return members, nil
+}
+
+// merged returns the mapping to merge, aliases resolved
+func (w *yamlJSONWriter) merged(node *yaml.Node) (*yaml.Node, error) {
+	for node.Kind == yaml.AliasNode {
+		w.aliases++
+		if w.aliases > maxYAMLAliases {
+			return nil, fmt.Errorf("yaml aliases expanded too many, over %d at line %d", maxYAMLAliases, node.Line)
+		}
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: only mappings could be merged", node.Line)
+	}
+	return node, nil
+}
@@ -0,0 +1,84 @@
+package oas
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAML(t *testing.T) {
+	jsonData := `{"openapi":"3.0.3","info":{"title":"x","version":"1"},"paths":{},"x-list":[1,1.5,"true",null,{"b":false,"a":"a: b"}]}`
+
+	yamlData, err := JSONToYAML([]byte(jsonData))
+	assert.NoError(t, err)
+	assert.Equal(t, `openapi: 3.0.3
+info:
+  title: x
+  version: "1"
+paths: {}
+x-list:
+  - 1
+  - 1.5
+  - "true"
+  - null
+  - b: false
+    a: 'a: b'
+`, string(yamlData))
+
+	back, err := YAMLToJSON(yamlData)
+	assert.NoError(t, err)
+	assert.Equal(t, jsonData, string(back))
+
+	openapi := &OpenAPI{}
+	assert.NoError(t, UnmarshalYAML(yamlData, openapi))
+	assert.Equal(t, "x", openapi.Title)
+
+	_, err = YAMLToJSON([]byte("a: [1"))
+	assert.Error(t, err)
+}
+
+func TestYAMLAliases(t *testing.T) {
+	t.Run("expanded", func(t *testing.T) {
+		data, err := YAMLToJSON([]byte("a: &a {b: 1}\nc: *a\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":{"b":1},"c":{"b":1}}`, string(data))
+	})
+
+	t.Run("merge keys", func(t *testing.T) {
+		data, err := YAMLToJSON([]byte("b: &b {type: string, format: date}\nfoo: {<<: *b, format: uuid}\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"b":{"type":"string","format":"date"},"foo":{"type":"string","format":"uuid"}}`, string(data))
+
+		data, err = YAMLToJSON([]byte("a: &a {x: 1, y: 1}\nb: &b {y: 2, z: 2, <<: *a}\nc: {<<: [*b, *a], z: 3}\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":{"x":1,"y":1},"b":{"y":2,"z":2,"x":1},"c":{"y":2,"x":1,"z":3}}`, string(data))
+
+		_, err = YAMLToJSON([]byte("a: &a [1]\nb: {<<: *a}\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("too many expansions", func(t *testing.T) {
+		b := strings.Builder{}
+		b.WriteString("l0: &l0 [x, x, x, x, x, x, x, x, x, x]\n")
+		for i := 1; i < 10; i++ {
+			b.WriteString(fmt.Sprintf("l%d: &l%d [*l%d, *l%d, *l%d, *l%d, *l%d, *l%d, *l%d, *l%d, *l%d, *l%d]\n", i, i, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1, i-1))
+		}
+		_, err := YAMLToJSON([]byte(b.String()))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "aliases expanded too many")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		b := strings.Builder{}
+		b.WriteString("a: &a " + strings.Repeat("x", 20000) + "\nb: [")
+		for i := 0; i < 5000; i++ {
+			b.WriteString("*a,")
+		}
+		b.WriteString("*a]\n")
+		_, err := YAMLToJSON([]byte(b.String()))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expanded too large")
+	})
+}